@@ -0,0 +1,117 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple/query"
+	"simple/store"
+)
+
+const (
+	// defaultLimit is the page size when the request doesn't give one
+	defaultLimit = 50
+	// maxLimit bounds the page size
+	maxLimit = 500
+	// defaultRange is the summary report's range when the request doesn't give one
+	defaultRange = "7d"
+)
+
+// validStatuses are the accepted values of the status parameter
+var validStatuses = map[string]bool{"TODO": true, "SNOOZED": true, "DONE": true}
+
+// threadQuery reads the filters and paging of GET /threads
+func threadQuery(params url.Values, now time.Time) (store.ThreadQuery, error) {
+	q := store.ThreadQuery{
+		Customer: params.Get("customer"),
+		Company:  params.Get("company"),
+		Search:   params.Get("q"),
+	}
+
+	if label := params.Get("label"); label != "" {
+		q.Labels = []string{label}
+	}
+
+	if value := params.Get("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.ToUpper(strings.TrimSpace(status))
+			if !validStatuses[status] {
+				return q, fmt.Errorf("invalid status %q (expected todo, snoozed or done)", status)
+			}
+			q.Statuses = append(q.Statuses, status)
+		}
+	}
+
+	times := []struct {
+		param string
+		dest  *time.Time
+	}{
+		{"created_after", &q.CreatedAfter},
+		{"created_before", &q.CreatedBefore},
+		{"updated_after", &q.UpdatedAfter},
+		{"updated_before", &q.UpdatedBefore},
+	}
+	for _, t := range times {
+		value := params.Get(t.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := query.ParseTime(value, now)
+		if err != nil {
+			return q, fmt.Errorf("%s: %w", t.param, err)
+		}
+		*t.dest = parsed
+	}
+
+	var err error
+	q.Limit, q.Offset, err = paging(params)
+	return q, err
+}
+
+// customerQuery reads the filters and paging of GET /customers
+func customerQuery(params url.Values) (store.CustomerQuery, error) {
+	q := store.CustomerQuery{
+		Search:  params.Get("q"),
+		Company: params.Get("company"),
+	}
+	var err error
+	q.Limit, q.Offset, err = paging(params)
+	return q, err
+}
+
+// summaryRange reads the range of the summary report, such as 7d or a date
+func summaryRange(params url.Values, now time.Time) (string, time.Time, error) {
+	value := params.Get("range")
+	if value == "" {
+		value = defaultRange
+	}
+	since, err := query.ParseTime(value, now)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("range: %w", err)
+	}
+	if since.After(now) {
+		return "", time.Time{}, fmt.Errorf("range: %s is in the future", value)
+	}
+	return value, since, nil
+}
+
+// paging reads the limit and offset parameters
+func paging(params url.Values) (limit, offset int, err error) {
+	limit = defaultLimit
+	if value := params.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+	}
+	if value := params.Get("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative number")
+		}
+	}
+	return limit, offset, nil
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"simple/store"
+)
+
+// Handler serves the read-only JSON API over the local store
+type Handler struct {
+	store *store.Stats
+	mux   *http.ServeMux
+	// now is the clock used for relative times
+	now    func() time.Time
+	errorf func(format string, args ...interface{})
+}
+
+// NewHandler creates a handler reading from the local store
+func NewHandler(stats *store.Stats, errorf func(format string, args ...interface{})) *Handler {
+	h := &Handler{
+		store:  stats,
+		mux:    http.NewServeMux(),
+		now:    time.Now,
+		errorf: errorf,
+	}
+	h.mux.HandleFunc("GET /threads", h.listThreads)
+	h.mux.HandleFunc("GET /threads/{id}", h.getThread)
+	h.mux.HandleFunc("GET /customers", h.listCustomers)
+	h.mux.HandleFunc("GET /reports/summary", h.summary)
+	return h
+}
+
+// ServeHTTP routes a request to its endpoint
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Thread is a thread in API responses
+type Thread struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Status    string     `json:"status"`
+	Labels    []string   `json:"labels"`
+	Customer  string     `json:"customer,omitempty"`
+	Company   string     `json:"company,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Customer is a customer in API responses
+type Customer struct {
+	Name    string `json:"name"`
+	Company string `json:"company,omitempty"`
+	Threads int    `json:"threads"`
+	Open    int    `json:"open"`
+}
+
+// Page holds the paging fields of list responses
+type Page struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// NextOffset is left out on the last page
+	NextOffset *int `json:"nextOffset,omitempty"`
+}
+
+// Summary is the response of the summary report
+type Summary struct {
+	Range    string         `json:"range"`
+	Since    time.Time      `json:"since"`
+	Until    time.Time      `json:"until"`
+	Statuses map[string]int `json:"statuses"`
+	Open     int            `json:"open"`
+	Created  int            `json:"created"`
+	Done     int            `json:"done"`
+}
+
+// listThreads serves GET /threads
+func (h *Handler) listThreads(w http.ResponseWriter, r *http.Request) {
+	q, err := threadQuery(r.URL.Query(), h.now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, total, err := h.store.ListThreads(q)
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+
+	threads := make([]Thread, 0, len(rows))
+	for i := range rows {
+		threads = append(threads, fromRow(&rows[i]))
+	}
+	h.writeJSON(w, r, struct {
+		Threads []Thread `json:"threads"`
+		Page
+	}{threads, newPage(total, q.Limit, q.Offset)})
+}
+
+// getThread serves GET /threads/{id}
+func (h *Handler) getThread(w http.ResponseWriter, r *http.Request) {
+	row, err := h.store.Thread(r.PathValue("id"))
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+	if row == nil {
+		writeError(w, http.StatusNotFound, "thread not found")
+		return
+	}
+	h.writeJSON(w, r, fromRow(row))
+}
+
+// listCustomers serves GET /customers
+func (h *Handler) listCustomers(w http.ResponseWriter, r *http.Request) {
+	q, err := customerQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, total, err := h.store.ListCustomers(q)
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+
+	customers := make([]Customer, 0, len(rows))
+	for _, row := range rows {
+		customers = append(customers, Customer(row))
+	}
+	h.writeJSON(w, r, struct {
+		Customers []Customer `json:"customers"`
+		Page
+	}{customers, newPage(total, q.Limit, q.Offset)})
+}
+
+// summary serves GET /reports/summary. The report runs up to the current time
+// to the minute, so the response and its ETag stay the same within a minute.
+func (h *Handler) summary(w http.ResponseWriter, r *http.Request) {
+	now := h.now().Truncate(time.Minute)
+	rangeValue, since, err := summaryRange(r.URL.Query(), now)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	statuses, err := h.store.CountByStatus()
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+	for status := range validStatuses {
+		if _, ok := statuses[status]; !ok {
+			statuses[status] = 0
+		}
+	}
+	bounds := []time.Time{since, now}
+	created, err := h.store.CreatedPerDay(bounds)
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+	done, err := h.store.DonePerDay(bounds)
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, Summary{
+		Range:    rangeValue,
+		Since:    since.UTC(),
+		Until:    now.UTC(),
+		Statuses: statuses,
+		Open:     statuses["TODO"] + statuses["SNOOZED"],
+		Created:  created[0],
+		Done:     done[0],
+	})
+}
+
+// fromRow converts a thread row from the store
+func fromRow(row *store.Threads) Thread {
+	thread := Thread{
+		ID:        row.ID,
+		Title:     row.Title,
+		Status:    row.Status,
+		Labels:    row.LabelNames(),
+		Customer:  row.Customer,
+		Company:   row.Company,
+		CreatedAt: utc(row.CreatedAt),
+		UpdatedAt: utc(row.UpdatedAt),
+	}
+	if thread.Labels == nil {
+		thread.Labels = []string{}
+	}
+	return thread
+}
+
+// utc converts an optional time to UTC
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// newPage builds the paging fields for a page starting at offset
+func newPage(total, limit, offset int) Page {
+	page := Page{Total: total, Limit: limit, Offset: offset}
+	if offset+limit < total {
+		next := offset + limit
+		page.NextOffset = &next
+	}
+	return page
+}
+
+// writeJSON writes a response with an ETag of its body, or 304 Not Modified
+// when the client already has it
+func (h *Handler) writeJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+	body = append(body, '\n')
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists the ETag
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// serverError reports a failure reading the store
+func (h *Handler) serverError(w http.ResponseWriter, err error) {
+	h.errorf("%v", err)
+	writeError(w, http.StatusInternalServerError, "failed to read the local store")
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"simple/config"
+	"simple/store"
+	"simple/types"
+)
+
+// newTestHandler creates a handler over a SQLite store holding a few threads
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	now := time.Now()
+	cfg := config.DBConfig{Source: filepath.Join(t.TempDir(), "threads.db")}
+	writer, err := store.OpenWriter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thread := func(id, title, status, customer, company string, age time.Duration, labels ...string) *types.Thread {
+		at := &types.DateTime{ISO8601: now.Add(-age).Format(time.RFC3339)}
+		th := &types.Thread{ID: id, Title: title, Status: status, CreatedAt: at, UpdatedAt: at}
+		if customer != "" {
+			th.Customer = &types.Customer{FullName: customer, Company: &types.Company{Name: company}}
+		}
+		for _, label := range labels {
+			th.Labels = append(th.Labels, types.Label{LabelType: types.LabelType{Name: label}})
+		}
+		return th
+	}
+	for _, th := range []*types.Thread{
+		thread("th_1", "Cannot log in", "TODO", "Ada Lovelace", "Analytical", time.Hour, "bug"),
+		thread("th_2", "Invoice is wrong", "TODO", "Ada Lovelace", "Analytical", 2*time.Hour, "billing", "bug_report"),
+		thread("th_3", "Export to CSV", "DONE", "Grace Hopper", "Navy", 3*time.Hour, "feature"),
+		thread("th_4", "Old login issue", "SNOOZED", "Grace Hopper", "Navy", 30*24*time.Hour, "bug"),
+	} {
+		if err := writer.SaveThread(th); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := store.OpenStats(cfg)
+	if err != nil || stats == nil {
+		t.Fatalf("OpenStats() = %v, %v", stats, err)
+	}
+	h := NewHandler(stats, func(format string, args ...interface{}) { t.Errorf(format, args...) })
+	h.now = func() time.Time { return now }
+	return h
+}
+
+// get serves a GET request and decodes the JSON response into v
+func get(t *testing.T, h http.Handler, target string, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if v != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("GET %s: %v", target, err)
+		}
+	}
+	return rec
+}
+
+func TestListThreads(t *testing.T) {
+	h := newTestHandler(t)
+
+	tests := []struct {
+		target     string
+		wantStatus int
+		wantIDs    []string
+		wantTotal  int
+	}{
+		{"/threads", http.StatusOK, []string{"th_1", "th_2", "th_3", "th_4"}, 4},
+		{"/threads?status=todo,snoozed", http.StatusOK, []string{"th_1", "th_2", "th_4"}, 3},
+		// bug_report must not match the bug label
+		{"/threads?label=bug", http.StatusOK, []string{"th_1", "th_4"}, 2},
+		{"/threads?customer=grace%20hopper", http.StatusOK, []string{"th_3", "th_4"}, 2},
+		{"/threads?q=LOG", http.StatusOK, []string{"th_1", "th_4"}, 2},
+		{"/threads?q=100%25", http.StatusOK, nil, 0},
+		{"/threads?created_after=7d", http.StatusOK, []string{"th_1", "th_2", "th_3"}, 3},
+		{"/threads?limit=2&offset=1", http.StatusOK, []string{"th_2", "th_3"}, 4},
+		{"/threads?status=open", http.StatusBadRequest, nil, 0},
+		{"/threads?limit=0", http.StatusBadRequest, nil, 0},
+		{"/threads?updated_after=yesterday", http.StatusBadRequest, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var resp struct {
+				Threads []Thread
+				Page
+			}
+			rec := get(t, h, tt.target, &resp)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var ids []string
+			for _, thread := range resp.Threads {
+				ids = append(ids, thread.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+			if resp.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", resp.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestGetThreadAndETag(t *testing.T) {
+	h := newTestHandler(t)
+
+	var thread Thread
+	rec := get(t, h, "/threads/th_2", &thread)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if thread.Customer != "Ada Lovelace" || len(thread.Labels) != 2 || thread.Labels[0] != "billing" {
+		t.Errorf("thread = %+v", thread)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+	req := httptest.NewRequest(http.MethodGet, "/threads/th_2", nil)
+	req.Header.Set("If-None-Match", etag)
+	cached := httptest.NewRecorder()
+	h.ServeHTTP(cached, req)
+	if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Errorf("conditional GET = %d with %d bytes, want 304 without a body", cached.Code, cached.Body.Len())
+	}
+
+	if rec := get(t, h, "/threads/th_missing", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing thread status = %d, want 404", rec.Code)
+	}
+}
+
+func TestListCustomers(t *testing.T) {
+	h := newTestHandler(t)
+
+	var resp struct {
+		Customers []Customer
+		Page
+	}
+	get(t, h, "/customers?limit=1", &resp)
+	if resp.Total != 2 || len(resp.Customers) != 1 || resp.NextOffset == nil || *resp.NextOffset != 1 {
+		t.Fatalf("customers page = %+v", resp)
+	}
+	want := Customer{Name: "Ada Lovelace", Company: "Analytical", Threads: 2, Open: 2}
+	if resp.Customers[0] != want {
+		t.Errorf("first customer = %+v, want %+v", resp.Customers[0], want)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	h := newTestHandler(t)
+
+	var summary Summary
+	rec := get(t, h, "/reports/summary?range=7d", &summary)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (%s)", rec.Code, rec.Body.String())
+	}
+	if summary.Open != 3 || summary.Created != 3 || summary.Done != 1 || summary.Statuses["TODO"] != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	if rec := get(t, h, "/reports/summary?range=soon", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad range status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"simple/types"
+
+	"github.com/machinebox/graphql"
+)
+
+// GetThreadAttachments retrieves the attachments of every timeline entry of a thread
+func (c *PlainClient) GetThreadAttachments(ctx context.Context, threadId string) ([]*types.Attachment, error) {
+	var attachments []*types.Attachment
+	err := c.EachTimelineEntry(ctx, threadId, func(entry *types.TimelineEntry) error {
+		attachments = append(attachments, entry.Attachments()...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// GetAttachmentDownloadURL creates a short-lived download URL for an attachment
+func (c *PlainClient) GetAttachmentDownloadURL(ctx context.Context, attachmentId string) (string, error) {
+	req := graphql.NewRequest(`
+		mutation createAttachmentDownloadUrl($input: CreateAttachmentDownloadUrlInput!) {
+			createAttachmentDownloadUrl(input: $input) {
+				attachmentDownloadUrl {
+					downloadUrl
+				}
+				error {
+					message
+					type
+				}
+			}
+		}
+	`)
+
+	req.Var("input", map[string]interface{}{
+		"attachmentId": attachmentId,
+	})
+	c.setHeaders(req)
+
+	var resp struct {
+		CreateAttachmentDownloadUrl struct {
+			AttachmentDownloadUrl *struct {
+				DownloadUrl string `json:"downloadUrl"`
+			} `json:"attachmentDownloadUrl"`
+			Error *types.APIError `json:"error"`
+		} `json:"createAttachmentDownloadUrl"`
+	}
+
+	if err := c.run(ctx, "createAttachmentDownloadUrl", req, &resp); err != nil {
+		return "", fmt.Errorf("failed to create attachment download url: %w", err)
+	}
+
+	if resp.CreateAttachmentDownloadUrl.Error != nil {
+		return "", resp.CreateAttachmentDownloadUrl.Error
+	}
+
+	if resp.CreateAttachmentDownloadUrl.AttachmentDownloadUrl == nil {
+		return "", fmt.Errorf("no download url returned for attachment %s", attachmentId)
+	}
+
+	return resp.CreateAttachmentDownloadUrl.AttachmentDownloadUrl.DownloadUrl, nil
+}
+
+// DownloadAttachment downloads an attachment to w. progress, if set, is called as
+// data arrives with the bytes written so far and the total size (-1 if unknown).
+func (c *PlainClient) DownloadAttachment(ctx context.Context, attachmentId string, w io.Writer, progress func(written, total int64)) (int64, error) {
+	url, err := c.GetAttachmentDownloadURL(ctx, attachmentId)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download attachment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to download attachment: %s", resp.Status)
+	}
+
+	body := io.Reader(resp.Body)
+	if progress != nil {
+		body = &progressReader{reader: resp.Body, total: resp.ContentLength, progress: progress}
+	}
+
+	written, err := io.Copy(w, body)
+	if err != nil {
+		return written, fmt.Errorf("failed to download attachment: %w", err)
+	}
+
+	return written, nil
+}
+
+// progressReader reports the number of bytes read through it
+type progressReader struct {
+	reader   io.Reader
+	read     int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.reader.Read(p)
+	pr.read += int64(n)
+	pr.progress(pr.read, pr.total)
+	return n, err
+}
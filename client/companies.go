@@ -0,0 +1,343 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"simple/types"
+
+	"github.com/machinebox/graphql"
+)
+
+// GetCompanies retrieves a list of companies with pagination
+func (c *PlainClient) GetCompanies(ctx context.Context, limit int, cursor string) (*types.CompanyConnection, error) {
+	req := graphql.NewRequest(`
+		query companies($first: Int!, $after: String) {
+			companies(first: $first, after: $after) {
+				edges {
+					node {
+						id
+						name
+						domain: domainName
+						tier {
+							id
+							name
+							color
+						}
+						createdAt {
+							iso8601
+						}
+						updatedAt {
+							iso8601
+						}
+					}
+					cursor
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`)
+
+	req.Var("first", limit)
+	if cursor != "" {
+		req.Var("after", cursor)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		Companies *types.CompanyConnection `json:"companies"`
+	}
+	if err := c.run(ctx, "companies", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get companies: %w", err)
+	}
+
+	return resp.Companies, nil
+}
+
+// GetCompanyById retrieves a single company by ID
+func (c *PlainClient) GetCompanyById(ctx context.Context, companyId string) (*types.Company, error) {
+	req := graphql.NewRequest(`
+		query company($companyId: ID!) {
+			company(companyId: $companyId) {
+				id
+				name
+				domain: domainName
+				tier {
+					id
+					name
+					color
+				}
+				createdAt {
+					iso8601
+				}
+				updatedAt {
+					iso8601
+				}
+			}
+		}
+	`)
+
+	req.Var("companyId", companyId)
+	c.setHeaders(req)
+
+	var resp struct {
+		Company *types.Company `json:"company"`
+	}
+	if err := c.run(ctx, "company", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return resp.Company, nil
+}
+
+// GetThreadsByCompany retrieves threads raised by customers of a company, filtered by status
+func (c *PlainClient) GetThreadsByCompany(ctx context.Context, companyId string, statuses []string, limit int, cursor string) (*types.ThreadConnection, error) {
+	req := graphql.NewRequest(`
+		query threadsByCompany($first: Int!, $after: String, $companyIdentifiers: [CompanyIdentifierInput!], $statuses: [ThreadStatus!]) {
+			threads(first: $first, after: $after, filters: { companyIdentifiers: $companyIdentifiers, statuses: $statuses }) {
+				edges {
+					node {
+						id
+						title
+						status
+						priority
+						createdAt {
+							iso8601
+						}
+						updatedAt {
+							iso8601
+						}
+						customer {
+							id
+							fullName
+							email {
+								email
+							}
+							company {
+								id
+								name
+							}
+						}
+						assignedTo {
+							... on User {
+								id
+								fullName
+								email
+							}
+						}
+					}
+					cursor
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				totalCount
+			}
+		}
+	`)
+
+	req.Var("first", limit)
+	req.Var("companyIdentifiers", []map[string]interface{}{
+		{"companyId": companyId},
+	})
+	req.Var("statuses", statuses)
+	if cursor != "" {
+		req.Var("after", cursor)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		Threads *types.ThreadConnection `json:"threads"`
+	}
+	if err := c.run(ctx, "threadsByCompany", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get threads by company: %w", err)
+	}
+
+	return resp.Threads, nil
+}
+
+// GetCustomersByCompany retrieves the customers belonging to a company with pagination
+func (c *PlainClient) GetCustomersByCompany(ctx context.Context, companyId string, limit int, cursor string) (*types.CustomerConnection, error) {
+	req := graphql.NewRequest(`
+		query customersByCompany($first: Int!, $after: String, $companyIdentifiers: [CompanyIdentifierInput!]) {
+			customers(first: $first, after: $after, filters: { companyIdentifiers: $companyIdentifiers }) {
+				edges {
+					node {
+						id
+						fullName
+						email {
+							email
+						}
+						status
+						company {
+							id
+							name
+						}
+						createdAt {
+							iso8601
+						}
+					}
+					cursor
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`)
+
+	req.Var("first", limit)
+	req.Var("companyIdentifiers", []map[string]interface{}{
+		{"companyId": companyId},
+	})
+	if cursor != "" {
+		req.Var("after", cursor)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		Customers *types.CustomerConnection `json:"customers"`
+	}
+	if err := c.run(ctx, "customersByCompany", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get customers by company: %w", err)
+	}
+
+	return resp.Customers, nil
+}
+
+// GetTenants retrieves a list of tenants with pagination
+func (c *PlainClient) GetTenants(ctx context.Context, limit int, cursor string) (*types.TenantConnection, error) {
+	req := graphql.NewRequest(`
+		query tenants($first: Int!, $after: String) {
+			tenants(first: $first, after: $after) {
+				edges {
+					node {
+						id
+						name
+						externalId
+						tier {
+							id
+							name
+							color
+						}
+						createdAt {
+							iso8601
+						}
+						updatedAt {
+							iso8601
+						}
+					}
+					cursor
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`)
+
+	req.Var("first", limit)
+	if cursor != "" {
+		req.Var("after", cursor)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		Tenants *types.TenantConnection `json:"tenants"`
+	}
+	if err := c.run(ctx, "tenants", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get tenants: %w", err)
+	}
+
+	return resp.Tenants, nil
+}
+
+// GetTenantById retrieves a single tenant by ID
+func (c *PlainClient) GetTenantById(ctx context.Context, tenantId string) (*types.Tenant, error) {
+	req := graphql.NewRequest(`
+		query tenant($tenantId: ID!) {
+			tenant(tenantId: $tenantId) {
+				id
+				name
+				externalId
+				tier {
+					id
+					name
+					color
+				}
+				createdAt {
+					iso8601
+				}
+				updatedAt {
+					iso8601
+				}
+			}
+		}
+	`)
+
+	req.Var("tenantId", tenantId)
+	c.setHeaders(req)
+
+	var resp struct {
+		Tenant *types.Tenant `json:"tenant"`
+	}
+	if err := c.run(ctx, "tenant", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	return resp.Tenant, nil
+}
+
+// FindCompany resolves a company from its ID or its (case-insensitive) name.
+// Names are matched by walking the company list, so an ambiguous name is reported as an error.
+func (c *PlainClient) FindCompany(ctx context.Context, idOrName string) (*types.Company, error) {
+	if strings.HasPrefix(idOrName, "co_") {
+		company, err := c.GetCompanyById(ctx, idOrName)
+		if err != nil {
+			return nil, err
+		}
+		if company == nil {
+			return nil, fmt.Errorf("company %q not found", idOrName)
+		}
+		return company, nil
+	}
+
+	var matches []*types.Company
+	cursor := ""
+	for {
+		companies, err := c.GetCompanies(ctx, 100, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if companies == nil {
+			break
+		}
+
+		for _, edge := range companies.Edges {
+			if edge.Node != nil && strings.EqualFold(edge.Node.Name, idOrName) {
+				matches = append(matches, edge.Node)
+			}
+		}
+
+		if companies.PageInfo == nil || !companies.PageInfo.HasNextPage {
+			break
+		}
+		cursor = companies.PageInfo.EndCursor
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("company %q not found", idOrName)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("company name %q is ambiguous (%d matches), use the company ID instead", idOrName, len(matches))
+	}
+}
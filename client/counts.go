@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/machinebox/graphql"
+)
+
+// countPageSize is the page size used when threads have to be counted by paging
+const countPageSize = 100
+
+// maxCountPages bounds how many pages are scanned when Plain returns no totalCount
+const maxCountPages = 20
+
+// CountThreads returns the number of threads matching a filter without fetching
+// them. exact is false when Plain gave no total and the count stopped at the
+// paging limit.
+func (c *PlainClient) CountThreads(ctx context.Context, filter ThreadFilter) (count int, exact bool, err error) {
+	req := graphql.NewRequest(`
+		query countThreads($filters: ThreadsFilter) {
+			threads(first: 1, filters: $filters) {
+				totalCount
+			}
+		}
+	`)
+
+	req.Var("filters", filter.input())
+	c.setHeaders(req)
+
+	var resp struct {
+		Threads *struct {
+			TotalCount *int `json:"totalCount"`
+		} `json:"threads"`
+	}
+	if err := c.run(ctx, "countThreads", req, &resp); err != nil {
+		return 0, false, fmt.Errorf("failed to count threads: %w", err)
+	}
+
+	if resp.Threads != nil && resp.Threads.TotalCount != nil {
+		return *resp.Threads.TotalCount, true, nil
+	}
+
+	return c.countThreadsByPaging(ctx, filter)
+}
+
+// countThreadsByPaging counts threads by paging through their IDs, up to maxCountPages pages
+func (c *PlainClient) countThreadsByPaging(ctx context.Context, filter ThreadFilter) (int, bool, error) {
+	count := 0
+	cursor := ""
+
+	for page := 0; page < maxCountPages; page++ {
+		req := graphql.NewRequest(`
+			query countThreadsByPaging($first: Int!, $after: String, $filters: ThreadsFilter) {
+				threads(first: $first, after: $after, filters: $filters) {
+					edges {
+						cursor
+					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		`)
+
+		req.Var("first", countPageSize)
+		req.Var("filters", filter.input())
+		if cursor != "" {
+			req.Var("after", cursor)
+		}
+		c.setHeaders(req)
+
+		var resp struct {
+			Threads *struct {
+				Edges []struct {
+					Cursor string `json:"cursor"`
+				} `json:"edges"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"threads"`
+		}
+		if err := c.run(ctx, "countThreadsByPaging", req, &resp); err != nil {
+			return 0, false, fmt.Errorf("failed to count threads: %w", err)
+		}
+		if resp.Threads == nil {
+			return count, true, nil
+		}
+
+		count += len(resp.Threads.Edges)
+		if !resp.Threads.PageInfo.HasNextPage {
+			return count, true, nil
+		}
+		cursor = resp.Threads.PageInfo.EndCursor
+	}
+
+	return count, false, nil
+}
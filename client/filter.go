@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"simple/types"
+
+	"github.com/machinebox/graphql"
+)
+
+// ThreadFilter holds the thread filters that can be sent to Plain's threads query.
+// Empty fields are left out of the request.
+type ThreadFilter struct {
+	Statuses     []string
+	Priorities   []int
+	LabelTypeIDs []string
+	CustomerIDs  []string
+	CompanyIDs   []string
+	// Date bounds are ISO8601 timestamps
+	CreatedAfter  string
+	CreatedBefore string
+	UpdatedAfter  string
+	UpdatedBefore string
+}
+
+// input builds the ThreadsFilter input object for the threads query
+func (f ThreadFilter) input() map[string]interface{} {
+	filters := map[string]interface{}{}
+	if len(f.Statuses) > 0 {
+		filters["statuses"] = f.Statuses
+	}
+	if len(f.Priorities) > 0 {
+		filters["priorities"] = f.Priorities
+	}
+	if len(f.LabelTypeIDs) > 0 {
+		filters["labelTypeIds"] = f.LabelTypeIDs
+	}
+	if len(f.CustomerIDs) > 0 {
+		filters["customerIds"] = f.CustomerIDs
+	}
+	if len(f.CompanyIDs) > 0 {
+		var identifiers []map[string]interface{}
+		for _, id := range f.CompanyIDs {
+			identifiers = append(identifiers, map[string]interface{}{"companyId": id})
+		}
+		filters["companyIdentifiers"] = identifiers
+	}
+	if dates := dateRange(f.CreatedAfter, f.CreatedBefore); dates != nil {
+		filters["createdAt"] = dates
+	}
+	if dates := dateRange(f.UpdatedAfter, f.UpdatedBefore); dates != nil {
+		filters["updatedAt"] = dates
+	}
+	return filters
+}
+
+// dateRange builds a datetime filter, or nil when neither bound is set
+func dateRange(after, before string) map[string]interface{} {
+	if after == "" && before == "" {
+		return nil
+	}
+	dates := map[string]interface{}{}
+	if after != "" {
+		dates["after"] = after
+	}
+	if before != "" {
+		dates["before"] = before
+	}
+	return dates
+}
+
+// GetThreadsByFilter retrieves threads matching a filter with pagination
+func (c *PlainClient) GetThreadsByFilter(ctx context.Context, filter ThreadFilter, limit int, cursor string) (*types.ThreadConnection, error) {
+	req := graphql.NewRequest(`
+		query threadsByFilter($first: Int!, $after: String, $filters: ThreadsFilter) {
+			threads(first: $first, after: $after, filters: $filters) {
+				edges {
+					node {
+						id
+						title
+						status
+						priority
+						createdAt {
+							iso8601
+						}
+						updatedAt {
+							iso8601
+						}
+						customer {
+							id
+							fullName
+							email {
+								email
+							}
+							company {
+								id
+								name
+							}
+						}
+						assignedTo {
+							... on User {
+								id
+								fullName
+								email
+							}
+						}
+						labels {
+							id
+							labelType {
+								id
+								name
+							}
+						}
+					}
+					cursor
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				totalCount
+			}
+		}
+	`)
+
+	req.Var("first", limit)
+	req.Var("filters", filter.input())
+	if cursor != "" {
+		req.Var("after", cursor)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		Threads *types.ThreadConnection `json:"threads"`
+	}
+	if err := c.run(ctx, "threadsByFilter", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get threads by filter: %w", err)
+	}
+
+	return resp.Threads, nil
+}
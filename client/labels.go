@@ -0,0 +1,281 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"simple/config"
+	"simple/types"
+
+	"github.com/machinebox/graphql"
+)
+
+// labelCacheTTL is how long cached label types are trusted before refetching
+const labelCacheTTL = time.Hour
+
+// ArchiveLabel archives a label type so it can no longer be applied to threads
+func (c *PlainClient) ArchiveLabel(ctx context.Context, labelTypeId string) (*types.LabelType, error) {
+	req := graphql.NewRequest(`
+		mutation archiveLabelType($input: ArchiveLabelTypeInput!) {
+			archiveLabelType(input: $input) {
+				labelType {
+					id
+					name
+					isArchived
+				}
+				error {
+					message
+					type
+				}
+			}
+		}
+	`)
+
+	req.Var("input", map[string]interface{}{
+		"labelTypeId": labelTypeId,
+	})
+	c.setHeaders(req)
+
+	var resp struct {
+		ArchiveLabelType struct {
+			LabelType *types.LabelType `json:"labelType"`
+			Error     *types.APIError  `json:"error"`
+		} `json:"archiveLabelType"`
+	}
+
+	if err := c.run(ctx, "archiveLabelType", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to archive label: %w", err)
+	}
+
+	if resp.ArchiveLabelType.Error != nil {
+		return nil, resp.ArchiveLabelType.Error
+	}
+
+	return resp.ArchiveLabelType.LabelType, nil
+}
+
+// AddLabels applies label types to a thread
+func (c *PlainClient) AddLabels(ctx context.Context, threadId string, labelTypeIds []string) ([]types.Label, error) {
+	req := graphql.NewRequest(`
+		mutation addLabels($input: AddLabelsInput!) {
+			addLabels(input: $input) {
+				labels {
+					id
+					labelType {
+						id
+						name
+					}
+				}
+				error {
+					message
+					type
+				}
+			}
+		}
+	`)
+
+	req.Var("input", map[string]interface{}{
+		"threadId":     threadId,
+		"labelTypeIds": labelTypeIds,
+	})
+	c.setHeaders(req)
+
+	var resp struct {
+		AddLabels struct {
+			Labels []types.Label   `json:"labels"`
+			Error  *types.APIError `json:"error"`
+		} `json:"addLabels"`
+	}
+
+	if err := c.run(ctx, "addLabels", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to add labels: %w", err)
+	}
+
+	if resp.AddLabels.Error != nil {
+		return nil, resp.AddLabels.Error
+	}
+
+	return resp.AddLabels.Labels, nil
+}
+
+// RemoveLabels removes labels from a thread. It takes the IDs of the labels applied
+// to the thread (types.Label.ID), not the IDs of their label types.
+func (c *PlainClient) RemoveLabels(ctx context.Context, labelIds []string) error {
+	req := graphql.NewRequest(`
+		mutation removeLabels($input: RemoveLabelsInput!) {
+			removeLabels(input: $input) {
+				error {
+					message
+					type
+				}
+			}
+		}
+	`)
+
+	req.Var("input", map[string]interface{}{
+		"labelIds": labelIds,
+	})
+	c.setHeaders(req)
+
+	var resp struct {
+		RemoveLabels struct {
+			Error *types.APIError `json:"error"`
+		} `json:"removeLabels"`
+	}
+
+	if err := c.run(ctx, "removeLabels", req, &resp); err != nil {
+		return fmt.Errorf("failed to remove labels: %w", err)
+	}
+
+	if resp.RemoveLabels.Error != nil {
+		return resp.RemoveLabels.Error
+	}
+
+	return nil
+}
+
+// LabelCache keeps a copy of the workspace label types on disk so label names
+// can be resolved to IDs without listing every label on each command.
+type LabelCache struct {
+	source DataSource
+	path   string
+	labels []*types.LabelType
+}
+
+// labelCacheFile is the on-disk format of the label cache
+type labelCacheFile struct {
+	FetchedAt time.Time          `json:"fetchedAt"`
+	Labels    []*types.LabelType `json:"labels"`
+}
+
+// NewLabelCache creates a label cache stored in the local cache directory.
+// Labels from other sources than the API are only kept in memory.
+func NewLabelCache(source DataSource) *LabelCache {
+	if _, ok := source.(*PlainClient); !ok {
+		return &LabelCache{source: source}
+	}
+
+	path, err := config.GetCachePath("labels.json")
+	if err != nil {
+		// Without a cache directory every lookup simply goes to the API
+		path = ""
+	}
+
+	return &LabelCache{
+		source: source,
+		path:   path,
+	}
+}
+
+// Labels returns the cached label types, fetching them from the API when the cache is missing or stale
+func (lc *LabelCache) Labels(ctx context.Context) ([]*types.LabelType, error) {
+	if lc.labels != nil {
+		return lc.labels, nil
+	}
+
+	if lc.path != "" {
+		if data, err := os.ReadFile(lc.path); err == nil {
+			var cached labelCacheFile
+			if err := json.Unmarshal(data, &cached); err == nil && time.Since(cached.FetchedAt) < labelCacheTTL {
+				lc.labels = cached.Labels
+				return lc.labels, nil
+			}
+		}
+	}
+
+	return lc.Refresh(ctx)
+}
+
+// Refresh refetches the label types and rewrites the cache
+func (lc *LabelCache) Refresh(ctx context.Context) ([]*types.LabelType, error) {
+	labels, err := lc.source.GetLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lc.labels = labels
+
+	if lc.path != "" {
+		data, err := json.Marshal(labelCacheFile{FetchedAt: time.Now(), Labels: labels})
+		if err == nil {
+			// A failed cache write only costs a refetch next time
+			_ = os.WriteFile(lc.path, data, 0644)
+		}
+	}
+
+	return labels, nil
+}
+
+// Invalidate drops the cached label types, forcing the next lookup to hit the API
+func (lc *LabelCache) Invalidate() {
+	lc.labels = nil
+	if lc.path != "" {
+		_ = os.Remove(lc.path)
+	}
+}
+
+// Resolve maps label names (or label type IDs) to label types. Names are matched
+// case-insensitively; if a name is not found the cache is refreshed once before failing.
+func (lc *LabelCache) Resolve(ctx context.Context, names []string) ([]*types.LabelType, error) {
+	labels, err := lc.Labels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]*types.LabelType, 0, len(names))
+	refreshed := false
+	for _, name := range names {
+		label, err := findLabel(labels, name)
+		if err != nil && !refreshed {
+			// The label may have been created since the cache was written
+			if labels, err = lc.Refresh(ctx); err != nil {
+				return nil, err
+			}
+			refreshed = true
+			label, err = findLabel(labels, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, label)
+	}
+
+	return resolved, nil
+}
+
+// findLabel finds a single label type by ID or name. An exact name match wins over
+// case-insensitive matches, and more than one match of either kind is ambiguous.
+func findLabel(labels []*types.LabelType, name string) (*types.LabelType, error) {
+	var exact, folded []*types.LabelType
+	for _, label := range labels {
+		if label.ID == name {
+			return label, nil
+		}
+		if label.Name == name {
+			exact = append(exact, label)
+		} else if strings.EqualFold(label.Name, name) {
+			folded = append(folded, label)
+		}
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = folded
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("label %q not found", name)
+	case 1:
+		return matches[0], nil
+	default:
+		var candidates []string
+		for _, label := range matches {
+			candidates = append(candidates, fmt.Sprintf("%q (%s)", label.Name, label.ID))
+		}
+		return nil, fmt.Errorf("label %q is ambiguous, it matches %s; use the label ID instead", name, strings.Join(candidates, ", "))
+	}
+}
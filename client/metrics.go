@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/machinebox/graphql"
+)
+
+// RequestObserver is told about every request made to the Plain API, with the
+// GraphQL operation name, how long it took and the error it failed with
+type RequestObserver func(operation string, duration time.Duration, err error)
+
+// SetObserver sets the function told about every API request
+func (c *PlainClient) SetObserver(observer RequestObserver) {
+	c.observer = observer
+}
+
+// run sends a request to the Plain API and reports it to the observer
+func (c *PlainClient) run(ctx context.Context, operation string, req *graphql.Request, resp interface{}) error {
+	start := time.Now()
+	err := c.client.Run(ctx, req, resp)
+	if c.observer != nil {
+		c.observer(operation, time.Since(start), err)
+	}
+	return err
+}
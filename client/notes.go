@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"simple/types"
+
+	"github.com/machinebox/graphql"
+)
+
+// CreateNote adds an internal note to a thread. Notes are only visible to the support team.
+func (c *PlainClient) CreateNote(ctx context.Context, customerId, threadId, markdown string) (*types.Note, error) {
+	req := graphql.NewRequest(`
+		mutation createNote($input: CreateNoteInput!) {
+			createNote(input: $input) {
+				note {
+					id
+					text
+					markdown
+					createdAt {
+						iso8601
+					}
+				}
+				error {
+					message
+					type
+				}
+			}
+		}
+	`)
+
+	req.Var("input", map[string]interface{}{
+		"customerId": customerId,
+		"threadId":   threadId,
+		"markdown":   markdown,
+		"text":       markdown,
+	})
+	c.setHeaders(req)
+
+	var resp struct {
+		CreateNote struct {
+			Note  *types.Note     `json:"note"`
+			Error *types.APIError `json:"error"`
+		} `json:"createNote"`
+	}
+
+	if err := c.run(ctx, "createNote", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create note: %w", err)
+	}
+
+	if resp.CreateNote.Error != nil {
+		return nil, resp.CreateNote.Error
+	}
+
+	return resp.CreateNote.Note, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"simple/types"
+
+	"github.com/machinebox/graphql"
+)
+
+// GetMyUser returns the user the API key belongs to
+func (c *PlainClient) GetMyUser(ctx context.Context) (*types.User, error) {
+	req := graphql.NewRequest(`
+		query myUser {
+			myUser {
+				id
+				fullName
+				email
+			}
+		}
+	`)
+	c.setHeaders(req)
+
+	var resp struct {
+		MyUser *types.User `json:"myUser"`
+	}
+	if err := c.run(ctx, "myUser", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	if resp.MyUser == nil {
+		return nil, fmt.Errorf("failed to get current user: the API key has no user")
+	}
+
+	return resp.MyUser, nil
+}
+
+// GetUpdatedThreads returns a page of the threads updated after since, an
+// ISO8601 timestamp, with the time of the customer's latest message
+func (c *PlainClient) GetUpdatedThreads(ctx context.Context, since string, limit int, cursor string) (*types.ThreadConnection, error) {
+	req := graphql.NewRequest(`
+		query updatedThreads($first: Int!, $after: String, $filters: ThreadsFilter) {
+			threads(first: $first, after: $after, filters: $filters) {
+				edges {
+					node {
+						id
+						title
+						status
+						priority
+						createdAt {
+							iso8601
+						}
+						updatedAt {
+							iso8601
+						}
+						lastInboundMessageInfo {
+							timestamp {
+								iso8601
+							}
+						}
+						customer {
+							id
+							fullName
+							email {
+								email
+							}
+							company {
+								id
+								name
+							}
+						}
+						assignedTo {
+							... on User {
+								id
+								fullName
+								email
+							}
+						}
+					}
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`)
+
+	req.Var("first", limit)
+	if cursor != "" {
+		req.Var("after", cursor)
+	}
+	req.Var("filters", ThreadFilter{UpdatedAfter: since}.input())
+	c.setHeaders(req)
+
+	var resp struct {
+		Threads *types.ThreadConnection `json:"threads"`
+	}
+	if err := c.run(ctx, "updatedThreads", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get updated threads: %w", err)
+	}
+
+	return resp.Threads, nil
+}
@@ -12,8 +12,9 @@ import (
 
 // PlainClient wraps the GraphQL client for Plain API
 type PlainClient struct {
-	client *graphql.Client
-	config *config.Config
+	client   *graphql.Client
+	config   *config.Config
+	observer RequestObserver
 }
 
 // NewPlainClient creates a new Plain API client
@@ -60,7 +61,7 @@ func (c *PlainClient) GetCustomerByEmail(ctx context.Context, email string) (*ty
 	var resp struct {
 		CustomerByEmail *types.Customer `json:"customerByEmail"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "customerByEmail", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to get customer by email: %w", err)
 	}
 
@@ -108,19 +109,128 @@ func (c *PlainClient) GetCustomers(ctx context.Context, limit int, cursor string
 	var resp struct {
 		Customers *types.CustomerConnection `json:"threads"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "customers", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to get threads: %w", err)
 	}
 
 	return resp.Customers, nil
 }
 
+// GetCustomerById retrieves a single customer by ID
+func (c *PlainClient) GetCustomerById(ctx context.Context, customerId string) (*types.Customer, error) {
+	req := graphql.NewRequest(`
+		query customer($customerId: ID!) {
+			customer(customerId: $customerId) {
+				id
+				fullName
+				email {
+					email
+				}
+				status
+				createdAt {
+					iso8601
+				}
+				updatedAt {
+					iso8601
+				}
+				company {
+					id
+					name
+					domain: domainName
+					tier {
+						id
+						name
+						color
+					}
+				}
+			}
+		}
+	`)
+
+	req.Var("customerId", customerId)
+	c.setHeaders(req)
+
+	var resp struct {
+		Customer *types.Customer `json:"customer"`
+	}
+	if err := c.run(ctx, "customer", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return resp.Customer, nil
+}
+
+// GetThreadsByCustomer retrieves threads belonging to a customer, filtered by status
+func (c *PlainClient) GetThreadsByCustomer(ctx context.Context, customerId string, statuses []string, limit int, cursor string) (*types.ThreadConnection, error) {
+	req := graphql.NewRequest(`
+		query threadsByCustomer($first: Int!, $after: String, $customerIds: [ID!], $statuses: [ThreadStatus!]) {
+			threads(first: $first, after: $after, filters: { customerIds: $customerIds, statuses: $statuses }) {
+				edges {
+					node {
+						id
+						title
+						status
+						priority
+						createdAt {
+							iso8601
+						}
+						updatedAt {
+							iso8601
+						}
+						customer {
+							id
+							fullName
+							email {
+								email
+							}
+							company {
+								id
+								name
+							}
+						}
+						assignedTo {
+							... on User {
+								id
+								fullName
+								email
+							}
+						}
+					}
+					cursor
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				totalCount
+			}
+		}
+	`)
+
+	req.Var("first", limit)
+	req.Var("customerIds", []string{customerId})
+	req.Var("statuses", statuses)
+	if cursor != "" {
+		req.Var("after", cursor)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		Threads *types.ThreadConnection `json:"threads"`
+	}
+	if err := c.run(ctx, "threadsByCustomer", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get threads by customer: %w", err)
+	}
+
+	return resp.Threads, nil
+}
+
 // GetThreadsByDateRange retrieves threads filtered by updated date range
 // The statusDetails field is intentionally excluding threads that are IGNORED.
 func (c *PlainClient) GetThreadsByDateRange(ctx context.Context, dateAfter string, limit int, cursor string) (*types.ThreadConnection, error) {
 
 	req := graphql.NewRequest(`
-		query GetThreadsByDateRange($first: Int!, $dateAfter: String, $cursor: String) {
+		query threadsByDateRange($first: Int!, $dateAfter: String, $cursor: String) {
 			threads(first: $first, after: $cursor, filters: {
 			statuses: [TODO,SNOOZED,DONE]
 			updatedAt: {
@@ -158,6 +268,7 @@ func (c *PlainClient) GetThreadsByDateRange(ctx context.Context, dateAfter strin
         customer {
           fullName
           company {
+            id
             name
           }
         }
@@ -204,7 +315,7 @@ func (c *PlainClient) GetThreadsByDateRange(ctx context.Context, dateAfter strin
 	var resp struct {
 		Threads *types.ThreadConnection `json:"threads"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "threadsByDateRange", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to get threads: %w", err)
 	}
 
@@ -267,7 +378,7 @@ func (c *PlainClient) GetThreads(ctx context.Context, limit int, cursor string)
 	var resp struct {
 		Threads *types.ThreadConnection `json:"threads"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "threads", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to get threads: %w", err)
 	}
 
@@ -309,6 +420,13 @@ func (c *PlainClient) GetAllThreads(ctx context.Context, limit int, cursor strin
 								email
 							}
 						}
+						labels {
+							id
+							labelType {
+								id
+								name
+							}
+						}
 					}
 					cursor
 				}
@@ -330,7 +448,7 @@ func (c *PlainClient) GetAllThreads(ctx context.Context, limit int, cursor strin
 	var resp struct {
 		Threads *types.ThreadConnection `json:"threads"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "threads", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to get all threads: %w", err)
 	}
 
@@ -370,6 +488,13 @@ func (c *PlainClient) GetThreadsByStatus(ctx context.Context, status string, lim
                 publicName
               }
             }
+						labels {
+							id
+							labelType {
+								id
+								name
+							}
+						}
 					}
 					cursor
 				}
@@ -392,7 +517,7 @@ func (c *PlainClient) GetThreadsByStatus(ctx context.Context, status string, lim
 	var resp struct {
 		Threads *types.ThreadConnection `json:"threads"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "threads", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to get threads by status: %w", err)
 	}
 
@@ -432,6 +557,15 @@ func (c *PlainClient) GetThreadById(ctx context.Context, threadId string) (*type
 						email
 					}
 				}
+				labels {
+					id
+					labelType {
+						id
+						name
+						icon
+						color
+					}
+				}
 			}
 		}
 	`)
@@ -442,23 +576,34 @@ func (c *PlainClient) GetThreadById(ctx context.Context, threadId string) (*type
 	var resp struct {
 		Thread *types.Thread `json:"thread"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "thread", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to get thread: %w", err)
 	}
 
 	return resp.Thread, nil
 }
 
-// GetLabels retrieves all labels
+// GetLabels retrieves all active (non-archived) label types in the workspace
 func (c *PlainClient) GetLabels(ctx context.Context) ([]*types.LabelType, error) {
 	req := graphql.NewRequest(`
-		query labels {
-			labels {
-				id
-				name
-				color
-				createdAt {
-					iso8601
+		query labelTypes($first: Int!, $after: String) {
+			labelTypes(first: $first, after: $after, filters: { isArchived: false }) {
+				edges {
+					node {
+						id
+						name
+						icon
+						color
+						isArchived
+						createdAt {
+							iso8601
+						}
+					}
+					cursor
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
 				}
 			}
 		}
@@ -466,26 +611,51 @@ func (c *PlainClient) GetLabels(ctx context.Context) ([]*types.LabelType, error)
 
 	c.setHeaders(req)
 
-	var resp struct {
-		Labels []*types.LabelType `json:"labels"`
-	}
+	var labels []*types.LabelType
+	cursor := ""
+	for {
+		req.Var("first", 100)
+		if cursor != "" {
+			req.Var("after", cursor)
+		}
+
+		var resp struct {
+			LabelTypes *types.LabelTypeConnection `json:"labelTypes"`
+		}
 
-	if err := c.client.Run(ctx, req, &resp); err != nil {
-		return nil, fmt.Errorf("failed to get labels: %w", err)
+		if err := c.run(ctx, "labelTypes", req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to get labels: %w", err)
+		}
+
+		if resp.LabelTypes == nil {
+			break
+		}
+		for _, edge := range resp.LabelTypes.Edges {
+			if edge.Node != nil {
+				labels = append(labels, edge.Node)
+			}
+		}
+
+		if resp.LabelTypes.PageInfo == nil || !resp.LabelTypes.PageInfo.HasNextPage {
+			break
+		}
+		cursor = resp.LabelTypes.PageInfo.EndCursor
 	}
 
-	return resp.Labels, nil
+	return labels, nil
 }
 
-// CreateLabel creates a new label
+// CreateLabel creates a new label type
 func (c *PlainClient) CreateLabel(ctx context.Context, name, color string) (*types.LabelType, error) {
 	req := graphql.NewRequest(`
-		mutation createLabel($input: CreateLabelInput!) {
-			createLabel(input: $input) {
+		mutation createLabelType($input: CreateLabelTypeInput!) {
+			createLabelType(input: $input) {
 				labelType {
 					id
 					name
+					icon
 					color
+					isArchived
 					createdAt {
 						iso8601
 					}
@@ -499,34 +669,36 @@ func (c *PlainClient) CreateLabel(ctx context.Context, name, color string) (*typ
 	`)
 
 	input := map[string]interface{}{
-		"name":  name,
-		"color": color,
+		"name": name,
+	}
+	if color != "" {
+		input["color"] = color
 	}
 	req.Var("input", input)
 	c.setHeaders(req)
 
 	var resp struct {
-		CreateLabel struct {
+		CreateLabelType struct {
 			LabelType *types.LabelType `json:"labelType"`
 			Error     *types.APIError  `json:"error"`
-		} `json:"createLabel"`
+		} `json:"createLabelType"`
 	}
 
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "createLabelType", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to create label: %w", err)
 	}
 
-	if resp.CreateLabel.Error != nil {
-		return nil, resp.CreateLabel.Error
+	if resp.CreateLabelType.Error != nil {
+		return nil, resp.CreateLabelType.Error
 	}
 
-	return resp.CreateLabel.LabelType, nil
+	return resp.CreateLabelType.LabelType, nil
 }
 
-// GetThreadWithMessages retrieves a thread with its messages
+// GetThreadWithMessages retrieves a thread with its most recent page of timeline entries
 func (c *PlainClient) GetThreadWithMessages(ctx context.Context, threadId string) (*types.Thread, error) {
 	req := graphql.NewRequest(`
-		query thread($threadId: ID!) {
+		query thread($threadId: ID!, $timelineLast: Int!) {
 			thread(threadId: $threadId) {
 				id
 				title
@@ -556,103 +728,23 @@ func (c *PlainClient) GetThreadWithMessages(ctx context.Context, threadId string
 						email
 					}
 				}
-				timelineEntries {
+				labels {
+					id
+					labelType {
+						id
+						name
+					}
+				}
+				timelineEntries(last: $timelineLast) {
 					edges {
 						node {
-							id
-							timestamp {
-								iso8601
-							}
-							actor {
-								... on UserActor {
-									user {
-										id
-										fullName
-										email
-									}
-								}
-								... on CustomerActor {
-									customer {
-										id
-										fullName
-										email {
-											email
-										}
-									}
-								}
-								... on SystemActor {
-									systemId
-								}
-							}
-							entry {
-								... on EmailEntry {
-									__typename
-									emailId
-									textContent
-									from {
-										name
-										email
-									}
-									to {
-										name
-										email
-									}
-								}
-								... on ChatEntry {
-									__typename
-									chatId
-									chatText: text
-								}
-								... on NoteEntry {
-									__typename
-									noteId
-									noteText: text
-									markdown
-									attachments {
-										id
-										fileName
-										fileSize {
-											bytes
-											kiloBytes
-											megaBytes
-										}
-										fileExtension
-										fileMimeType
-										type
-									}
-								}
-								... on ThreadAssignmentTransitionedEntry {
-									__typename
-									previousAssignee {
-										... on User {
-											id
-											fullName
-											email
-										}
-									}
-									nextAssignee {
-										... on User {
-											id
-											fullName
-											email
-										}
-									}
-								}
-								... on ThreadStatusTransitionedEntry {
-									__typename
-									previousStatus
-									nextStatus
-								}
-								... on ThreadPriorityChangedEntry {
-									__typename
-									previousPriority
-									nextPriority
-								}
-							}
+							` + timelineEntryFields + `
 						}
 						cursor
 					}
 					pageInfo {
+						hasPreviousPage
+						startCursor
 						hasNextPage
 						endCursor
 					}
@@ -662,12 +754,13 @@ func (c *PlainClient) GetThreadWithMessages(ctx context.Context, threadId string
 	`)
 
 	req.Var("threadId", threadId)
+	req.Var("timelineLast", TimelinePageSize)
 	c.setHeaders(req)
 
 	var resp struct {
 		Thread *types.Thread `json:"thread"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "thread", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to get thread with messages: %w", err)
 	}
 
@@ -712,7 +805,7 @@ func (c *PlainClient) SearchCustomers(ctx context.Context, query string, limit i
 	var resp struct {
 		Customers *types.CustomerConnection `json:"customers"`
 	}
-	if err := c.client.Run(ctx, req, &resp); err != nil {
+	if err := c.run(ctx, "searchCustomers", req, &resp); err != nil {
 		return nil, fmt.Errorf("failed to search customers: %w", err)
 	}
 
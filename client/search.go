@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"simple/types"
+
+	"github.com/machinebox/graphql"
+)
+
+// SearchThreads returns a page of the threads matching a search term, as Plain's
+// thread search ranks them
+func (c *PlainClient) SearchThreads(ctx context.Context, term string, limit int, cursor string) (*types.ThreadSearchResultConnection, error) {
+	req := graphql.NewRequest(`
+		query searchThreads($searchQuery: ThreadsSearchQuery!, $first: Int!, $after: String) {
+			searchThreads(searchQuery: $searchQuery, first: $first, after: $after) {
+				edges {
+					node {
+						thread {
+							id
+							title
+							previewText
+							status
+							priority
+							createdAt {
+								iso8601
+							}
+							updatedAt {
+								iso8601
+							}
+							customer {
+								id
+								fullName
+								email {
+									email
+								}
+								company {
+									id
+									name
+								}
+							}
+							assignedTo {
+								... on User {
+									id
+									fullName
+									email
+								}
+							}
+							labels {
+								id
+								labelType {
+									id
+									name
+								}
+							}
+						}
+					}
+					cursor
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`)
+
+	req.Var("searchQuery", map[string]interface{}{"term": term})
+	req.Var("first", limit)
+	if cursor != "" {
+		req.Var("after", cursor)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		SearchThreads *types.ThreadSearchResultConnection `json:"searchThreads"`
+	}
+	if err := c.run(ctx, "searchThreads", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to search threads: %w", err)
+	}
+	if resp.SearchThreads == nil {
+		return &types.ThreadSearchResultConnection{}, nil
+	}
+
+	return resp.SearchThreads, nil
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"simple/types"
+)
+
+// ErrOffline is returned for requests only the Plain API can answer
+var ErrOffline = errors.New("not available offline")
+
+// DataSource reads threads. PlainClient reads them from the Plain API, and the
+// local store stands in for it in offline mode.
+type DataSource interface {
+	GetThreads(ctx context.Context, limit int, cursor string) (*types.ThreadConnection, error)
+	GetAllThreads(ctx context.Context, limit int, cursor string) (*types.ThreadConnection, error)
+	GetThreadsByStatus(ctx context.Context, status string, limit int, cursor string) (*types.ThreadConnection, error)
+	GetThreadsByFilter(ctx context.Context, filter ThreadFilter, limit int, cursor string) (*types.ThreadConnection, error)
+	GetThreadsByDateRange(ctx context.Context, dateAfter string, limit int, cursor string) (*types.ThreadConnection, error)
+	GetThreadById(ctx context.Context, threadId string) (*types.Thread, error)
+	GetThreadWithMessages(ctx context.Context, threadId string) (*types.Thread, error)
+	GetThreadTimeline(ctx context.Context, threadId string, last int, before string) (*types.TimelineEntryConnection, error)
+	CountThreads(ctx context.Context, filter ThreadFilter) (count int, exact bool, err error)
+	GetLabels(ctx context.Context) ([]*types.LabelType, error)
+}
@@ -0,0 +1,348 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"simple/types"
+
+	"github.com/machinebox/graphql"
+)
+
+// timelineEntryFields selects a timeline entry with its actor and entry. Every actor
+// and entry fragment requests __typename, which types.TimelineEntry decodes by.
+// Text fields are aliased because their nullability differs between entry types.
+const timelineEntryFields = `
+	id
+	timestamp {
+		iso8601
+	}
+	actor {
+		__typename
+		... on UserActor {
+			user {
+				id
+				fullName
+				email
+			}
+		}
+		... on CustomerActor {
+			customer {
+				id
+				fullName
+				email {
+					email
+				}
+			}
+		}
+		... on DeletedCustomerActor {
+			customerId
+		}
+		... on SystemActor {
+			systemId
+		}
+		... on MachineUserActor {
+			machineUser {
+				id
+				fullName
+			}
+		}
+	}
+	entry {
+		__typename
+		... on EmailEntry {
+			emailId
+			textContent
+			from {
+				name
+				email
+			}
+			to {
+				name
+				email
+			}
+			attachments {
+				` + attachmentFields + `
+			}
+		}
+		... on ChatEntry {
+			chatId
+			chatText: text
+			attachments {
+				` + attachmentFields + `
+			}
+		}
+		... on NoteEntry {
+			noteId
+			noteText: text
+			markdown
+			attachments {
+				` + attachmentFields + `
+			}
+		}
+		... on CustomEntry {
+			externalId
+			title
+			type
+			attachments {
+				` + attachmentFields + `
+			}
+		}
+		... on SlackMessageEntry {
+			slackMessageLink
+			slackWebMessageLink
+			slackText: text
+			customerId
+			lastEditedOnSlackAt {
+				iso8601
+			}
+			deletedOnSlackAt {
+				iso8601
+			}
+			attachments {
+				` + attachmentFields + `
+			}
+		}
+		... on SlackReplyEntry {
+			slackMessageLink
+			slackWebMessageLink
+			slackText: text
+			customerId
+			lastEditedOnSlackAt {
+				iso8601
+			}
+			deletedOnSlackAt {
+				iso8601
+			}
+			attachments {
+				` + attachmentFields + `
+			}
+		}
+		... on ThreadAssignmentTransitionedEntry {
+			previousAssignee {
+				__typename
+				... on User {
+					id
+					fullName
+					email
+				}
+				... on MachineUser {
+					id
+					fullName
+				}
+			}
+			nextAssignee {
+				__typename
+				... on User {
+					id
+					fullName
+					email
+				}
+				... on MachineUser {
+					id
+					fullName
+				}
+			}
+		}
+		... on ThreadStatusTransitionedEntry {
+			previousStatus
+			nextStatus
+		}
+		... on ThreadPriorityChangedEntry {
+			previousPriority
+			nextPriority
+		}
+		... on ThreadLabelsChangedEntry {
+			previousLabels {
+				id
+				labelType {
+					id
+					name
+				}
+			}
+			nextLabels {
+				id
+				labelType {
+					id
+					name
+				}
+			}
+		}
+		... on ServiceLevelAgreementStatusTransitionedEntry {
+			previousSlaStatus: previousStatus
+			nextSlaStatus: nextStatus
+			serviceLevelAgreement {
+				__typename
+				id
+			}
+		}
+		... on LinearIssueThreadLinkStateTransitionedEntry {
+			linearIssueId
+			previousLinearStateId
+			nextLinearStateId
+		}
+		... on ThreadDiscussionEntry {
+			threadDiscussionId
+			slackChannelName
+			slackMessageLink
+			emailRecipients
+		}
+		... on ThreadDiscussionResolvedEntry {
+			threadDiscussionId
+			slackChannelName
+			slackMessageLink
+			resolvedAt {
+				iso8601
+			}
+		}
+		... on ThreadEventEntry {
+			timelineEventId
+			title
+			externalId
+		}
+		... on CustomerEventEntry {
+			timelineEventId
+			title
+			externalId
+		}
+	}
+`
+
+// attachmentFields selects the metadata of an attachment
+const attachmentFields = `
+	id
+	fileName
+	fileSize {
+		bytes
+		kiloBytes
+		megaBytes
+	}
+	fileExtension
+	fileMimeType
+	type
+`
+
+// TimelinePageSize is the number of timeline entries fetched per page
+const TimelinePageSize = 50
+
+// GetThreadTimeline retrieves the page of timeline entries immediately before a cursor, oldest
+// first. An empty cursor returns the most recent entries; use PageInfo.StartCursor and
+// HasPreviousPage to page further back.
+func (c *PlainClient) GetThreadTimeline(ctx context.Context, threadId string, last int, before string) (*types.TimelineEntryConnection, error) {
+	req := graphql.NewRequest(`
+		query threadTimeline($threadId: ID!, $last: Int!, $before: String) {
+			thread(threadId: $threadId) {
+				timelineEntries(last: $last, before: $before) {
+					edges {
+						node {
+							` + timelineEntryFields + `
+						}
+						cursor
+					}
+					pageInfo {
+						hasPreviousPage
+						startCursor
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		}
+	`)
+
+	req.Var("threadId", threadId)
+	req.Var("last", last)
+	if before != "" {
+		req.Var("before", before)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		Thread *struct {
+			TimelineEntries *types.TimelineEntryConnection `json:"timelineEntries"`
+		} `json:"thread"`
+	}
+	if err := c.run(ctx, "threadTimeline", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get thread timeline: %w", err)
+	}
+
+	if resp.Thread == nil {
+		return nil, fmt.Errorf("thread %s not found", threadId)
+	}
+
+	return resp.Thread.TimelineEntries, nil
+}
+
+// EachTimelineEntry calls fn for every timeline entry of a thread in chronological order,
+// fetching one page at a time so long timelines are streamed rather than held in memory
+func (c *PlainClient) EachTimelineEntry(ctx context.Context, threadId string, fn func(entry *types.TimelineEntry) error) error {
+	cursor := ""
+	for {
+		page, err := c.GetThreadTimelineAfter(ctx, threadId, TimelinePageSize, cursor)
+		if err != nil {
+			return err
+		}
+		if page == nil {
+			return nil
+		}
+
+		for _, edge := range page.Edges {
+			if edge == nil || edge.Node == nil {
+				continue
+			}
+			if err := fn(edge.Node); err != nil {
+				return err
+			}
+		}
+
+		if page.PageInfo == nil || !page.PageInfo.HasNextPage {
+			return nil
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+}
+
+// GetThreadTimelineAfter retrieves the page of timeline entries after a cursor, oldest first.
+// An empty cursor starts at the first entry.
+func (c *PlainClient) GetThreadTimelineAfter(ctx context.Context, threadId string, first int, after string) (*types.TimelineEntryConnection, error) {
+	req := graphql.NewRequest(`
+		query threadTimelineAfter($threadId: ID!, $first: Int!, $after: String) {
+			thread(threadId: $threadId) {
+				timelineEntries(first: $first, after: $after) {
+					edges {
+						node {
+							` + timelineEntryFields + `
+						}
+						cursor
+					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		}
+	`)
+
+	req.Var("threadId", threadId)
+	req.Var("first", first)
+	if after != "" {
+		req.Var("after", after)
+	}
+	c.setHeaders(req)
+
+	var resp struct {
+		Thread *struct {
+			TimelineEntries *types.TimelineEntryConnection `json:"timelineEntries"`
+		} `json:"thread"`
+	}
+	if err := c.run(ctx, "threadTimelineAfter", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get thread timeline: %w", err)
+	}
+
+	if resp.Thread == nil {
+		return nil, fmt.Errorf("thread %s not found", threadId)
+	}
+
+	return resp.Thread.TimelineEntries, nil
+}
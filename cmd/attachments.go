@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"simple/client"
+	"simple/config"
+	"simple/types"
+)
+
+// ThreadsAttachmentsCmd lists and downloads the attachments of a thread
+type ThreadsAttachmentsCmd struct {
+	ID       string `arg:"" help:"Thread ID"`
+	Download string `help:"Download the attachments into this directory" type:"path" optional:""`
+}
+
+// Run executes the threads attachments command
+func (t *ThreadsAttachmentsCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	plainClient := client.NewPlainClient(cfg)
+
+	attachments, err := plainClient.GetThreadAttachments(ctx, t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	if len(attachments) == 0 {
+		fmt.Println("No attachments found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSIZE\tTYPE")
+	fmt.Fprintln(w, "---\t----\t----\t----")
+	for _, attachment := range attachments {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", attachment.ID, attachment.FileName, attachment.Size(), attachment.FileMimeType)
+	}
+	w.Flush()
+
+	if t.Download == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(t.Download, 0755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	fmt.Println()
+	used := make(map[string]bool)
+	for _, attachment := range attachments {
+		path := filepath.Join(t.Download, attachmentFileName(attachment, used))
+		if err := downloadAttachment(ctx, plainClient, attachment, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachmentFileName picks a file name for an attachment that is safe to join to the
+// download directory and unique among the attachments downloaded so far
+func attachmentFileName(attachment *types.Attachment, used map[string]bool) string {
+	name := filepath.Base(filepath.Clean("/" + attachment.FileName))
+	if name == "/" || name == "." {
+		name = attachment.ID
+	}
+	if used[name] {
+		ext := filepath.Ext(name)
+		name = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(name, ext), attachment.ID, ext)
+	}
+	used[name] = true
+	return name
+}
+
+// downloadAttachment saves an attachment to path, reporting progress on stderr and
+// printing the SHA-256 checksum of the saved file
+func downloadAttachment(ctx context.Context, plainClient *client.PlainClient, attachment *types.Attachment, path string) (err error) {
+	// Write to a temporary file so an interrupted download never looks complete
+	partial := path + ".part"
+	file, err := os.Create(partial)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", partial, err)
+	}
+	// Nothing is left behind when the download can't be saved
+	defer func() {
+		if err != nil {
+			os.Remove(partial)
+		}
+	}()
+
+	hash := sha256.New()
+	name := filepath.Base(path)
+	written, err := plainClient.DownloadAttachment(ctx, attachment.ID, io.MultiWriter(file, hash), func(written, total int64) {
+		if total > 0 {
+			fmt.Fprintf(os.Stderr, "\r  %s  %3d%% (%s / %s)", name, written*100/total, types.FormatBytes(written), types.FormatBytes(total))
+		} else {
+			fmt.Fprintf(os.Stderr, "\r  %s  %s", name, types.FormatBytes(written))
+		}
+	})
+	fmt.Fprint(os.Stderr, "\r\033[K")
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", attachment.FileName, err)
+	}
+
+	if err = os.Rename(partial, path); err != nil {
+		return fmt.Errorf("failed to save %s: %w", path, err)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	fmt.Printf("Saved %s (%s)  sha256:%s\n", path, types.FormatBytes(written), checksum)
+
+	if attachment.FileSize != nil && attachment.FileSize.Bytes > 0 && int64(attachment.FileSize.Bytes) != written {
+		fmt.Fprintf(os.Stderr, "Warning: %s is %d bytes but Plain reported %d\n", path, written, attachment.FileSize.Bytes)
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"simple/client"
+	"simple/config"
+	"simple/types"
+)
+
+// tierToString returns the display name of a tier
+func tierToString(tier *types.Tier) string {
+	if tier == nil || tier.Name == "" {
+		return "N/A"
+	}
+	return tier.Name
+}
+
+// CompaniesCmd represents the companies command
+type CompaniesCmd struct {
+	List      CompaniesListCmd      `cmd:"" help:"List companies"`
+	Get       CompaniesGetCmd       `cmd:"" help:"Get company by ID or name, with its open threads"`
+	Threads   CompaniesThreadsCmd   `cmd:"" help:"List threads for a company"`
+	Customers CompaniesCustomersCmd `cmd:"" help:"List customers in a company"`
+}
+
+// CompaniesListCmd lists companies
+type CompaniesListCmd struct {
+	Limit  int    `help:"Number of companies to retrieve" default:"20"`
+	Cursor string `help:"Cursor for pagination" optional:""`
+}
+
+// Run executes the companies list command
+func (c *CompaniesListCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	client := client.NewPlainClient(cfg)
+
+	companies, err := client.GetCompanies(ctx, c.Limit, c.Cursor)
+	if err != nil {
+		return fmt.Errorf("failed to get companies: %w", err)
+	}
+
+	if companies == nil || len(companies.Edges) == 0 {
+		fmt.Println("No companies found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tNAME\tDOMAIN\tTIER\tCREATED")
+	fmt.Fprintln(w, "---\t----\t------\t----\t-------")
+
+	for _, edge := range companies.Edges {
+		company := edge.Node
+		if company == nil {
+			continue
+		}
+
+		createdAt := "N/A"
+		if company.CreatedAt != nil {
+			if t, err := company.CreatedAt.Time(); err == nil {
+				createdAt = t.Format("2006-01-02 15:04")
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			company.ID,
+			company.Name,
+			company.Domain,
+			tierToString(company.Tier),
+			createdAt,
+		)
+	}
+	w.Flush()
+
+	if companies.PageInfo != nil && companies.PageInfo.HasNextPage {
+		fmt.Printf("\nNext page cursor: %s\n", companies.PageInfo.EndCursor)
+	}
+
+	return nil
+}
+
+// CompaniesGetCmd gets a company by ID or name
+type CompaniesGetCmd struct {
+	Company string `arg:"" help:"Company ID or name"`
+	Limit   int    `help:"Number of open threads to show" default:"20"`
+}
+
+// Run executes the companies get command
+func (c *CompaniesGetCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	client := client.NewPlainClient(cfg)
+
+	company, err := client.FindCompany(ctx, c.Company)
+	if err != nil {
+		return fmt.Errorf("failed to get company: %w", err)
+	}
+
+	fmt.Printf("Company Details:\n")
+	fmt.Printf("  ID: %s\n", company.ID)
+	fmt.Printf("  Name: %s\n", company.Name)
+	if company.Domain != "" {
+		fmt.Printf("  Domain: %s\n", company.Domain)
+	}
+	fmt.Printf("  Tier: %s\n", tierToString(company.Tier))
+
+	if company.CreatedAt != nil {
+		if t, err := company.CreatedAt.Time(); err == nil {
+			fmt.Printf("  Created: %s\n", t.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	if company.UpdatedAt != nil {
+		if t, err := company.UpdatedAt.Time(); err == nil {
+			fmt.Printf("  Updated: %s\n", t.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	threads, err := client.GetThreadsByCompany(ctx, company.ID, []string{"TODO", "SNOOZED"}, c.Limit, "")
+	if err != nil {
+		return fmt.Errorf("failed to get threads for company: %w", err)
+	}
+
+	fmt.Printf("\nOpen Threads:\n")
+	if threads == nil || len(threads.Edges) == 0 {
+		fmt.Println("  No open threads")
+		return nil
+	}
+	printThreadsTable(threads)
+
+	return nil
+}
+
+// CompaniesThreadsCmd lists threads for a company
+type CompaniesThreadsCmd struct {
+	Company string `arg:"" help:"Company ID or name"`
+	Status  string `help:"Filter by status (TODO, SNOOZED, DONE), comma separated" default:"TODO,SNOOZED"`
+	Limit   int    `help:"Number of threads to retrieve" default:"20"`
+	Cursor  string `help:"Cursor for pagination" optional:""`
+}
+
+// Run executes the companies threads command
+func (c *CompaniesThreadsCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	client := client.NewPlainClient(cfg)
+
+	company, err := client.FindCompany(ctx, c.Company)
+	if err != nil {
+		return fmt.Errorf("failed to get company: %w", err)
+	}
+
+	var statuses []string
+	for _, status := range strings.Split(c.Status, ",") {
+		if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+
+	threads, err := client.GetThreadsByCompany(ctx, company.ID, statuses, c.Limit, c.Cursor)
+	if err != nil {
+		return fmt.Errorf("failed to get threads: %w", err)
+	}
+
+	if threads == nil || len(threads.Edges) == 0 {
+		fmt.Println("No threads found")
+		return nil
+	}
+
+	printThreadsTable(threads)
+
+	return nil
+}
+
+// CompaniesCustomersCmd lists customers in a company
+type CompaniesCustomersCmd struct {
+	Company string `arg:"" help:"Company ID or name"`
+	Limit   int    `help:"Number of customers to retrieve" default:"20"`
+	Cursor  string `help:"Cursor for pagination" optional:""`
+}
+
+// Run executes the companies customers command
+func (c *CompaniesCustomersCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	client := client.NewPlainClient(cfg)
+
+	company, err := client.FindCompany(ctx, c.Company)
+	if err != nil {
+		return fmt.Errorf("failed to get company: %w", err)
+	}
+
+	customers, err := client.GetCustomersByCompany(ctx, company.ID, c.Limit, c.Cursor)
+	if err != nil {
+		return fmt.Errorf("failed to get customers: %w", err)
+	}
+
+	if customers == nil || len(customers.Edges) == 0 {
+		fmt.Println("No customers found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tNAME\tEMAIL\tSTATUS\tCREATED")
+	fmt.Fprintln(w, "---\t----\t-----\t------\t-------")
+
+	for _, edge := range customers.Edges {
+		customer := edge.Node
+		if customer == nil {
+			continue
+		}
+
+		createdAt := "N/A"
+		if customer.CreatedAt != nil {
+			if t, err := customer.CreatedAt.Time(); err == nil {
+				createdAt = t.Format("2006-01-02 15:04")
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			customer.ID,
+			customer.FullName,
+			customer.GetEmail(),
+			customer.Status,
+			createdAt,
+		)
+	}
+	w.Flush()
+
+	if customers.PageInfo != nil && customers.PageInfo.HasNextPage {
+		fmt.Printf("\nNext page cursor: %s\n", customers.PageInfo.EndCursor)
+	}
+
+	return nil
+}
+
+// TenantsCmd represents the tenants command
+type TenantsCmd struct {
+	List TenantsListCmd `cmd:"" help:"List tenants"`
+}
+
+// TenantsListCmd lists tenants
+type TenantsListCmd struct {
+	Limit  int    `help:"Number of tenants to retrieve" default:"20"`
+	Cursor string `help:"Cursor for pagination" optional:""`
+}
+
+// Run executes the tenants list command
+func (t *TenantsListCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	client := client.NewPlainClient(cfg)
+
+	tenants, err := client.GetTenants(ctx, t.Limit, t.Cursor)
+	if err != nil {
+		return fmt.Errorf("failed to get tenants: %w", err)
+	}
+
+	if tenants == nil || len(tenants.Edges) == 0 {
+		fmt.Println("No tenants found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tNAME\tEXTERNAL ID\tTIER\tCREATED")
+	fmt.Fprintln(w, "---\t----\t-----------\t----\t-------")
+
+	for _, edge := range tenants.Edges {
+		tenant := edge.Node
+		if tenant == nil {
+			continue
+		}
+
+		createdAt := "N/A"
+		if tenant.CreatedAt != nil {
+			if t, err := tenant.CreatedAt.Time(); err == nil {
+				createdAt = t.Format("2006-01-02 15:04")
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			tenant.ID,
+			tenant.Name,
+			tenant.ExternalID,
+			tierToString(tenant.Tier),
+			createdAt,
+		)
+	}
+	w.Flush()
+
+	if tenants.PageInfo != nil && tenants.PageInfo.HasNextPage {
+		fmt.Printf("\nNext page cursor: %s\n", tenants.PageInfo.EndCursor)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"simple/client"
+	"simple/config"
+)
+
+// LabelsCmd represents the labels command
+type LabelsCmd struct {
+	List    LabelsListCmd    `cmd:"" help:"List labels"`
+	Create  LabelsCreateCmd  `cmd:"" help:"Create a label"`
+	Archive LabelsArchiveCmd `cmd:"" help:"Archive a label"`
+}
+
+// LabelsListCmd lists labels
+type LabelsListCmd struct{}
+
+// Run executes the labels list command
+func (l *LabelsListCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	labelCache := client.NewLabelCache(client.NewPlainClient(cfg))
+
+	// Listing always goes to the API so the cache is refreshed as a side effect.
+	labels, err := labelCache.Refresh(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get labels: %w", err)
+	}
+
+	if len(labels) == 0 {
+		fmt.Println("No labels found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "ID\tNAME\tCOLOR\tCREATED")
+	fmt.Fprintln(w, "---\t----\t-----\t-------")
+
+	for _, label := range labels {
+		color := "N/A"
+		if label.Color != nil && *label.Color != "" {
+			color = *label.Color
+		}
+
+		createdAt := "N/A"
+		if label.CreatedAt != nil {
+			if t, err := label.CreatedAt.Time(); err == nil {
+				createdAt = t.Format("2006-01-02 15:04")
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", label.ID, label.Name, color, createdAt)
+	}
+
+	return nil
+}
+
+// LabelsCreateCmd creates a label
+type LabelsCreateCmd struct {
+	Name  string `arg:"" help:"Label name"`
+	Color string `help:"Label color as a hex code (e.g. #ff0000)" optional:""`
+}
+
+// Run executes the labels create command
+func (l *LabelsCreateCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	plainClient := client.NewPlainClient(cfg)
+
+	label, err := plainClient.CreateLabel(ctx, l.Name, l.Color)
+	if err != nil {
+		return fmt.Errorf("failed to create label: %w", err)
+	}
+	client.NewLabelCache(plainClient).Invalidate()
+
+	fmt.Printf("Created label %q (%s)\n", label.Name, label.ID)
+	return nil
+}
+
+// LabelsArchiveCmd archives a label
+type LabelsArchiveCmd struct {
+	Name string `arg:"" help:"Label name or ID"`
+}
+
+// Run executes the labels archive command
+func (l *LabelsArchiveCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	plainClient := client.NewPlainClient(cfg)
+	labelCache := client.NewLabelCache(plainClient)
+
+	labels, err := labelCache.Resolve(ctx, []string{l.Name})
+	if err != nil {
+		return err
+	}
+
+	if _, err := plainClient.ArchiveLabel(ctx, labels[0].ID); err != nil {
+		return fmt.Errorf("failed to archive label: %w", err)
+	}
+	labelCache.Invalidate()
+
+	fmt.Printf("Archived label %q (%s)\n", labels[0].Name, labels[0].ID)
+	return nil
+}
+
+// ThreadsLabelCmd manages the labels applied to a thread
+type ThreadsLabelCmd struct {
+	Add    ThreadsLabelAddCmd    `cmd:"" help:"Add labels to a thread"`
+	Remove ThreadsLabelRemoveCmd `cmd:"" help:"Remove labels from a thread"`
+}
+
+// ThreadsLabelAddCmd adds labels to a thread
+type ThreadsLabelAddCmd struct {
+	ThreadID string   `arg:"" help:"Thread ID"`
+	Labels   []string `arg:"" help:"Label names or IDs"`
+}
+
+// Run executes the threads label add command
+func (t *ThreadsLabelAddCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	plainClient := client.NewPlainClient(cfg)
+
+	labels, err := client.NewLabelCache(plainClient).Resolve(ctx, t.Labels)
+	if err != nil {
+		return err
+	}
+
+	labelTypeIDs := make([]string, len(labels))
+	names := make([]string, len(labels))
+	for i, label := range labels {
+		labelTypeIDs[i] = label.ID
+		names[i] = label.Name
+	}
+
+	if _, err := plainClient.AddLabels(ctx, t.ThreadID, labelTypeIDs); err != nil {
+		return fmt.Errorf("failed to add labels: %w", err)
+	}
+
+	fmt.Printf("Added %s to thread %s\n", strings.Join(names, ", "), t.ThreadID)
+	return nil
+}
+
+// ThreadsLabelRemoveCmd removes labels from a thread
+type ThreadsLabelRemoveCmd struct {
+	ThreadID string   `arg:"" help:"Thread ID"`
+	Labels   []string `arg:"" help:"Label names or IDs"`
+}
+
+// Run executes the threads label remove command
+func (t *ThreadsLabelRemoveCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	plainClient := client.NewPlainClient(cfg)
+
+	labelTypes, err := client.NewLabelCache(plainClient).Resolve(ctx, t.Labels)
+	if err != nil {
+		return err
+	}
+
+	thread, err := plainClient.GetThreadById(ctx, t.ThreadID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread: %w", err)
+	}
+	if thread == nil {
+		return fmt.Errorf("thread %q not found", t.ThreadID)
+	}
+
+	// removeLabels takes the IDs of the labels on the thread, so map each label type to its label.
+	var labelIDs []string
+	var names []string
+	for _, labelType := range labelTypes {
+		found := false
+		for _, label := range thread.Labels {
+			if label.LabelType.ID == labelType.ID {
+				labelIDs = append(labelIDs, label.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("thread %s does not have label %q", t.ThreadID, labelType.Name)
+		}
+		names = append(names, labelType.Name)
+	}
+
+	if err := plainClient.RemoveLabels(ctx, labelIDs); err != nil {
+		return fmt.Errorf("failed to remove labels: %w", err)
+	}
+
+	fmt.Printf("Removed %s from thread %s\n", strings.Join(names, ", "), t.ThreadID)
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"simple/client"
+	"simple/config"
+)
+
+// ThreadsNoteCmd adds an internal note to a thread
+type ThreadsNoteCmd struct {
+	ID      string `arg:"" help:"Thread ID"`
+	Message string `short:"m" help:"Note text (Markdown). Without it the note is read from stdin, or written in $EDITOR" optional:""`
+}
+
+// Run executes the threads note command
+func (t *ThreadsNoteCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	plainClient := client.NewPlainClient(cfg)
+
+	thread, err := plainClient.GetThreadById(ctx, t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread: %w", err)
+	}
+	if thread == nil {
+		return fmt.Errorf("thread %s not found", t.ID)
+	}
+	if thread.Customer == nil {
+		return fmt.Errorf("thread %s has no customer", t.ID)
+	}
+
+	text, err := t.readNote()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("note is empty, nothing was added")
+	}
+
+	note, err := plainClient.CreateNote(ctx, thread.Customer.ID, thread.ID, strings.TrimSpace(text))
+	if err != nil {
+		return fmt.Errorf("failed to add note: %w", err)
+	}
+
+	fmt.Printf("Added internal note %s to thread %s\n", note.ID, thread.ID)
+	return nil
+}
+
+// readNote reads the note from --message, piped stdin or the user's editor
+func (t *ThreadsNoteCmd) readNote() (string, error) {
+	if t.Message != "" {
+		return t.Message, nil
+	}
+
+	if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read note from stdin: %w", err)
+		}
+		return string(data), nil
+	}
+
+	return editNote()
+}
+
+// editNote opens $VISUAL or $EDITOR on a temporary file and returns what was written
+func editNote() (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", "simple-note-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create note file: %w", err)
+	}
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
+
+	// Editors are often configured with arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run editor %q: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read note file: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+
+	"simple/client"
+	"simple/config"
+	"simple/store"
+)
+
+// offlineCommands read threads through a client.DataSource, so they can run
+// from the local store with --offline
+var offlineCommands = map[string]bool{
+	"tui":          true,
+	"threads list": true,
+	"threads all":  true,
+	"threads get":  true,
+	"report":       true,
+	"search":       true,
+}
+
+// SupportsOffline reports whether a command, as named by kong, can run with --offline
+func SupportsOffline(command string) bool {
+	return offlineCommands[commandPath(command)]
+}
+
+// commandPath drops the arguments from a command as named by kong, e.g.
+// "threads get <id>" becomes "threads get"
+func commandPath(command string) string {
+	var words []string
+	for _, word := range strings.Fields(command) {
+		if !strings.HasPrefix(word, "<") {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// newDataSource returns the local store in offline mode and the Plain API otherwise
+func newDataSource(cfg *config.Config) (client.DataSource, error) {
+	if cfg.Offline {
+		return store.OpenSource(cfg.DB)
+	}
+	return client.NewPlainClient(cfg), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -16,15 +17,26 @@ import (
 
 // ReportCmd represents the report command.
 type ReportCmd struct {
-	Range    string `arg:"" enum:"1d,7d,30d,60d" placeholder:"7d" default:"1d" help:"Generate a report of threads for a time range, accepts [1d, 7d,30d]"`
-	Summary  bool   `help:"Display only the summary of the report"`
-	Database string `arg:"" enum:"sqlite,postgres" short:"d" default:"sqlite" help:"Database to use for storing data"`
+	Range     string `arg:"" enum:"1d,7d,30d,60d" placeholder:"7d" default:"1d" help:"Generate a report of threads for a time range, accepts [1d, 7d,30d]"`
+	Summary   bool   `help:"Display only the summary of the report"`
+	Database  string `arg:"" enum:"sqlite,postgres" short:"d" default:"sqlite" help:"Database to use for storing data"`
+	Company   string `help:"Only include threads from this company (ID or name)" optional:""`
+	Timelines bool   `help:"Also store the threads' messages in the local store for simple search"`
 }
 
 // Run executes the report command.
 func (r *ReportCmd) Run(cfg *config.Config) error {
 	ctx := context.Background()
-	client := client.NewPlainClient(cfg)
+	if r.Timelines && r.Summary {
+		return fmt.Errorf("--timelines can't be combined with --summary, which stores nothing")
+	}
+	if r.Timelines && cfg.Offline {
+		return fmt.Errorf("storing timelines is %w", client.ErrOffline)
+	}
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
 
 	// Calculate date range based on the specified range.
 	now := time.Now()
@@ -51,15 +63,15 @@ func (r *ReportCmd) Run(cfg *config.Config) error {
 		startTime.Format("2006-01-02 15:04"),
 		now.Format("2006-01-02 15:04"))
 
-	// Fetch threads from the API.
-	threads, err := client.GetThreadsByDateRange(ctx, after.ISO8601, 100, "")
+	// Fetch threads from the API, or the local store when offline.
+	threads, err := source.GetThreadsByDateRange(ctx, after.ISO8601, 100, "")
 	if err != nil {
 		return fmt.Errorf("failed to get threads for date range: %w", err)
 	}
 
 	// If we are paginating, we should fetch all of the threads until we reach the end.
 	if threads.PageInfo.HasNextPage {
-		nextThreads, err := client.GetThreadsByDateRange(ctx, after.ISO8601, 100, threads.PageInfo.EndCursor)
+		nextThreads, err := source.GetThreadsByDateRange(ctx, after.ISO8601, 100, threads.PageInfo.EndCursor)
 		if err != nil {
 			return fmt.Errorf("failed to get next page of threads: %w", err)
 		}
@@ -67,7 +79,7 @@ func (r *ReportCmd) Run(cfg *config.Config) error {
 		// while there are more pages, continue fetching the threads.
 		for nextThreads.PageInfo.HasNextPage {
 			fmt.Printf("Found another page -- continuing\n")
-			nextThreads, err = client.GetThreadsByDateRange(ctx, after.ISO8601, 100, nextThreads.PageInfo.EndCursor)
+			nextThreads, err = source.GetThreadsByDateRange(ctx, after.ISO8601, 100, nextThreads.PageInfo.EndCursor)
 			if err != nil {
 				return fmt.Errorf("failed to get next page of threads: %w", err)
 			}
@@ -75,6 +87,21 @@ func (r *ReportCmd) Run(cfg *config.Config) error {
 		}
 	}
 
+	// Narrow the report down to a single company if requested.
+	// The local store only knows companies by name.
+	if r.Company != "" && threads != nil && cfg.Offline {
+		fmt.Printf("Filtering threads for company %s\n", r.Company)
+		threads.Edges = filterThreadsByCompanyName(threads.Edges, r.Company)
+	} else if r.Company != "" && threads != nil {
+		company, err := source.(*client.PlainClient).FindCompany(ctx, r.Company)
+		if err != nil {
+			return fmt.Errorf("failed to get company: %w", err)
+		}
+
+		fmt.Printf("Filtering threads for company %s (tier: %s)\n", company.Name, tierToString(company.Tier))
+		threads.Edges = filterThreadsByCompany(threads.Edges, company.ID)
+	}
+
 	if threads == nil || len(threads.Edges) == 0 {
 		fmt.Println("No threads found for the specified date range")
 		return nil
@@ -86,12 +113,18 @@ func (r *ReportCmd) Run(cfg *config.Config) error {
 		return nil
 	}
 
-	// Lets write to the database now
+	// Lets write to the database now, unless the threads came from it
 	//
-	switch r.Database {
+	database := r.Database
+	if cfg.Offline {
+		database = ""
+	}
+	switch database {
 	case "sqlite":
-		// TODO move this to config
-		db, err := store.SQLiteInitDB(os.Getenv("SQLITE_DB_PATH"))
+		if err := os.MkdirAll(filepath.Dir(cfg.DB.Source), 0755); err != nil {
+			return fmt.Errorf("failed to create database directory: %w", err)
+		}
+		db, err := store.SQLiteInitDB(cfg.DB.Source)
 		if err != nil {
 			return fmt.Errorf("failed to initialize database: %w", err)
 		}
@@ -137,31 +170,74 @@ func (r *ReportCmd) Run(cfg *config.Config) error {
 		}
 	}
 
-	db, err := store.SQLiteInitDB("/Users/jeremy/dev/tools/metabase/sqlite-data/plain.db")
+	if r.Timelines {
+		if err := saveTimelines(ctx, source.(*client.PlainClient), cfg.DB, threads); err != nil {
+			return err
+		}
+	}
+
+	// Display the report.
+	err = r.displayReport(threads, r.Range)
+	if err != nil {
+		return fmt.Errorf("failed to display report: %w", err)
+	}
+
+	return nil
+}
+
+// saveTimelines stores the messages of the threads in the local store
+func saveTimelines(ctx context.Context, plainClient *client.PlainClient, db config.DBConfig, threads *types.ThreadConnection) error {
+	writer, err := store.OpenWriter(db)
 	if err != nil {
-		return fmt.Errorf("failed to initialize database: %w", err)
+		return err
 	}
 
-	var threadReports []*store.Threads
+	fmt.Printf("Storing the timelines of %d threads\n", len(threads.Edges))
 	for _, edge := range threads.Edges {
 		if edge.Node == nil {
 			continue
 		}
-		threadReport := store.SQLiteFromThread(edge.Node)
-		threadReports = append(threadReports, threadReport)
+		var entries []*types.TimelineEntry
+		err := plainClient.EachTimelineEntry(ctx, edge.Node.ID, func(entry *types.TimelineEntry) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get timeline of thread %s: %w", edge.Node.ID, err)
+		}
+		if err := writer.SaveTimeline(edge.Node.ID, entries); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if err := store.SQLiteSaveThreads(db, threadReports); err != nil {
-		return fmt.Errorf("failed to write threads to database: %w", err)
+// filterThreadsByCompany returns the thread edges whose customer belongs to the given company.
+func filterThreadsByCompany(edges []*types.ThreadEdge, companyID string) []*types.ThreadEdge {
+	var filtered []*types.ThreadEdge
+	for _, edge := range edges {
+		if edge.Node == nil || edge.Node.Customer == nil || edge.Node.Customer.Company == nil {
+			continue
+		}
+		if edge.Node.Customer.Company.ID == companyID {
+			filtered = append(filtered, edge)
+		}
 	}
+	return filtered
+}
 
-	// Display the report.
-	err = r.displayReport(threads, r.Range)
-	if err != nil {
-		return fmt.Errorf("failed to display report: %w", err)
+// filterThreadsByCompanyName returns the thread edges whose customer's company has the given name, ignoring case.
+func filterThreadsByCompanyName(edges []*types.ThreadEdge, name string) []*types.ThreadEdge {
+	var filtered []*types.ThreadEdge
+	for _, edge := range edges {
+		if edge.Node == nil || edge.Node.Customer == nil || edge.Node.Customer.Company == nil {
+			continue
+		}
+		if strings.EqualFold(edge.Node.Customer.Company.Name, name) {
+			filtered = append(filtered, edge)
+		}
 	}
-
-	return nil
+	return filtered
 }
 
 // displayReport formats and displays the thread report.
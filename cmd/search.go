@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"simple/client"
+	"simple/config"
+	"simple/store"
+	"simple/types"
+)
+
+// SearchCmd searches the threads in the local store
+type SearchCmd struct {
+	Terms  []string `arg:"" help:"Words that the thread's title or one of its messages must all contain"`
+	Limit  int      `help:"Number of threads to show" default:"20"`
+	Offset int      `help:"Number of threads to skip, for the next page" default:"0"`
+}
+
+// Run executes the search command
+func (s *SearchCmd) Run(cfg *config.Config) error {
+	stats, err := store.OpenStats(cfg.DB)
+	if err != nil {
+		return err
+	}
+	if stats == nil {
+		return fmt.Errorf("no local store found (sync threads and their messages with `simple report --timelines` first)")
+	}
+
+	results, total, err := stats.Search(store.SearchQuery{
+		Terms:  strings.Join(s.Terms, " "),
+		Limit:  s.Limit,
+		Offset: s.Offset,
+	})
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No threads found")
+		return nil
+	}
+
+	threads := make([]*types.Thread, len(results))
+	snippets := make([]string, len(results))
+	for i := range results {
+		threads[i] = results[i].Thread()
+		snippets[i] = results[i].Snippet
+	}
+	printSearchResults(threads, snippets)
+
+	fmt.Printf("\nShowing %d-%d of %d threads\n", s.Offset+1, s.Offset+len(results), total)
+	if next := s.Offset + len(results); next < total {
+		fmt.Printf("Next page: --offset %d\n", next)
+	}
+	if !stats.Ranked() {
+		fmt.Fprintln(os.Stderr, "Results are unranked: this build has no SQLite FTS5 (build with -tags sqlite_fts5)")
+	}
+	return nil
+}
+
+// ThreadsSearchCmd searches threads with Plain's search
+type ThreadsSearchCmd struct {
+	Text   []string `arg:"" help:"Text to search for"`
+	Limit  int      `help:"Number of threads to retrieve" default:"20"`
+	Cursor string   `help:"Cursor for pagination" optional:""`
+}
+
+// Run executes the threads search command
+func (t *ThreadsSearchCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	plainClient := client.NewPlainClient(cfg)
+
+	text := strings.Join(t.Text, " ")
+	results, err := plainClient.SearchThreads(ctx, text, t.Limit, t.Cursor)
+	if err != nil {
+		return err
+	}
+
+	var threads []*types.Thread
+	var snippets []string
+	for _, edge := range results.Edges {
+		if edge == nil || edge.Node == nil || edge.Node.Thread == nil {
+			continue
+		}
+		threads = append(threads, edge.Node.Thread)
+		snippets = append(snippets, edge.Node.Thread.SearchSnippet(text))
+	}
+	if len(threads) == 0 {
+		fmt.Println("No threads found")
+		return nil
+	}
+	printSearchResults(threads, snippets)
+
+	if results.PageInfo != nil && results.PageInfo.HasNextPage {
+		fmt.Printf("\nNext page cursor: %s\n", results.PageInfo.EndCursor)
+	}
+	return nil
+}
+
+// printSearchResults prints threads with the snippets of their matching text
+// below them. Matches are bold on terminals and plain when piped.
+func printSearchResults(threads []*types.Thread, snippets []string) {
+	style := lipgloss.NewStyle().Bold(true).Underline(true)
+	highlight := func(match string) string { return style.Render(match) }
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, thread := range threads {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", thread.ID, thread.Status, thread.Title)
+		// Matches in the title have nothing more to show
+		if types.Highlight(snippets[i], strings.TrimSpace) != thread.Title {
+			fmt.Fprintf(w, "\t\t%s\n", types.Highlight(snippets[i], highlight))
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"simple/api"
+	"simple/client"
+	"simple/config"
+	"simple/metrics"
+	"simple/store"
+	"simple/webhook"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// localCommands don't call the Plain API, so they run without an API key
+var localCommands = map[string]bool{
+	"serve webhooks": true,
+	"serve api":      true,
+	"search":         true,
+}
+
+// NeedsPlain reports whether a command, as named by kong, calls the Plain API
+func NeedsPlain(command string) bool {
+	return !localCommands[commandPath(command)]
+}
+
+// ServeCmd represents the serve command
+type ServeCmd struct {
+	Webhooks ServeWebhooksCmd `cmd:"" help:"Receive Plain webhook deliveries"`
+	Metrics  ServeMetricsCmd  `cmd:"" help:"Expose thread metrics to Prometheus"`
+	API      ServeAPICmd      `cmd:"" name:"api" help:"Serve a read-only JSON API over the local store"`
+}
+
+// ServeWebhooksCmd receives Plain webhooks, stores their threads and passes them to the configured sinks
+type ServeWebhooksCmd struct {
+	Listen  string `help:"Address to listen on" default:":8080"`
+	Path    string `help:"URL path Plain delivers to" default:"/webhooks/plain"`
+	NoStore bool   `help:"Don't write threads from events to the local store"`
+}
+
+// Run executes the serve webhooks command
+func (s *ServeWebhooksCmd) Run(cfg *config.Config) error {
+	if cfg.Webhooks.Secret == "" {
+		return fmt.Errorf("webhooks.secret is required (set PLAIN_WEBHOOK_SECRET or configure it in the config file)")
+	}
+
+	var writer webhook.ThreadWriter
+	if !s.NoStore {
+		w, err := store.OpenWriter(cfg.DB)
+		if err != nil {
+			return err
+		}
+		writer = w
+	}
+
+	errorf := func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, "simple serve: "+format+"\n", args...)
+	}
+
+	handler := webhook.NewHandler(cfg.Webhooks.Secret, writer, webhook.NewSinks(cfg.Webhooks.Sinks, os.Stdout), errorf)
+	mux := http.NewServeMux()
+	mux.Handle(s.Path, handler)
+	server := &http.Server{
+		Addr:              s.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Listening for Plain webhooks on %s%s\n", s.Listen, s.Path)
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to serve webhooks: %w", err)
+	case <-ctx.Done():
+	}
+
+	err := shutdown(server)
+	// Events already acknowledged still reach the sinks
+	handler.Wait()
+	return err
+}
+
+// ServeMetricsCmd exposes thread metrics for Prometheus to scrape
+type ServeMetricsCmd struct {
+	Listen   string        `help:"Address to listen on" default:":9090"`
+	Path     string        `help:"URL path to serve the metrics on" default:"/metrics"`
+	Interval time.Duration `help:"How often to refresh the thread metrics" default:"1m"`
+	Source   string        `help:"Where to read threads from (api, store). The store has no priorities or assignees." enum:"api,store" default:"api"`
+}
+
+// Run executes the serve metrics command
+func (s *ServeMetricsCmd) Run(cfg *config.Config) error {
+	if s.Interval < config.MinRefreshInterval {
+		return fmt.Errorf("interval must be at least %s", config.MinRefreshInterval)
+	}
+
+	errorf := func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, "simple serve: "+format+"\n", args...)
+	}
+
+	var exporter *metrics.Exporter
+	switch s.Source {
+	case "store":
+		stats, err := store.OpenStats(cfg.DB)
+		if err != nil {
+			return err
+		}
+		if stats == nil {
+			return fmt.Errorf("no local store found (configure db.source and sync threads first)")
+		}
+		exporter = metrics.NewExporter(metrics.NewStoreSource(stats), errorf)
+	default:
+		plainClient := client.NewPlainClient(cfg)
+		exporter = metrics.NewExporter(metrics.NewAPISource(plainClient), errorf)
+		plainClient.SetObserver(exporter.ObserveRequest)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(s.Path, exporter.Handler())
+	server := &http.Server{
+		Addr:              s.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go exporter.Run(ctx, s.Interval)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Serving metrics on %s%s\n", s.Listen, s.Path)
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to serve metrics: %w", err)
+	case <-ctx.Done():
+	}
+
+	return shutdown(server)
+}
+
+// ServeAPICmd serves a read-only JSON API over the local store, so tools can
+// query support data without a Plain API key
+type ServeAPICmd struct {
+	// The API isn't authenticated, so it is only served locally unless asked
+	Listen string `help:"Address to listen on" default:"127.0.0.1:8081"`
+}
+
+// Run executes the serve api command
+func (s *ServeAPICmd) Run(cfg *config.Config) error {
+	stats, err := store.OpenStats(cfg.DB)
+	if err != nil {
+		return err
+	}
+	if stats == nil {
+		return fmt.Errorf("no local store found (configure db.source and sync threads first)")
+	}
+
+	errorf := func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, "simple serve: "+format+"\n", args...)
+	}
+
+	server := &http.Server{
+		Addr:              s.Listen,
+		Handler:           api.NewHandler(stats, errorf),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Serving the API on %s\n", s.Listen)
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to serve the API: %w", err)
+	case <-ctx.Done():
+	}
+
+	return shutdown(server)
+}
+
+// shutdown stops a server, giving in-flight requests shutdownTimeout to finish
+func shutdown(server *http.Server) error {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	return nil
+}
@@ -2,12 +2,16 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"simple/client"
 	"simple/config"
+	"simple/query"
+	"simple/store"
 	"simple/types"
 )
 
@@ -22,51 +26,17 @@ func priorityToString(priority int) string {
 		return "Medium"
 	case 3:
 		return "Low"
+	case store.UnknownPriority:
+		return "-"
 	default:
 		return fmt.Sprintf("%d", priority)
 	}
 }
 
-// ThreadsCmd represents the threads command
-type ThreadsCmd struct {
-	List ThreadsListCmd `cmd:"" help:"List threads"`
-	All  ThreadsAllCmd  `cmd:"" help:"List all threads (including done)"`
-	Get  ThreadsGetCmd  `cmd:"" help:"Get thread by ID"`
-}
-
-// ThreadsListCmd lists threads
-type ThreadsListCmd struct {
-	Limit  int    `help:"Number of threads to retrieve" default:"20"`
-	Cursor string `help:"Cursor for pagination" optional:""`
-	Status string `help:"Filter by status (TODO, SNOOZED, DONE)" optional:""`
-}
-
-// Run executes the threads list command
-func (t *ThreadsListCmd) Run(cfg *config.Config) error {
-	ctx := context.Background()
-	client := client.NewPlainClient(cfg)
-
-	var threads *types.ThreadConnection
-	var err error
-
-	if t.Status != "" {
-		threads, err = client.GetThreadsByStatus(ctx, t.Status, t.Limit, t.Cursor)
-	} else {
-		threads, err = client.GetThreads(ctx, t.Limit, t.Cursor)
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to get threads: %w", err)
-	}
-
-	if threads == nil || len(threads.Edges) == 0 {
-		fmt.Println("No threads found")
-		return nil
-	}
-
+// printThreadsTable prints a thread connection as a table followed by the next page cursor
+func printThreadsTable(threads *types.ThreadConnection) {
 	// Create table writer
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
 
 	// Print header
 	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tPRIORITY\tCUSTOMER\tCOMPANY\tCREATED")
@@ -75,6 +45,10 @@ func (t *ThreadsListCmd) Run(cfg *config.Config) error {
 	// Print threads
 	for _, edge := range threads.Edges {
 		thread := edge.Node
+		if thread == nil {
+			continue
+		}
+
 		createdAt := "N/A"
 		if thread.CreatedAt != nil {
 			if t, err := thread.CreatedAt.Time(); err == nil {
@@ -83,8 +57,12 @@ func (t *ThreadsListCmd) Run(cfg *config.Config) error {
 		}
 
 		customerName := "N/A"
+		companyName := "N/A"
 		if thread.Customer != nil {
 			customerName = thread.Customer.FullName
+			if thread.Customer.Company != nil {
+				companyName = thread.Customer.Company.Name
+			}
 		}
 
 		fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\t%s\t%s\n",
@@ -93,31 +71,55 @@ func (t *ThreadsListCmd) Run(cfg *config.Config) error {
 			thread.Status,
 			priorityToString(thread.Priority),
 			customerName,
-			thread.Customer.Company.Name,
+			companyName,
 			createdAt,
 		)
 	}
+	w.Flush()
 
 	// Print pagination info
-	if threads.PageInfo.HasNextPage {
+	if threads.PageInfo != nil && threads.PageInfo.HasNextPage {
 		fmt.Printf("\nNext page cursor: %s\n", threads.PageInfo.EndCursor)
 	}
+}
 
-	return nil
+// ThreadsCmd represents the threads command
+type ThreadsCmd struct {
+	List        ThreadsListCmd        `cmd:"" help:"List threads"`
+	All         ThreadsAllCmd         `cmd:"" help:"List all threads (including done)"`
+	Get         ThreadsGetCmd         `cmd:"" help:"Get thread by ID"`
+	Search      ThreadsSearchCmd      `cmd:"" help:"Search threads with Plain's search"`
+	Label       ThreadsLabelCmd       `cmd:"" help:"Add or remove thread labels"`
+	Attachments ThreadsAttachmentsCmd `cmd:"" help:"List or download thread attachments"`
+	Note        ThreadsNoteCmd        `cmd:"" help:"Add an internal note to a thread"`
 }
 
-// ThreadsAllCmd lists all threads including completed ones
-type ThreadsAllCmd struct {
+// ThreadsListCmd lists threads
+type ThreadsListCmd struct {
 	Limit  int    `help:"Number of threads to retrieve" default:"20"`
 	Cursor string `help:"Cursor for pagination" optional:""`
+	Status string `help:"Filter by status (TODO, SNOOZED, DONE)" optional:"" xor:"status"`
+	Query  string `help:"Filter with a query, e.g. 'status:todo priority:<=1 label:bug updated:>7d -assignee:none'" short:"q" optional:"" xor:"status"`
 }
 
-// Run executes the threads all command
-func (t *ThreadsAllCmd) Run(cfg *config.Config) error {
+// Run executes the threads list command
+func (t *ThreadsListCmd) Run(cfg *config.Config) error {
 	ctx := context.Background()
-	client := client.NewPlainClient(cfg)
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
+
+	var threads *types.ThreadConnection
+
+	if t.Query != "" {
+		threads, err = t.runQuery(ctx, source)
+	} else if t.Status != "" {
+		threads, err = source.GetThreadsByStatus(ctx, t.Status, t.Limit, t.Cursor)
+	} else {
+		threads, err = source.GetThreads(ctx, t.Limit, t.Cursor)
+	}
 
-	threads, err := client.GetAllThreads(ctx, t.Limit, t.Cursor)
 	if err != nil {
 		return fmt.Errorf("failed to get threads: %w", err)
 	}
@@ -127,59 +129,81 @@ func (t *ThreadsAllCmd) Run(cfg *config.Config) error {
 		return nil
 	}
 
-	// Create table writer
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
+	printThreadsTable(threads)
 
-	// Print header
-	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tPRIORITY\tCUSTOMER\tCOMPANY\tCREATED")
-	fmt.Fprintln(w, "---\t-----\t------\t--------\t--------\t-------\t-------")
+	return nil
+}
 
-	// Print threads
-	for _, edge := range threads.Edges {
-		thread := edge.Node
-		createdAt := "N/A"
-		if thread.CreatedAt != nil {
-			if t, err := thread.CreatedAt.Time(); err == nil {
-				createdAt = t.Format("2006-01-02 15:04")
-			}
+// runQuery fetches the threads matching the --query filter
+func (t *ThreadsListCmd) runQuery(ctx context.Context, source client.DataSource) (*types.ThreadConnection, error) {
+	compiled, err := query.ParseAndCompile(t.Query, time.Now())
+	if err != nil {
+		var parseErr *query.ParseError
+		if errors.As(err, &parseErr) {
+			fmt.Fprintln(os.Stderr, parseErr.Caret())
 		}
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
 
-		customerName := "N/A"
-		if thread.Customer != nil {
-			customerName = thread.Customer.FullName
-		}
+	if err := compiled.ResolveLabels(ctx, client.NewLabelCache(source)); err != nil {
+		return nil, err
+	}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\t%s\t%s\n",
-			thread.ID,
-			thread.Title,
-			thread.Status,
-			priorityToString(thread.Priority),
-			customerName,
-			thread.Customer.Company.Name,
-			createdAt,
-		)
+	return compiled.Fetch(ctx, source, t.Limit, t.Cursor)
+}
+
+// ThreadsAllCmd lists all threads including completed ones
+type ThreadsAllCmd struct {
+	Limit  int    `help:"Number of threads to retrieve" default:"20"`
+	Cursor string `help:"Cursor for pagination" optional:""`
+}
+
+// Run executes the threads all command
+func (t *ThreadsAllCmd) Run(cfg *config.Config) error {
+	ctx := context.Background()
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
 	}
 
-	// Print pagination info
-	if threads.PageInfo.HasNextPage {
-		fmt.Printf("\nNext page cursor: %s\n", threads.PageInfo.EndCursor)
+	threads, err := source.GetAllThreads(ctx, t.Limit, t.Cursor)
+	if err != nil {
+		return fmt.Errorf("failed to get threads: %w", err)
+	}
+
+	if threads == nil || len(threads.Edges) == 0 {
+		fmt.Println("No threads found")
+		return nil
 	}
 
+	printThreadsTable(threads)
+
 	return nil
 }
 
 // ThreadsGetCmd gets a thread by ID
 type ThreadsGetCmd struct {
-	ID string `arg:"" help:"Thread ID"`
+	ID       string `arg:"" help:"Thread ID"`
+	Timeline bool   `help:"Print the thread's conversation and events after its details"`
+	All      bool   `help:"With --timeline, print every timeline entry instead of only the most recent ones"`
+	Output   string `short:"o" enum:"text,markdown" default:"text" help:"Output format: text or markdown (a transcript for incident docs)"`
 }
 
 // Run executes the threads get command
 func (t *ThreadsGetCmd) Run(cfg *config.Config) error {
 	ctx := context.Background()
-	client := client.NewPlainClient(cfg)
+	if t.All && !t.Timeline {
+		return fmt.Errorf("--all requires --timeline")
+	}
+	if t.Timeline && cfg.Offline {
+		return fmt.Errorf("timelines are %w", client.ErrOffline)
+	}
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
 
-	thread, err := client.GetThreadById(ctx, t.ID)
+	thread, err := source.GetThreadById(ctx, t.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get thread: %w", err)
 	}
@@ -189,26 +213,43 @@ func (t *ThreadsGetCmd) Run(cfg *config.Config) error {
 		return nil
 	}
 
-	// Print thread details
-	fmt.Printf("Thread Details:\n")
-	fmt.Printf("  ID: %s\n", thread.ID)
-	fmt.Printf("  Title: %s\n", thread.Title)
-	fmt.Printf("  Status: %s\n", thread.Status)
-	fmt.Printf("  Priority: %s\n", priorityToString(thread.Priority))
+	tr := &transcript{w: os.Stdout, markdown: t.Output == "markdown"}
+	tr.details(thread)
 
-	if thread.Customer != nil {
-		fmt.Printf("  Customer: %s (%s)\n", thread.Customer.FullName, thread.Customer.GetEmail())
+	if !t.Timeline {
+		return nil
 	}
 
-	if thread.CreatedAt != nil {
-		if t, err := thread.CreatedAt.Time(); err == nil {
-			fmt.Printf("  Created: %s\n", t.Format("2006-01-02 15:04:05"))
+	// Long timelines are printed page by page as they are fetched. Offline
+	// timelines were refused above, so the source is the Plain client.
+	if t.All {
+		tr.timelineHeading(0)
+		if err := source.(*client.PlainClient).EachTimelineEntry(ctx, t.ID, tr.entry); err != nil {
+			return fmt.Errorf("failed to get timeline: %w", err)
 		}
+		return nil
 	}
 
-	if thread.UpdatedAt != nil {
-		if t, err := thread.UpdatedAt.Time(); err == nil {
-			fmt.Printf("  Updated: %s\n", t.Format("2006-01-02 15:04:05"))
+	timeline, err := source.GetThreadTimeline(ctx, t.ID, client.TimelinePageSize, "")
+	if err != nil {
+		return fmt.Errorf("failed to get timeline: %w", err)
+	}
+	if timeline == nil {
+		// Plain returns no connection for threads without a timeline
+		tr.timelineHeading(0)
+		return nil
+	}
+
+	shown := 0
+	if timeline.PageInfo != nil && timeline.PageInfo.HasPreviousPage {
+		shown = len(timeline.Edges)
+	}
+	tr.timelineHeading(shown)
+	for _, edge := range timeline.Edges {
+		if edge != nil && edge.Node != nil {
+			if err := tr.entry(edge.Node); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"simple/types"
+)
+
+// transcript prints a thread and its timeline as plain text or as a markdown
+// transcript that can be pasted into an incident doc
+type transcript struct {
+	w        io.Writer
+	markdown bool
+}
+
+// details prints the thread's details
+func (tr *transcript) details(thread *types.Thread) {
+	fields := [][2]string{
+		{"ID", thread.ID},
+		{"Title", thread.Title},
+		{"Status", thread.Status},
+		{"Priority", priorityToString(thread.Priority)},
+	}
+	if thread.Customer != nil {
+		fields = append(fields, [2]string{"Customer", fmt.Sprintf("%s (%s)", thread.Customer.FullName, thread.Customer.GetEmail())})
+	}
+	if created := tr.timestamp(thread.CreatedAt, "2006-01-02 15:04:05"); created != "" {
+		fields = append(fields, [2]string{"Created", created})
+	}
+	if updated := tr.timestamp(thread.UpdatedAt, "2006-01-02 15:04:05"); updated != "" {
+		fields = append(fields, [2]string{"Updated", updated})
+	}
+
+	if tr.markdown {
+		fmt.Fprintf(tr.w, "# %s\n\n", thread.Title)
+		for _, field := range fields {
+			// The title is the heading
+			if field[0] != "Title" {
+				fmt.Fprintf(tr.w, "- **%s:** %s\n", field[0], field[1])
+			}
+		}
+		return
+	}
+
+	fmt.Fprintf(tr.w, "Thread Details:\n")
+	for _, field := range fields {
+		fmt.Fprintf(tr.w, "  %s: %s\n", field[0], field[1])
+	}
+}
+
+// timelineHeading starts the timeline section, noting when only the latest entries are shown
+func (tr *transcript) timelineHeading(shown int) {
+	if tr.markdown {
+		fmt.Fprintf(tr.w, "\n## Timeline\n\n")
+		if shown > 0 {
+			fmt.Fprintf(tr.w, "_Showing the latest %d entries._\n\n", shown)
+		}
+		return
+	}
+
+	fmt.Fprintf(tr.w, "\nTimeline:\n")
+	if shown > 0 {
+		fmt.Fprintf(tr.w, "  (showing the latest %d entries, use --all for the full timeline)\n", shown)
+	}
+}
+
+// entry prints a single timeline entry
+func (tr *transcript) entry(entry *types.TimelineEntry) error {
+	if tr.markdown {
+		tr.markdownEntry(entry)
+	} else {
+		tr.textEntry(entry)
+	}
+	return nil
+}
+
+// textEntry prints an entry header line with its content indented below it
+func (tr *transcript) textEntry(entry *types.TimelineEntry) {
+	timestamp := tr.timestamp(entry.Timestamp, "2006-01-02 15:04")
+
+	if !entry.IsMessage() {
+		fmt.Fprintf(tr.w, "  %s  %s  %s\n", timestamp, entry.ActorName(), entry.Text())
+		return
+	}
+
+	fmt.Fprintf(tr.w, "  %s  %s  [%s]\n", timestamp, entry.ActorName(), entry.Kind())
+	if email, ok := entry.Entry.(*types.EmailEntry); ok {
+		fmt.Fprintf(tr.w, "    From: %s\n", email.From)
+		fmt.Fprintf(tr.w, "    To: %s\n", email.To)
+	}
+	for _, line := range contentLines(entry.Text()) {
+		fmt.Fprintf(tr.w, "    %s\n", line)
+	}
+	for _, attachment := range entry.Attachments() {
+		fmt.Fprintf(tr.w, "    Attachment: %s (%s, %s) [%s]\n", attachment.FileName, attachment.Size(), attachment.FileMimeType, attachment.ID)
+	}
+	fmt.Fprintln(tr.w)
+}
+
+// markdownEntry prints messages as sections with quoted content and events as single lines
+func (tr *transcript) markdownEntry(entry *types.TimelineEntry) {
+	timestamp := tr.timestamp(entry.Timestamp, "2006-01-02 15:04 MST")
+
+	if !entry.IsMessage() {
+		fmt.Fprintf(tr.w, "- _%s — %s: %s_\n\n", timestamp, entry.ActorName(), entry.Text())
+		return
+	}
+
+	fmt.Fprintf(tr.w, "### %s — %s (%s)\n\n", timestamp, entry.ActorName(), entry.Kind())
+	if entry.IsInternal() {
+		fmt.Fprintf(tr.w, "_Internal, not visible to the customer._\n\n")
+	}
+	if email, ok := entry.Entry.(*types.EmailEntry); ok {
+		fmt.Fprintf(tr.w, "**From:** %s  \n**To:** %s\n\n", email.From, email.To)
+	}
+	lines := contentLines(entry.Text())
+	if len(lines) == 0 {
+		lines = []string{"_(no content)_"}
+	}
+	for _, line := range lines {
+		fmt.Fprintf(tr.w, "> %s\n", line)
+	}
+	if attachments := entry.Attachments(); len(attachments) > 0 {
+		fmt.Fprintln(tr.w)
+		for _, attachment := range attachments {
+			fmt.Fprintf(tr.w, "- 📎 %s (%s, %s)\n", attachment.FileName, attachment.Size(), attachment.FileMimeType)
+		}
+	}
+	fmt.Fprintln(tr.w)
+}
+
+// timestamp formats a timestamp, in UTC for markdown transcripts so they read the same for everyone
+func (tr *transcript) timestamp(dt *types.DateTime, layout string) string {
+	if dt == nil {
+		return ""
+	}
+	t, err := dt.Time()
+	if err != nil {
+		return dt.String()
+	}
+	if tr.markdown {
+		t = t.UTC()
+	}
+	return t.Format(layout)
+}
+
+// contentLines splits message content into lines, dropping surrounding blank lines
+func contentLines(text string) []string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -6,7 +6,6 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
-	"simple/client"
 	"simple/config"
 	"simple/ui"
 )
@@ -26,14 +25,21 @@ func (t *TUICmd) Run(cfg *config.Config) error {
 		defer f.Close()
 	}
 
-	// Create Plain API client
-	client := client.NewPlainClient(cfg)
+	if err := ui.UseTheme(cfg); err != nil {
+		return fmt.Errorf("failed to load theme: %w", err)
+	}
+
+	// Read threads from Plain, or from the local store when offline
+	source, err := newDataSource(cfg)
+	if err != nil {
+		return err
+	}
 
 	// Create the main model
-	model := ui.NewMainModel(cfg, client)
+	model := ui.NewMainModel(cfg, source)
 
 	// Create and run the program
 	program := tea.NewProgram(model, tea.WithAltScreen())
-	_, err := program.Run()
+	_, err = program.Run()
 	return err
 }
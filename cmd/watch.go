@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"simple/client"
+	"simple/config"
+	"simple/query"
+	"simple/watch"
+)
+
+// WatchCmd streams thread changes as NDJSON
+type WatchCmd struct {
+	Filter   string        `help:"Only report threads matching a query, e.g. 'status:todo label:bug'" short:"q" optional:""`
+	Since    string        `help:"Report changes after this time (e.g. 1h, 7d, 2024-01-31 or an RFC3339 timestamp); defaults to now, or to where the state file left off" optional:""`
+	State    string        `help:"File to remember progress in, so a restarted watch resumes where it stopped" type:"path" optional:""`
+	Interval time.Duration `help:"How often to poll Plain" default:"30s"`
+}
+
+// Run executes the watch command
+func (w *WatchCmd) Run(cfg *config.Config) error {
+	if w.Interval < config.MinRefreshInterval {
+		return fmt.Errorf("--interval must be at least %s", config.MinRefreshInterval)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	plainClient := client.NewPlainClient(cfg)
+	now := time.Now()
+
+	var compiled *query.Compiled
+	if w.Filter != "" {
+		var err error
+		compiled, err = query.ParseAndCompile(w.Filter, now)
+		if err != nil {
+			var parseErr *query.ParseError
+			if errors.As(err, &parseErr) {
+				fmt.Fprintln(os.Stderr, parseErr.Caret())
+			}
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+		if err := compiled.ResolveLabels(ctx, client.NewLabelCache(plainClient)); err != nil {
+			return err
+		}
+	}
+
+	state, err := w.loadState(now)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	errorf := func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, "simple watch: "+format+"\n", args...)
+	}
+
+	watcher := watch.NewWatcher(plainClient, compiled, w.Interval, state, w.State, func(event watch.Event) error {
+		return encoder.Encode(event)
+	}, errorf)
+	return watcher.Run(ctx)
+}
+
+// loadState resumes from the state file, unless --since asks for another start
+func (w *WatchCmd) loadState(now time.Time) (*watch.State, error) {
+	var state *watch.State
+	if w.State != "" {
+		var err error
+		if state, err = watch.LoadState(w.State); err != nil {
+			return nil, err
+		}
+	}
+	if state == nil {
+		state = watch.NewState(now)
+	}
+
+	if w.Since != "" {
+		since, err := query.ParseTime(w.Since, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --since: %w", err)
+		}
+		state.Since = since
+	}
+	return state, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,6 +13,11 @@ import (
 type Config struct {
 	Plain PlainConfig `yaml:"plain"`
 	UI    UIConfig    `yaml:"ui"`
+	DB    DBConfig    `yaml:"db"`
+	// Webhooks configures the receiver for Plain webhook deliveries
+	Webhooks WebhookConfig `yaml:"webhooks,omitempty"`
+	// Offline reads threads from the local store instead of Plain, set by --offline
+	Offline bool `yaml:"-"`
 }
 
 // PlainConfig contains Plain API configuration
@@ -26,25 +32,84 @@ type UIConfig struct {
 	Theme     string `yaml:"theme" kong:"default:default"`
 	PageSize  int    `yaml:"page_size" kong:"default:20"`
 	ShowDebug bool   `yaml:"show_debug" kong:"default:false"`
+	// RefreshInterval reloads the thread list and dashboard in the background, e.g. "1m"
+	RefreshInterval time.Duration `yaml:"refresh_interval,omitempty"`
+	// Notifications alerts about new threads and replies found while polling
+	Notifications NotificationConfig `yaml:"notifications,omitempty"`
+	// Keys maps action names to the keys bound to them, e.g. note: ["n", "ctrl+n"]
+	Keys map[string][]string `yaml:"keys,omitempty"`
+	// Themes defines custom themes that can be selected with Theme
+	Themes map[string]ThemeConfig `yaml:"themes,omitempty"`
+	// Dashboard configures the widgets shown on the dashboard
+	Dashboard DashboardConfig `yaml:"dashboard,omitempty"`
 }
 
-// DBConfig contains database configuration. Currently only SQLite is supported.
+// ThemeConfig defines a custom theme on top of a built-in or another custom theme
+type ThemeConfig struct {
+	Extends  string            `yaml:"extends,omitempty"`
+	Markdown string            `yaml:"markdown,omitempty"`
+	Colors   map[string]string `yaml:"colors,omitempty"`
+}
+
+// NotificationConfig configures the alerts shown for new TODO threads and for
+// customer replies on threads assigned to you. They are checked every
+// ui.refresh_interval.
+type NotificationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Bell rings the terminal bell with each notification
+	Bell bool `yaml:"bell"`
+	// Me is your Plain user ID or email. Defaults to the user of the API key.
+	Me string `yaml:"me,omitempty"`
+	// Hook is a shell command run for each notification with the thread JSON on stdin
+	Hook string `yaml:"hook,omitempty"`
+}
+
+// DBConfig contains the local thread store configuration, written by the report
+// command and read by the dashboard charts, search and offline mode. Source is a
+// file path for SQLite and a DSN for Postgres; SQLITE_DB_PATH, which report used
+// before, still sets it for SQLite.
 type DBConfig struct {
 	Driver string `yaml:"driver" kong:"default:sqlite" kong:"env:SIMPLE_DB_DRIVER"`
-	Source string `yaml:"source" kong:"default:sqlite.db" kong:"env:SIMPLE_DB_SOURCE"`
+	Source string `yaml:"source,omitempty" kong:"env:SIMPLE_DB_SOURCE"`
 }
 
-// Validate validates the configuration
+// Validate validates the configuration, apart from the Plain settings checked by ValidatePlain
 func (c *Config) Validate() error {
+	if c.UI.PageSize <= 0 {
+		return fmt.Errorf("UI page size must be positive")
+	}
+	if c.UI.RefreshInterval < 0 || (c.UI.RefreshInterval > 0 && c.UI.RefreshInterval < MinRefreshInterval) {
+		return fmt.Errorf("ui.refresh_interval must be at least %s", MinRefreshInterval)
+	}
+	if c.UI.Notifications.Enabled && c.UI.RefreshInterval == 0 {
+		return fmt.Errorf("ui.notifications need ui.refresh_interval to be set")
+	}
+	if c.DB.Driver != "sqlite" && c.DB.Driver != "postgres" {
+		return fmt.Errorf("db.driver must be sqlite or postgres")
+	}
+	if err := c.UI.validateKeys(); err != nil {
+		return err
+	}
+	if err := c.UI.validateThemes(); err != nil {
+		return err
+	}
+	if err := c.UI.Dashboard.validate(); err != nil {
+		return err
+	}
+	if err := c.Webhooks.validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidatePlain checks the settings needed to call the Plain API
+func (c *Config) ValidatePlain() error {
 	if c.Plain.APIKey == "" {
 		return fmt.Errorf("Plain API key is required (set PLAIN_API_KEY environment variable or configure in config file)")
 	}
 	if c.Plain.Endpoint == "" {
 		return fmt.Errorf("Plain API endpoint is required")
 	}
-	if c.UI.PageSize <= 0 {
-		return fmt.Errorf("UI page size must be positive")
-	}
 	return nil
 }
 
@@ -66,6 +131,32 @@ func GetConfigPath() (string, error) {
 	return configPath, nil
 }
 
+// GetCachePath returns the path to a file in the local cache directory
+func GetCachePath(name string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	cacheDir := filepath.Join(homeDir, ".simple", "cache")
+
+	// Create cache directory if it doesn't exist
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return filepath.Join(cacheDir, name), nil
+}
+
+// DefaultDBPath returns the path of the local SQLite thread store
+func DefaultDBPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".simple", "plain.db"), nil
+}
+
 // Load loads configuration from file
 func Load(configPath string) (*Config, error) {
 	// Default configuration
@@ -74,9 +165,13 @@ func Load(configPath string) (*Config, error) {
 			Endpoint: "https://core-api.uk.plain.com/graphql/v1",
 		},
 		UI: UIConfig{
-			Theme:     "default",
-			PageSize:  20,
-			ShowDebug: false,
+			Theme:         "default",
+			PageSize:      20,
+			ShowDebug:     false,
+			Notifications: NotificationConfig{Bell: true},
+		},
+		DB: DBConfig{
+			Driver: "sqlite",
 		},
 	}
 
@@ -97,6 +192,26 @@ func Load(configPath string) (*Config, error) {
 		}
 	}
 
+	if secret := os.Getenv("PLAIN_WEBHOOK_SECRET"); secret != "" {
+		cfg.Webhooks.Secret = secret
+	}
+	if driver := os.Getenv("SIMPLE_DB_DRIVER"); driver != "" {
+		cfg.DB.Driver = driver
+	}
+	if source := os.Getenv("SIMPLE_DB_SOURCE"); source != "" {
+		cfg.DB.Source = source
+	} else if source := os.Getenv("SQLITE_DB_PATH"); source != "" && cfg.DB.Source == "" {
+		// Older setups pointed the report command at the database this way
+		cfg.DB.Source = source
+	}
+	if cfg.DB.Source == "" && cfg.DB.Driver == "sqlite" {
+		path, err := DefaultDBPath()
+		if err != nil {
+			return nil, err
+		}
+		cfg.DB.Source = path
+	}
+
 	return cfg, nil
 }
 
@@ -127,6 +242,9 @@ func CreateDefaultConfig(configPath string) error {
 			PageSize:  20,
 			ShowDebug: false,
 		},
+		DB: DBConfig{
+			Driver: "sqlite",
+		},
 	}
 
 	return cfg.Save(configPath)
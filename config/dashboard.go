@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Dashboard widget types
+const (
+	WidgetStatus       = "status"        // threads with a status, e.g. TODO
+	WidgetCreatedToday = "created_today" // threads created today
+	WidgetUnassigned   = "unassigned"    // open threads without an assignee
+	WidgetQuery        = "query"         // threads matching a filter query
+	WidgetLabel        = "label"         // threads with a label
+	WidgetAssigneeLoad = "assignee_load" // open threads per assignee
+	WidgetOldestOpen   = "oldest_open"   // age of the oldest open thread
+	WidgetTopCompanies = "top_companies" // companies with the most open threads
+	WidgetCreatedChart = "created_chart" // threads created per day
+	WidgetDoneChart    = "done_chart"    // threads done per day
+	WidgetBacklogChart = "backlog_chart" // open threads at the end of each day
+)
+
+// Chart styles
+const (
+	ChartSparkline = "sparkline"
+	ChartBars      = "bars"
+)
+
+// DefaultChartDays is the number of days shown by chart widgets
+const DefaultChartDays = 14
+
+// MinRefreshInterval is the shortest interval views and widgets may poll Plain at
+const MinRefreshInterval = 10 * time.Second
+
+// DashboardConfig contains the dashboard layout. Without widgets the default
+// dashboard is shown.
+type DashboardConfig struct {
+	Columns int            `yaml:"columns,omitempty"`
+	Widgets []WidgetConfig `yaml:"widgets,omitempty"`
+}
+
+// WidgetConfig configures a single dashboard widget
+type WidgetConfig struct {
+	Type  string `yaml:"type"`
+	Title string `yaml:"title,omitempty"`
+	// Query narrows the threads the widget looks at, in the threads list query syntax
+	Query string `yaml:"query,omitempty"`
+	// Status is the thread status counted by status widgets
+	Status string `yaml:"status,omitempty"`
+	// Label is the label name counted by label widgets
+	Label string `yaml:"label,omitempty"`
+	// Limit is the number of rows shown by assignee_load and top_companies
+	Limit int `yaml:"limit,omitempty"`
+	// Days is the number of days shown by chart widgets
+	Days int `yaml:"days,omitempty"`
+	// Chart is the chart style, sparkline or bars
+	Chart string `yaml:"chart,omitempty"`
+	// Refresh reloads the widget on an interval, e.g. "5m"
+	Refresh time.Duration `yaml:"refresh,omitempty"`
+	// Row and Column place the widget in the grid, starting at 1. Widgets
+	// without a position fill the first free cells in order.
+	Row    int `yaml:"row,omitempty"`
+	Column int `yaml:"column,omitempty"`
+}
+
+// widgetTypes lists the known widget types
+var widgetTypes = map[string]bool{
+	WidgetStatus:       true,
+	WidgetCreatedToday: true,
+	WidgetUnassigned:   true,
+	WidgetQuery:        true,
+	WidgetLabel:        true,
+	WidgetAssigneeLoad: true,
+	WidgetOldestOpen:   true,
+	WidgetTopCompanies: true,
+	WidgetCreatedChart: true,
+	WidgetDoneChart:    true,
+	WidgetBacklogChart: true,
+}
+
+// DefaultDashboardColumns is the number of widgets per dashboard row
+const DefaultDashboardColumns = 2
+
+// GridColumns returns the number of widgets per dashboard row
+func (d DashboardConfig) GridColumns() int {
+	if d.Columns <= 0 {
+		return DefaultDashboardColumns
+	}
+	return d.Columns
+}
+
+// validate checks the widget types, required fields and grid positions
+func (d DashboardConfig) validate() error {
+	if d.Columns < 0 {
+		return fmt.Errorf("ui.dashboard.columns must be positive")
+	}
+
+	taken := make(map[[2]int]int)
+	for i, widget := range d.Widgets {
+		name := fmt.Sprintf("ui.dashboard.widgets[%d]", i)
+		if !widgetTypes[widget.Type] {
+			return fmt.Errorf("%s: unknown widget type %q", name, widget.Type)
+		}
+		switch widget.Type {
+		case WidgetStatus:
+			if widget.Status == "" {
+				return fmt.Errorf("%s: status widgets need a status", name)
+			}
+		case WidgetLabel:
+			if widget.Label == "" {
+				return fmt.Errorf("%s: label widgets need a label", name)
+			}
+		case WidgetQuery:
+			if widget.Query == "" {
+				return fmt.Errorf("%s: query widgets need a query", name)
+			}
+		}
+		if widget.Chart != "" && widget.Chart != ChartSparkline && widget.Chart != ChartBars {
+			return fmt.Errorf("%s: chart must be %s or %s", name, ChartSparkline, ChartBars)
+		}
+		if widget.Days < 0 || widget.Days > 90 {
+			return fmt.Errorf("%s: days must be between 1 and 90", name)
+		}
+		if widget.Refresh < 0 || (widget.Refresh > 0 && widget.Refresh < MinRefreshInterval) {
+			return fmt.Errorf("%s: refresh must be at least %s", name, MinRefreshInterval)
+		}
+		if widget.Row < 0 || widget.Column < 0 || widget.Column > d.GridColumns() {
+			return fmt.Errorf("%s: column must be between 1 and %d", name, d.GridColumns())
+		}
+		if (widget.Row == 0) != (widget.Column == 0) {
+			return fmt.Errorf("%s: set both row and column, or neither", name)
+		}
+		if widget.Row > 0 {
+			cell := [2]int{widget.Row, widget.Column}
+			if other, ok := taken[cell]; ok {
+				return fmt.Errorf("%s: row %d, column %d is already used by widget %d", name, widget.Row, widget.Column, other)
+			}
+			taken[cell] = i
+		}
+	}
+
+	return nil
+}
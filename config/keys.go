@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Key actions that can be rebound in the ui.keys section of the config file
+const (
+	KeyQuit          = "quit"
+	KeyDashboard     = "dashboard"
+	KeyThreads       = "threads"
+	KeyRefresh       = "refresh"
+	KeyOpen          = "open"
+	KeyBack          = "back"
+	KeyFilterTODO    = "filter_todo"
+	KeyFilterSnoozed = "filter_snoozed"
+	KeyFilterAll     = "filter_all"
+	KeyFilterLabels  = "filter_labels"
+	KeyQuery         = "query"
+	KeySearch        = "search"
+	KeyLabelThread   = "label_thread"
+	KeyNextPage      = "next_page"
+	KeyOpenBrowser   = "open_browser"
+	KeyEditLabels    = "edit_labels"
+	KeyNote          = "note"
+	KeyExpandQuoted  = "expand_quoted"
+	KeyCustomer      = "customer"
+	// Keys of the text prompts and note editor
+	KeySubmit         = "submit"
+	KeyCancel         = "cancel"
+	KeyToggle         = "toggle"
+	KeySelectPrevious = "select_previous"
+	KeySelectNext     = "select_next"
+	KeySaveNote       = "save_note"
+)
+
+// Key scopes group the actions that are active at the same time. Global actions
+// are active everywhere except in text inputs, which get every key, so they may
+// not share a key with any other action outside of them.
+const (
+	keyScopeGlobal    = "global"
+	keyScopeList      = "list"
+	keyScopeDetail    = "detail"
+	keyScopeDashboard = "dashboard"
+	keyScopeCustomer  = "customer"
+	keyScopeSearch    = "search"
+	// keyScopePrompt is the query prompt, label picker and search input
+	keyScopePrompt = "prompt"
+	keyScopeNote   = "note"
+)
+
+// inputScopes are the scopes of text inputs, where global actions don't apply
+var inputScopes = map[string]bool{keyScopePrompt: true, keyScopeNote: true}
+
+// listKeys and viewportKeys are the keys the list and viewport components scroll
+// and page with. They are reserved in the scopes using those components, see builtinKeys.
+var (
+	listKeys     = []string{"up", "k", "down", "j", "left", "h", "right", "l", "pgup", "pgdown", "b", "f", "u", "d", "home", "g", "end", "G"}
+	viewportKeys = []string{"up", "k", "down", "j", "pgup", "pgdown", " ", "b", "f", "u", "d", "ctrl+u", "ctrl+d"}
+)
+
+// builtinKeys are the keys handled by the components of each scope. Actions are
+// matched first, so configured keys may not take them over; a few defaults,
+// such as d for the dashboard, deliberately do.
+var builtinKeys = map[string][]string{
+	keyScopeList:     listKeys,
+	keyScopeSearch:   listKeys,
+	keyScopeCustomer: listKeys,
+	keyScopeDetail:   viewportKeys,
+}
+
+// keyAction describes a bindable action and where it applies
+type keyAction struct {
+	name     string
+	scopes   []string
+	defaults []string
+}
+
+// keyActions lists every bindable action with its default keys
+var keyActions = []keyAction{
+	{KeyQuit, []string{keyScopeGlobal}, []string{"q", "ctrl+c"}},
+	{KeyDashboard, []string{keyScopeGlobal}, []string{"d"}},
+	{KeyThreads, []string{keyScopeGlobal}, []string{"t"}},
+	{KeyRefresh, []string{keyScopeList, keyScopeDashboard, keyScopeCustomer}, []string{"r"}},
+	{KeyOpen, []string{keyScopeList, keyScopeCustomer, keyScopeSearch}, []string{"enter"}},
+	{KeyBack, []string{keyScopeDetail, keyScopeCustomer, keyScopeSearch}, []string{"esc"}},
+	{KeyFilterTODO, []string{keyScopeList}, []string{"1"}},
+	{KeyFilterSnoozed, []string{keyScopeList}, []string{"2"}},
+	{KeyFilterAll, []string{keyScopeList}, []string{"3"}},
+	{KeyFilterLabels, []string{keyScopeList}, []string{"l"}},
+	{KeyQuery, []string{keyScopeList}, []string{"/"}},
+	{KeySearch, []string{keyScopeList, keyScopeSearch}, []string{"s"}},
+	{KeyLabelThread, []string{keyScopeList}, []string{"L"}},
+	{KeyNextPage, []string{keyScopeList, keyScopeSearch}, []string{"n"}},
+	{KeyOpenBrowser, []string{keyScopeDetail}, []string{"b"}},
+	{KeyEditLabels, []string{keyScopeDetail}, []string{"l"}},
+	{KeyNote, []string{keyScopeDetail}, []string{"N"}},
+	{KeyExpandQuoted, []string{keyScopeDetail}, []string{"e"}},
+	{KeyCustomer, []string{keyScopeDetail}, []string{"c"}},
+	{KeySubmit, []string{keyScopePrompt}, []string{"enter"}},
+	{KeyCancel, []string{keyScopePrompt, keyScopeNote}, []string{"esc", "ctrl+c"}},
+	{KeyToggle, []string{keyScopePrompt}, []string{"tab"}},
+	{KeySelectPrevious, []string{keyScopePrompt}, []string{"up", "ctrl+p"}},
+	{KeySelectNext, []string{keyScopePrompt}, []string{"down", "ctrl+n"}},
+	{KeySaveNote, []string{keyScopeNote}, []string{"ctrl+s"}},
+}
+
+// KeyBindings returns the keys bound to every action: the defaults, overridden
+// by the actions configured in ui.keys
+func (u UIConfig) KeyBindings() map[string][]string {
+	bindings := make(map[string][]string, len(keyActions))
+	for _, action := range keyActions {
+		bindings[action.name] = action.defaults
+		if keys, ok := u.Keys[action.name]; ok {
+			bindings[action.name] = keys
+		}
+	}
+	return bindings
+}
+
+// validateKeys checks ui.keys for unknown actions, empty bindings, keys bound
+// to two actions that are active at the same time and keys that would take
+// over scrolling or paging
+func (u UIConfig) validateKeys() error {
+	known := make(map[string]bool, len(keyActions))
+	for _, action := range keyActions {
+		known[action.name] = true
+	}
+
+	var unknown []string
+	for name, keys := range u.Keys {
+		if !known[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("ui.keys.%s must have at least one key", name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown action(s) in ui.keys: %s", strings.Join(unknown, ", "))
+	}
+
+	bindings := u.KeyBindings()
+	var conflicts []string
+	for i, a := range keyActions {
+		for _, b := range keyActions[i+1:] {
+			if !scopesOverlap(a.scopes, b.scopes) {
+				continue
+			}
+			for _, key := range bindings[a.name] {
+				for _, other := range bindings[b.name] {
+					if key == other {
+						conflicts = append(conflicts, fmt.Sprintf("%q is bound to both %s and %s", key, a.name, b.name))
+					}
+				}
+			}
+		}
+	}
+	for _, action := range keyActions {
+		for _, key := range u.Keys[action.name] {
+			if containsKey(action.defaults, key) {
+				continue
+			}
+			if scope := builtinScope(action.scopes, key); scope != "" {
+				conflicts = append(conflicts, fmt.Sprintf("%q of %s is used for scrolling in the %s view", key, action.name, scope))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("conflicting key bindings in ui.keys: %s", strings.Join(conflicts, "; "))
+	}
+
+	return nil
+}
+
+// builtinScope returns the first scope among scopes that handles key itself, or ""
+func builtinScope(scopes []string, key string) string {
+	for _, scope := range []string{keyScopeList, keyScopeDetail, keyScopeCustomer, keyScopeSearch} {
+		for _, s := range scopes {
+			if (s == scope || s == keyScopeGlobal) && containsKey(builtinKeys[scope], key) {
+				return scope
+			}
+		}
+	}
+	return ""
+}
+
+// containsKey returns true if key is one of keys
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// scopesOverlap returns true if actions in the two scope lists can be active at the same time
+func scopesOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y || (x == keyScopeGlobal && !inputScopes[y]) || (y == keyScopeGlobal && !inputScopes[x]) {
+				return true
+			}
+		}
+	}
+	return false
+}
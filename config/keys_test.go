@@ -0,0 +1,70 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    map[string][]string
+		wantErr string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "rebind",
+			keys: map[string][]string{KeyNote: {"n", "ctrl+n"}},
+		},
+		{
+			name: "same key in separate views",
+			keys: map[string][]string{KeyOpenBrowser: {"o"}, KeyQuery: {"o"}},
+		},
+		{
+			name:    "conflict in one view",
+			keys:    map[string][]string{KeyNextPage: {"r"}},
+			wantErr: `"r" is bound to both refresh and next_page`,
+		},
+		{
+			name:    "conflict with global action",
+			keys:    map[string][]string{KeyNote: {"d"}},
+			wantErr: `"d" is bound to both dashboard and note`,
+		},
+		{
+			name:    "shadows list paging",
+			keys:    map[string][]string{KeyNextPage: {"b"}},
+			wantErr: `"b" of next_page is used for scrolling in the list view`,
+		},
+		{
+			name: "default that shadows list paging",
+			keys: map[string][]string{KeyFilterLabels: {"l", "ctrl+l"}},
+		},
+		{
+			name:    "unknown action",
+			keys:    map[string][]string{"explode": {"x"}},
+			wantErr: "unknown action(s) in ui.keys: explode",
+		},
+		{
+			name:    "empty binding",
+			keys:    map[string][]string{KeyQuit: {}},
+			wantErr: "ui.keys.quit must have at least one key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UIConfig{Keys: tt.keys}.validateKeys()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
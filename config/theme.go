@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// BuiltinThemes are the themes the terminal UI provides
+var BuiltinThemes = []string{"default", "dark", "light", "high-contrast", "no-color"}
+
+// ThemeColors are the colors a theme in ui.themes may set
+var ThemeColors = []string{
+	"title", "heading", "label", "text", "muted", "highlight", "value", "loading", "error", "note", "attachment",
+	"selected_fg", "selected_bg",
+	"status_todo", "status_snoozed", "status_open", "status_pending", "status_done", "status_other",
+	"priority_urgent", "priority_high", "priority_medium", "priority_low", "priority_none",
+	"card_todo", "card_snoozed", "card_created", "card_unassigned", "card_other",
+}
+
+// validateThemes checks that ui.theme names a theme, and that every theme in
+// ui.themes extends a theme that exists without a cycle and only sets known colors
+func (u UIConfig) validateThemes() error {
+	if u.Theme != "" && !u.hasTheme(u.Theme) {
+		return fmt.Errorf("ui.theme: unknown theme %q (available: %s)", u.Theme, strings.Join(u.themeNames(), ", "))
+	}
+
+	names := make([]string, 0, len(u.Themes))
+	for name := range u.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		custom := u.Themes[name]
+		if custom.Markdown != "" {
+			if _, ok := styles.DefaultStyles[custom.Markdown]; !ok {
+				return fmt.Errorf("ui.themes.%s: unknown markdown style %q", name, custom.Markdown)
+			}
+		}
+		for color := range custom.Colors {
+			if !containsKey(ThemeColors, color) {
+				return fmt.Errorf("ui.themes.%s: unknown color %q", name, color)
+			}
+		}
+		if err := u.checkExtends(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkExtends follows a theme's extends chain down to a built-in theme. A theme
+// may extend the built-in theme it overrides.
+func (u UIConfig) checkExtends(name string) error {
+	seen := []string{name}
+	for current := name; ; {
+		base := u.Themes[current].Extends
+		if base == "" {
+			base = "default"
+		}
+		if base == current {
+			if !containsKey(BuiltinThemes, base) {
+				return fmt.Errorf("ui.themes.%s: theme %q extends itself", name, current)
+			}
+			return nil
+		}
+		if _, ok := u.Themes[base]; !ok {
+			if !containsKey(BuiltinThemes, base) {
+				return fmt.Errorf("ui.themes.%s: %q extends unknown theme %q", name, current, base)
+			}
+			return nil
+		}
+		if containsKey(seen, base) {
+			return fmt.Errorf("ui.themes.%s: themes extend each other (%s)", name, strings.Join(append(seen, base), " -> "))
+		}
+		seen = append(seen, base)
+		current = base
+	}
+}
+
+// hasTheme returns true if name is a built-in or user-defined theme
+func (u UIConfig) hasTheme(name string) bool {
+	_, ok := u.Themes[name]
+	return ok || containsKey(BuiltinThemes, name)
+}
+
+// themeNames lists the built-in and user-defined theme names
+func (u UIConfig) themeNames() []string {
+	names := append([]string(nil), BuiltinThemes...)
+	for name := range u.Themes {
+		if !containsKey(BuiltinThemes, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateThemes(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		themes  map[string]ThemeConfig
+		wantErr string
+	}{
+		{name: "built-in", theme: "dark"},
+		{name: "chain", theme: "night", themes: map[string]ThemeConfig{
+			"solarized": {Extends: "light"},
+			"night":     {Extends: "solarized", Markdown: "dark"},
+			"default":   {Extends: "default"},
+		}},
+		{name: "unknown theme", theme: "missing", wantErr: `ui.theme: unknown theme "missing"`},
+		{name: "unknown base", themes: map[string]ThemeConfig{"mine": {Extends: "nope"}}, wantErr: `"mine" extends unknown theme "nope"`},
+		{name: "cycle", themes: map[string]ThemeConfig{"a": {Extends: "b"}, "b": {Extends: "a"}}, wantErr: "themes extend each other (a -> b -> a)"},
+		{name: "extends itself", themes: map[string]ThemeConfig{"loop": {Extends: "loop"}}, wantErr: `theme "loop" extends itself`},
+		{name: "unknown color", themes: map[string]ThemeConfig{"mine": {Colors: map[string]string{"sparkle": "1"}}}, wantErr: `unknown color "sparkle"`},
+		{name: "unknown markdown style", themes: map[string]ThemeConfig{"mine": {Markdown: "neon"}}, wantErr: `unknown markdown style "neon"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UIConfig{Theme: tt.theme, Themes: tt.themes}.validateThemes()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import "fmt"
+
+// Webhook sink types
+const (
+	SinkStdout = "stdout" // each event as a line of JSON on stdout
+	SinkExec   = "exec"   // a shell command run with the event JSON on stdin
+)
+
+// WebhookConfig configures the receiver started by serve webhooks
+type WebhookConfig struct {
+	// Secret is the signing secret of the webhook target in Plain
+	Secret string `yaml:"secret,omitempty" kong:"env:PLAIN_WEBHOOK_SECRET"`
+	// Sinks receive every verified event, after its thread has been stored
+	Sinks []SinkConfig `yaml:"sinks,omitempty"`
+}
+
+// SinkConfig configures where webhook events are sent
+type SinkConfig struct {
+	Type string `yaml:"type"`
+	// Command is the shell command run by exec sinks
+	Command string `yaml:"command,omitempty"`
+	// Events limits the sink to these event types, e.g. thread.thread_created
+	Events []string `yaml:"events,omitempty"`
+}
+
+// validate checks the sinks
+func (w WebhookConfig) validate() error {
+	for i, sink := range w.Sinks {
+		name := fmt.Sprintf("webhooks.sinks[%d]", i)
+		switch sink.Type {
+		case SinkStdout:
+		case SinkExec:
+			if sink.Command == "" {
+				return fmt.Errorf("%s: exec sinks need a command", name)
+			}
+		default:
+			return fmt.Errorf("%s: unknown sink type %q (expected %s or %s)", name, sink.Type, SinkStdout, SinkExec)
+		}
+	}
+	return nil
+}
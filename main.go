@@ -12,15 +12,22 @@ import (
 )
 
 var CLI struct {
-	Config string `help:"Config file path" type:"path" default:"${config_file}"`
+	Config  string `help:"Config file path" type:"path" default:"${config_file}"`
+	Offline bool   `help:"Read threads from the local store instead of Plain"`
 
 	// Commands
 	TUI       cmd.TUICmd       `cmd:"" help:"Launch the terminal UI (default)" default:"1"`
 	Configure cmd.ConfigureCmd `cmd:"" help:"Create default configuration file"`
 
 	// API Commands
-	Threads cmd.ThreadsCmd `cmd:"" help:"Manage threads"`
-	Report  cmd.ReportCmd  `cmd:"" help:"Generate a report of threads"`
+	Threads   cmd.ThreadsCmd   `cmd:"" help:"Manage threads"`
+	Companies cmd.CompaniesCmd `cmd:"" help:"View companies and their support activity"`
+	Tenants   cmd.TenantsCmd   `cmd:"" help:"View tenants"`
+	Labels    cmd.LabelsCmd    `cmd:"" help:"Manage labels"`
+	Report    cmd.ReportCmd    `cmd:"" help:"Generate a report of threads"`
+	Watch     cmd.WatchCmd     `cmd:"" help:"Stream thread changes as NDJSON"`
+	Search    cmd.SearchCmd    `cmd:"" help:"Search synced threads and messages in the local store"`
+	Serve     cmd.ServeCmd     `cmd:"" help:"Run servers, such as the webhook receiver"`
 }
 
 func main() {
@@ -82,11 +89,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	cfg.Offline = CLI.Offline
+	if cfg.Offline && !cmd.SupportsOffline(ctx.Command()) {
+		fmt.Fprintf(os.Stderr, "Error: %s is not available offline\n", ctx.Command())
+		os.Exit(1)
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
 		os.Exit(1)
 	}
+	if !cfg.Offline && cmd.NeedsPlain(ctx.Command()) {
+		if err := cfg.ValidatePlain(); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Run the selected command
 	err = ctx.Run(cfg)
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Exporter keeps Prometheus metrics about the threads up to date
+type Exporter struct {
+	source   Source
+	registry *prometheus.Registry
+	// since is when the counted window for created and done threads starts
+	since time.Time
+	// open has the IDs of the threads open at the last refresh
+	open      map[string]bool
+	truncated bool
+	errorf    func(format string, args ...interface{})
+
+	threads        *prometheus.GaugeVec
+	byPriority     *prometheus.GaugeVec
+	byLabel        *prometheus.GaugeVec
+	byAssignee     *prometheus.GaugeVec
+	oldestTODO     prometheus.Gauge
+	openTruncated  prometheus.Gauge
+	created        prometheus.Counter
+	done           prometheus.Counter
+	lastRefresh    prometheus.Gauge
+	refreshErrors  prometheus.Counter
+	requestLatency *prometheus.HistogramVec
+	requestErrors  *prometheus.CounterVec
+}
+
+// NewExporter creates an exporter refreshing from source. Created and done
+// threads are counted from now on.
+func NewExporter(source Source, errorf func(format string, args ...interface{})) *Exporter {
+	e := &Exporter{
+		source:   source,
+		registry: prometheus.NewRegistry(),
+		since:    time.Now(),
+		errorf:   errorf,
+		threads: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "simple_threads",
+			Help: "Number of threads by status.",
+		}, []string{"status"}),
+		byPriority: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "simple_open_threads_by_priority",
+			Help: "Number of open threads by priority.",
+		}, []string{"priority"}),
+		byLabel: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "simple_open_threads_by_label",
+			Help: "Number of open threads by label.",
+		}, []string{"label"}),
+		byAssignee: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "simple_open_threads_by_assignee",
+			Help: "Number of open threads by assignee.",
+		}, []string{"assignee"}),
+		oldestTODO: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "simple_oldest_todo_thread_age_seconds",
+			Help: "Age of the oldest thread in TODO, 0 when there is none.",
+		}),
+		openTruncated: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "simple_open_threads_truncated",
+			Help: "1 when only some of the open threads were read, so the open thread breakdowns and oldest TODO age are partial.",
+		}),
+		created: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "simple_threads_created_total",
+			Help: "Threads created since the exporter started.",
+		}),
+		done: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "simple_threads_done_total",
+			Help: "Threads moved to done since the exporter started.",
+		}),
+		lastRefresh: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "simple_metrics_last_refresh_timestamp_seconds",
+			Help: "When the thread metrics were last refreshed.",
+		}),
+		refreshErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "simple_metrics_refresh_errors_total",
+			Help: "Refreshes of the thread metrics that failed.",
+		}),
+		requestLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "simple_plain_request_duration_seconds",
+			Help:    "Latency of Plain API requests by GraphQL operation.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+		requestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "simple_plain_request_errors_total",
+			Help: "Failed Plain API requests by GraphQL operation.",
+		}, []string{"operation"}),
+	}
+
+	e.registry.MustRegister(
+		e.threads, e.byPriority, e.byLabel, e.byAssignee, e.oldestTODO, e.openTruncated,
+		e.created, e.done, e.lastRefresh, e.refreshErrors,
+		e.requestLatency, e.requestErrors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return e
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{Registry: e.registry})
+}
+
+// ObserveRequest records a Plain API request. It is a client.RequestObserver.
+func (e *Exporter) ObserveRequest(operation string, duration time.Duration, err error) {
+	e.requestLatency.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		e.requestErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// Run refreshes the metrics now and then every interval until ctx is done
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Refresh(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			e.errorf("failed to refresh metrics: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh takes a snapshot and updates the metrics. A failed refresh leaves the
+// previous values in place and counts the same window again next time.
+func (e *Exporter) Refresh(ctx context.Context, now time.Time) error {
+	snapshot, err := e.source.Snapshot(ctx, e.since, now)
+	if err != nil {
+		e.refreshErrors.Inc()
+		return err
+	}
+
+	// Done threads were closed since the last refresh if they were open then,
+	// or created since. Others were only updated after being closed.
+	done := 0
+	for _, thread := range snapshot.Updated {
+		if e.open[thread.id] || !thread.createdAt.Before(e.since) {
+			done++
+		}
+	}
+	e.since = now
+	e.open = snapshot.Open
+
+	setGauges(e.threads, snapshot.Statuses)
+	setGauges(e.byPriority, snapshot.Priorities)
+	setGauges(e.byLabel, snapshot.Labels)
+	setGauges(e.byAssignee, snapshot.Assignees)
+
+	age := 0.0
+	if !snapshot.OldestTODO.IsZero() {
+		age = now.Sub(snapshot.OldestTODO).Seconds()
+	}
+	e.oldestTODO.Set(age)
+
+	truncated := 0.0
+	if snapshot.Truncated {
+		truncated = 1
+		if !e.truncated {
+			e.errorf("only some of the open threads were read, their breakdowns are partial")
+		}
+	}
+	e.truncated = snapshot.Truncated
+	e.openTruncated.Set(truncated)
+
+	e.created.Add(float64(snapshot.Created))
+	e.done.Add(float64(done))
+	e.lastRefresh.Set(float64(now.Unix()))
+	return nil
+}
+
+// setGauges replaces a gauge vector's values, dropping series that went away
+func setGauges(gauges *prometheus.GaugeVec, counts map[string]int) {
+	gauges.Reset()
+	for value, count := range counts {
+		gauges.WithLabelValues(value).Set(float64(count))
+	}
+}
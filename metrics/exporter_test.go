@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeSource returns its snapshots in order and records the windows asked for
+type fakeSource struct {
+	snapshots []*Snapshot
+	err       error
+	windows   [][2]time.Time
+}
+
+func (s *fakeSource) Snapshot(_ context.Context, since, now time.Time) (*Snapshot, error) {
+	s.windows = append(s.windows, [2]time.Time{since, now})
+	if s.err != nil {
+		return nil, s.err
+	}
+	snapshot := s.snapshots[0]
+	s.snapshots = s.snapshots[1:]
+	return snapshot, nil
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	threads := []openThread{
+		{status: "TODO", priority: 0, labels: []string{"bug", "billing"}, assignee: "Ada", createdAt: now.Add(-2 * time.Hour)},
+		{status: "TODO", priority: 2, labels: []string{"bug"}, createdAt: now.Add(-5 * time.Hour)},
+		// Snoozed threads don't count towards the oldest TODO
+		{status: "SNOOZED", priority: 2, assignee: "Ada", createdAt: now.Add(-48 * time.Hour)},
+	}
+
+	var snapshot Snapshot
+	snapshot.summarize(threads, true, true)
+
+	if got := snapshot.Priorities; got["urgent"] != 1 || got["medium"] != 2 || len(got) != 2 {
+		t.Errorf("Priorities = %v", got)
+	}
+	if got := snapshot.Labels; got["bug"] != 2 || got["billing"] != 1 || len(got) != 2 {
+		t.Errorf("Labels = %v", got)
+	}
+	if got := snapshot.Assignees; got["Ada"] != 2 || got[unassigned] != 1 || len(got) != 2 {
+		t.Errorf("Assignees = %v", got)
+	}
+	if want := now.Add(-5 * time.Hour); !snapshot.OldestTODO.Equal(want) {
+		t.Errorf("OldestTODO = %v, want %v", snapshot.OldestTODO, want)
+	}
+
+	var withoutBreakdowns Snapshot
+	withoutBreakdowns.summarize(threads, false, false)
+	if withoutBreakdowns.Priorities != nil || withoutBreakdowns.Assignees != nil {
+		t.Errorf("summarize without priorities and assignees = %+v", withoutBreakdowns)
+	}
+}
+
+func TestExporterRefresh(t *testing.T) {
+	start := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	source := &fakeSource{snapshots: []*Snapshot{
+		{
+			Statuses:   map[string]int{"TODO": 3, "DONE": 10},
+			Labels:     map[string]int{"bug": 2, "billing": 1},
+			OldestTODO: start.Add(-time.Hour),
+			Open:       map[string]bool{"th_1": true, "th_2": true, "th_3": true},
+			Created:    2,
+			// Nothing is known to have been open before the first refresh
+			Updated: []doneThread{{id: "th_4", createdAt: start.Add(-time.Hour)}},
+		},
+		{
+			Statuses: map[string]int{"TODO": 1, "DONE": 12},
+			Labels:   map[string]int{"bug": 1},
+			Open:     map[string]bool{"th_3": true},
+			Created:  1,
+			Updated: []doneThread{
+				{id: "th_1", createdAt: start.Add(-time.Hour)},
+				{id: "th_2", createdAt: start.Add(-time.Hour)},
+				// Done before, only relabelled since
+				{id: "th_4", createdAt: start.Add(-time.Hour)},
+				// Created and closed between refreshes
+				{id: "th_5", createdAt: start.Add(time.Minute)},
+			},
+			Truncated: true,
+		},
+	}}
+	e := NewExporter(source, func(string, ...interface{}) {})
+	e.since = start.Add(-time.Minute)
+
+	if err := e.Refresh(context.Background(), start); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(e.oldestTODO); got != 3600 {
+		t.Errorf("oldest TODO age = %v, want 3600", got)
+	}
+
+	// A failed refresh keeps the values and asks for the same window again
+	source.err = errors.New("timeout")
+	if err := e.Refresh(context.Background(), start.Add(time.Minute)); err == nil {
+		t.Fatal("Refresh() succeeded with a failing source")
+	}
+	source.err = nil
+	if err := e.Refresh(context.Background(), start.Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if got := source.windows[2][0]; !got.Equal(start) {
+		t.Errorf("window after a failed refresh starts at %v, want %v", got, start)
+	}
+
+	if got := testutil.ToFloat64(e.threads.WithLabelValues("TODO")); got != 1 {
+		t.Errorf("TODO threads = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(e.byLabel); got != 1 {
+		t.Errorf("label series = %d, want 1 after billing went away", got)
+	}
+	if got := testutil.ToFloat64(e.oldestTODO); got != 0 {
+		t.Errorf("oldest TODO age = %v, want 0 without TODO threads", got)
+	}
+	if created, done := testutil.ToFloat64(e.created), testutil.ToFloat64(e.done); created != 3 || done != 3 {
+		t.Errorf("created, done = %v, %v, want 3, 3", created, done)
+	}
+	if got := testutil.ToFloat64(e.openTruncated); got != 1 {
+		t.Errorf("open threads truncated = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(e.refreshErrors); got != 1 {
+		t.Errorf("refresh errors = %v, want 1", got)
+	}
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Snapshot is the state of the threads at one refresh
+type Snapshot struct {
+	// Statuses counts all threads by status
+	Statuses map[string]int
+	// Priorities, Labels and Assignees count open threads. Sources that don't
+	// know a dimension leave it nil.
+	Priorities map[string]int
+	Labels     map[string]int
+	Assignees  map[string]int
+	// OldestTODO is when the oldest TODO thread was created, zero without one
+	OldestTODO time.Time
+	// Open has the IDs of the open threads, so the next refresh can tell which
+	// threads were closed since
+	Open map[string]bool
+	// Truncated is set when only some of the open threads were read, so the
+	// breakdowns and OldestTODO are partial
+	Truncated bool
+	// Created counts the threads created since the last refresh
+	Created int
+	// Updated are the done threads updated since the last refresh, whether or
+	// not they were closed since
+	Updated []doneThread
+}
+
+// Source takes snapshots of the threads
+type Source interface {
+	Snapshot(ctx context.Context, since, now time.Time) (*Snapshot, error)
+}
+
+// openThread is what the breakdowns need to know about an open thread
+type openThread struct {
+	id        string
+	status    string
+	priority  int
+	labels    []string
+	assignee  string
+	createdAt time.Time
+}
+
+// doneThread is a done thread, counted as closed when it was open or didn't exist yet
+type doneThread struct {
+	id        string
+	createdAt time.Time
+}
+
+// unassigned is the assignee label of threads nobody is assigned to
+const unassigned = "unassigned"
+
+// summarize fills in the open thread breakdowns and the oldest TODO thread
+func (s *Snapshot) summarize(threads []openThread, withPriority, withAssignee bool) {
+	s.Labels = make(map[string]int)
+	s.Open = make(map[string]bool, len(threads))
+	if withPriority {
+		s.Priorities = make(map[string]int)
+	}
+	if withAssignee {
+		s.Assignees = make(map[string]int)
+	}
+
+	for _, thread := range threads {
+		s.Open[thread.id] = true
+		if withPriority {
+			s.Priorities[priorityLabel(thread.priority)]++
+		}
+		if withAssignee {
+			assignee := thread.assignee
+			if assignee == "" {
+				assignee = unassigned
+			}
+			s.Assignees[assignee]++
+		}
+		for _, label := range thread.labels {
+			s.Labels[label]++
+		}
+		if thread.status == "TODO" && !thread.createdAt.IsZero() &&
+			(s.OldestTODO.IsZero() || thread.createdAt.Before(s.OldestTODO)) {
+			s.OldestTODO = thread.createdAt
+		}
+	}
+}
+
+// priorityLabel converts a priority number to a metric label value
+func priorityLabel(priority int) string {
+	switch priority {
+	case 0:
+		return "urgent"
+	case 1:
+		return "high"
+	case 2:
+		return "medium"
+	case 3:
+		return "low"
+	default:
+		return fmt.Sprintf("p%d", priority)
+	}
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"simple/client"
+	"simple/store"
+	"simple/types"
+)
+
+// statuses are the thread statuses counted on every refresh
+var statuses = []string{"TODO", "SNOOZED", "DONE"}
+
+const (
+	// openPageSize is the page size used to fetch threads
+	openPageSize = 100
+	// maxOpenPages bounds how many pages of open or done threads each refresh fetches
+	maxOpenPages = 20
+)
+
+// APISource takes snapshots from the Plain API
+type APISource struct {
+	client *client.PlainClient
+}
+
+// NewAPISource creates a source reading from the Plain API
+func NewAPISource(plainClient *client.PlainClient) *APISource {
+	return &APISource{client: plainClient}
+}
+
+// Snapshot counts threads by status and fetches the open threads for the
+// breakdowns, up to maxOpenPages pages of them, and the done threads updated
+// since the last refresh
+func (s *APISource) Snapshot(ctx context.Context, since, now time.Time) (*Snapshot, error) {
+	snapshot := &Snapshot{Statuses: make(map[string]int)}
+	for _, status := range statuses {
+		count, _, err := s.client.CountThreads(ctx, client.ThreadFilter{Statuses: []string{status}})
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Statuses[status] = count
+	}
+
+	open, truncated, err := s.threads(ctx, client.ThreadFilter{Statuses: []string{"TODO", "SNOOZED"}})
+	if err != nil {
+		return nil, err
+	}
+	snapshot.summarize(open, true, true)
+	snapshot.Truncated = truncated
+
+	after, before := since.UTC().Format(time.RFC3339), now.UTC().Format(time.RFC3339)
+	// Without statuses, Plain only counts open threads
+	created := client.ThreadFilter{Statuses: statuses, CreatedAfter: after, CreatedBefore: before}
+	if snapshot.Created, _, err = s.client.CountThreads(ctx, created); err != nil {
+		return nil, err
+	}
+	done, _, err := s.threads(ctx, client.ThreadFilter{Statuses: []string{"DONE"}, UpdatedAfter: after, UpdatedBefore: before})
+	if err != nil {
+		return nil, err
+	}
+	for _, thread := range done {
+		snapshot.Updated = append(snapshot.Updated, doneThread{id: thread.id, createdAt: thread.createdAt})
+	}
+
+	return snapshot, nil
+}
+
+// threads fetches up to maxOpenPages pages of threads matching filter. It
+// returns true if there were more.
+func (s *APISource) threads(ctx context.Context, filter client.ThreadFilter) ([]openThread, bool, error) {
+	var threads []openThread
+	cursor := ""
+	for page := 0; page < maxOpenPages; page++ {
+		conn, err := s.client.GetThreadsByFilter(ctx, filter, openPageSize, cursor)
+		if err != nil {
+			return nil, false, err
+		}
+		if conn == nil {
+			return threads, false, nil
+		}
+		for _, edge := range conn.Edges {
+			if edge.Node != nil {
+				threads = append(threads, fromThread(edge.Node))
+			}
+		}
+		if conn.PageInfo == nil || !conn.PageInfo.HasNextPage {
+			return threads, false, nil
+		}
+		cursor = conn.PageInfo.EndCursor
+	}
+	return threads, true, nil
+}
+
+// fromThread converts a thread from the API
+func fromThread(t *types.Thread) openThread {
+	thread := openThread{id: t.ID, status: t.Status, priority: t.Priority}
+	for _, label := range t.Labels {
+		thread.labels = append(thread.labels, label.LabelType.Name)
+	}
+	if t.AssignedTo != nil {
+		thread.assignee = t.AssignedTo.FullName
+		if thread.assignee == "" {
+			thread.assignee = t.AssignedTo.Email
+		}
+	}
+	if t.CreatedAt != nil {
+		if created, err := t.CreatedAt.Time(); err == nil {
+			thread.createdAt = created
+		}
+	}
+	return thread
+}
+
+// StoreSource takes snapshots from the local store, which doesn't record
+// priorities or assignees
+type StoreSource struct {
+	stats *store.Stats
+}
+
+// NewStoreSource creates a source reading from the local store
+func NewStoreSource(stats *store.Stats) *StoreSource {
+	return &StoreSource{stats: stats}
+}
+
+// Snapshot reads the counts and open threads from the local store
+func (s *StoreSource) Snapshot(_ context.Context, since, now time.Time) (*Snapshot, error) {
+	counts, err := s.stats.CountByStatus()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{Statuses: make(map[string]int)}
+	for _, status := range statuses {
+		snapshot.Statuses[status] = counts[status]
+	}
+
+	rows, err := s.stats.OpenThreads()
+	if err != nil {
+		return nil, err
+	}
+	open := make([]openThread, 0, len(rows))
+	for _, row := range rows {
+		thread := openThread{id: row.ID, status: row.Status, labels: row.LabelNames()}
+		if row.CreatedAt != nil {
+			thread.createdAt = *row.CreatedAt
+		}
+		open = append(open, thread)
+	}
+	snapshot.summarize(open, false, false)
+
+	created, err := s.stats.CreatedPerDay([]time.Time{since, now})
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Created = created[0]
+
+	done, _, err := s.stats.ListThreads(store.ThreadQuery{Statuses: []string{"DONE"}, UpdatedAfter: since, UpdatedBefore: now, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range done {
+		thread := doneThread{id: row.ID}
+		if row.CreatedAt != nil {
+			thread.createdAt = *row.CreatedAt
+		}
+		snapshot.Updated = append(snapshot.Updated, thread)
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,536 @@
+package query
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple/client"
+	"simple/types"
+)
+
+// statuses lists the thread statuses in display order
+var statuses = []string{"TODO", "SNOOZED", "DONE"}
+
+// priorityNames maps priority names to Plain's numeric priorities
+var priorityNames = map[string]int{
+	"urgent": 0,
+	"high":   1,
+	"medium": 2,
+	"normal": 2,
+	"low":    3,
+}
+
+// relativeTime matches relative times such as 12h, 7d or 2w
+var relativeTime = regexp.MustCompile(`^(\d+)([hdw])$`)
+
+// Predicate checks a thread against a term on the client side
+type Predicate func(thread *types.Thread) bool
+
+// Compiled is a query split into a filter for Plain's threads query and client-side
+// predicates. The filter only narrows what is fetched; Match applies every term, so
+// threads must still be checked with Match after fetching.
+type Compiled struct {
+	Filter client.ThreadFilter
+	// LabelNames must be resolved into Filter.LabelTypeIDs before fetching, see ResolveLabels
+	LabelNames []string
+	Predicates []Predicate
+	// clientSide is set when a term can't be expressed exactly in Filter
+	clientSide bool
+}
+
+// Compile compiles a parsed query. Relative dates are resolved against now.
+func (q *Query) Compile(now time.Time) (*Compiled, error) {
+	c := &Compiled{}
+
+	var statusSet, prioritySet map[int]bool
+	var lastStatus, lastPriority Term
+	labelTerms, companyTerms, customerTerms := 0, 0, 0
+
+	for _, term := range q.Terms {
+		var err error
+		switch term.Field {
+		case "":
+			c.addText(term)
+			c.clientSide = true
+		case FieldStatus:
+			statusSet, err = c.addStatus(q, term, statusSet)
+			lastStatus = term
+		case FieldPriority:
+			prioritySet, err = c.addPriority(q, term, prioritySet)
+			lastPriority = term
+		case FieldLabel:
+			c.addLabel(term)
+			// Plain matches any of the label types of a single term
+			labelTerms++
+			c.clientSide = c.clientSide || term.Negated || labelTerms > 1
+		case FieldCompany:
+			c.addCompany(term)
+			// Like labels, Plain matches any of the companies of a single term
+			companyTerms++
+			c.clientSide = c.clientSide || term.Negated || companyTerms > 1 || !allHavePrefix(term.Values(), "co_")
+		case FieldCustomer:
+			c.addCustomer(term)
+			customerTerms++
+			c.clientSide = c.clientSide || term.Negated || customerTerms > 1 || !allHavePrefix(term.Values(), "c_")
+		case FieldAssignee:
+			c.addAssignee(term)
+			c.clientSide = true
+		case FieldCreated, FieldUpdated:
+			err = c.addDate(q, term, now)
+			c.clientSide = c.clientSide || (term.Negated && term.Op == OpEq)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if statusSet != nil {
+		if len(statusSet) == 0 {
+			return nil, &ParseError{Input: q.Input, Pos: lastStatus.Pos, Msg: "status filters exclude every status"}
+		}
+		for i, status := range statuses {
+			if statusSet[i] {
+				c.Filter.Statuses = append(c.Filter.Statuses, status)
+			}
+		}
+	}
+
+	if prioritySet != nil {
+		if len(prioritySet) == 0 {
+			return nil, &ParseError{Input: q.Input, Pos: lastPriority.Pos, Msg: "priority filters exclude every priority"}
+		}
+		for priority := 0; priority <= 3; priority++ {
+			if prioritySet[priority] {
+				c.Filter.Priorities = append(c.Filter.Priorities, priority)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// ParseAndCompile parses and compiles a query in one step
+func ParseAndCompile(input string, now time.Time) (*Compiled, error) {
+	q, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return q.Compile(now)
+}
+
+// ServerSide returns true when Filter alone selects exactly the threads matching the
+// query, so Plain's totalCount can be used instead of checking every thread
+func (c *Compiled) ServerSide() bool {
+	return !c.clientSide
+}
+
+// Clone returns a copy of the compiled query that can be narrowed further without affecting the original
+func (c *Compiled) Clone() *Compiled {
+	clone := *c
+	clone.Filter.Statuses = append([]string(nil), c.Filter.Statuses...)
+	clone.Filter.Priorities = append([]int(nil), c.Filter.Priorities...)
+	clone.Filter.LabelTypeIDs = append([]string(nil), c.Filter.LabelTypeIDs...)
+	clone.Filter.CustomerIDs = append([]string(nil), c.Filter.CustomerIDs...)
+	clone.Filter.CompanyIDs = append([]string(nil), c.Filter.CompanyIDs...)
+	clone.LabelNames = append([]string(nil), c.LabelNames...)
+	clone.Predicates = append([]Predicate(nil), c.Predicates...)
+	return &clone
+}
+
+// Match returns true if the thread satisfies every term of the query
+func (c *Compiled) Match(thread *types.Thread) bool {
+	for _, predicate := range c.Predicates {
+		if !predicate(thread) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterThreads returns the threads that match the query
+func (c *Compiled) FilterThreads(threads []*types.Thread) []*types.Thread {
+	var matched []*types.Thread
+	for _, thread := range threads {
+		if thread != nil && c.Match(thread) {
+			matched = append(matched, thread)
+		}
+	}
+	return matched
+}
+
+// ResolveLabels resolves the label names of the query into label type IDs for the filter
+func (c *Compiled) ResolveLabels(ctx context.Context, labelCache *client.LabelCache) error {
+	if len(c.LabelNames) == 0 {
+		return nil
+	}
+
+	labels, err := labelCache.Resolve(ctx, c.LabelNames)
+	if err != nil {
+		return err
+	}
+
+	c.Filter.LabelTypeIDs = nil
+	for _, label := range labels {
+		c.Filter.LabelTypeIDs = append(c.Filter.LabelTypeIDs, label.ID)
+	}
+	return nil
+}
+
+// addPredicate adds a predicate, inverting it for negated terms
+func (c *Compiled) addPredicate(term Term, predicate Predicate) {
+	if term.Negated {
+		c.Predicates = append(c.Predicates, func(thread *types.Thread) bool {
+			return !predicate(thread)
+		})
+		return
+	}
+	c.Predicates = append(c.Predicates, predicate)
+}
+
+// addText matches free text against the thread title
+func (c *Compiled) addText(term Term) {
+	text := strings.ToLower(term.Value)
+	c.addPredicate(term, func(thread *types.Thread) bool {
+		return strings.Contains(strings.ToLower(thread.Title), text)
+	})
+}
+
+// addStatus narrows the set of allowed statuses (by index into statuses)
+func (c *Compiled) addStatus(q *Query, term Term, allowed map[int]bool) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, value := range term.Values() {
+		index := -1
+		for i, status := range statuses {
+			if strings.EqualFold(value, status) {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil, &ParseError{Input: q.Input, Pos: term.ValuePos, Msg: "unknown status " + strconv.Quote(value) + " (expected todo, snoozed or done)"}
+		}
+		values[index] = true
+	}
+
+	c.addPredicate(term, func(thread *types.Thread) bool {
+		for i := range values {
+			if strings.EqualFold(thread.Status, statuses[i]) {
+				return true
+			}
+		}
+		return false
+	})
+
+	return intersect(allowed, len(statuses), values, term.Negated), nil
+}
+
+// addPriority narrows the set of allowed priorities
+func (c *Compiled) addPriority(q *Query, term Term, allowed map[int]bool) (map[int]bool, error) {
+	values := term.Values()
+	if term.Op != OpEq && len(values) != 1 {
+		return nil, &ParseError{Input: q.Input, Pos: term.ValuePos, Msg: "comparisons take a single priority"}
+	}
+
+	matching := make(map[int]bool)
+	for _, value := range values {
+		priority, ok := priorityNames[strings.ToLower(value)]
+		if !ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > 3 {
+				return nil, &ParseError{Input: q.Input, Pos: term.ValuePos, Msg: "unknown priority " + strconv.Quote(value) + " (expected 0-3, urgent, high, medium or low)"}
+			}
+			priority = n
+		}
+
+		for p := 0; p <= 3; p++ {
+			if compareInt(p, term.Op, priority) {
+				matching[p] = true
+			}
+		}
+	}
+
+	c.addPredicate(term, func(thread *types.Thread) bool {
+		return matching[thread.Priority]
+	})
+
+	return intersect(allowed, 4, matching, term.Negated), nil
+}
+
+// addLabel matches threads with any of the given labels
+func (c *Compiled) addLabel(term Term) {
+	values := term.Values()
+	if !term.Negated {
+		// Plain returns threads with any of the label types, the predicate keeps terms ANDed
+		c.LabelNames = append(c.LabelNames, values...)
+	}
+
+	c.addPredicate(term, func(thread *types.Thread) bool {
+		for _, label := range thread.Labels {
+			for _, value := range values {
+				if label.LabelType.ID == value || strings.EqualFold(label.LabelType.Name, value) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// addCompany matches threads from any of the given companies (by ID or name)
+func (c *Compiled) addCompany(term Term) {
+	values := term.Values()
+	if !term.Negated && allHavePrefix(values, "co_") {
+		c.Filter.CompanyIDs = append(c.Filter.CompanyIDs, values...)
+	}
+
+	c.addPredicate(term, func(thread *types.Thread) bool {
+		if thread.Customer == nil || thread.Customer.Company == nil {
+			return false
+		}
+		company := thread.Customer.Company
+		for _, value := range values {
+			if company.ID == value || strings.EqualFold(company.Name, value) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// addCustomer matches threads from any of the given customers (by ID, or part of name or email)
+func (c *Compiled) addCustomer(term Term) {
+	values := term.Values()
+	if !term.Negated && allHavePrefix(values, "c_") {
+		c.Filter.CustomerIDs = append(c.Filter.CustomerIDs, values...)
+	}
+
+	c.addPredicate(term, func(thread *types.Thread) bool {
+		if thread.Customer == nil {
+			return false
+		}
+		name := strings.ToLower(thread.Customer.FullName)
+		email := strings.ToLower(thread.Customer.GetEmail())
+		for _, value := range values {
+			lower := strings.ToLower(value)
+			if thread.Customer.ID == value || strings.Contains(name, lower) || (email != "" && strings.Contains(email, lower)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// addAssignee matches threads by assignee; "none" and "any" match unassigned and assigned threads
+func (c *Compiled) addAssignee(term Term) {
+	values := term.Values()
+
+	c.addPredicate(term, func(thread *types.Thread) bool {
+		assigned := thread.AssignedTo != nil && (thread.AssignedTo.ID != "" || thread.AssignedTo.FullName != "")
+		for _, value := range values {
+			lower := strings.ToLower(value)
+			switch lower {
+			case "none":
+				if !assigned {
+					return true
+				}
+			case "any":
+				if assigned {
+					return true
+				}
+			default:
+				if assigned && (thread.AssignedTo.ID == value ||
+					strings.Contains(strings.ToLower(thread.AssignedTo.FullName), lower) ||
+					strings.EqualFold(thread.AssignedTo.Email, value)) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// addDate bounds the created or updated timestamp of threads
+func (c *Compiled) addDate(q *Query, term Term, now time.Time) error {
+	if len(term.Values()) != 1 {
+		return &ParseError{Input: q.Input, Pos: term.ValuePos, Msg: "date filters take a single value"}
+	}
+
+	start, end, relative, err := parseTime(term.Value, now)
+	if err != nil {
+		return &ParseError{Input: q.Input, Pos: term.ValuePos, Msg: err.Error()}
+	}
+
+	// A bare relative time means "since then", a bare date means "on that day"
+	op := term.Op
+	if op == OpEq && relative {
+		op = OpGte
+	}
+
+	var after, before time.Time
+	switch op {
+	case OpGt:
+		after = end
+	case OpGte:
+		after = start
+	case OpLt:
+		before = start
+	case OpLte:
+		before = end
+	case OpEq:
+		after, before = start, end
+	}
+
+	// Only single sided bounds can be negated server side, by swapping them
+	if term.Negated && op != OpEq {
+		after, before = before, after
+	}
+	if !term.Negated || op != OpEq {
+		if term.Field == FieldCreated {
+			c.Filter.CreatedAfter = laterBound(c.Filter.CreatedAfter, after)
+			c.Filter.CreatedBefore = earlierBound(c.Filter.CreatedBefore, before)
+		} else {
+			c.Filter.UpdatedAfter = laterBound(c.Filter.UpdatedAfter, after)
+			c.Filter.UpdatedBefore = earlierBound(c.Filter.UpdatedBefore, before)
+		}
+	}
+
+	field := term.Field
+	c.addPredicate(term, func(thread *types.Thread) bool {
+		timestamp := thread.UpdatedAt
+		if field == FieldCreated {
+			timestamp = thread.CreatedAt
+		}
+		if timestamp == nil {
+			return false
+		}
+		t, err := timestamp.Time()
+		if err != nil {
+			return false
+		}
+		switch op {
+		case OpGt:
+			return !t.Before(end)
+		case OpGte:
+			return !t.Before(start)
+		case OpLt:
+			return t.Before(start)
+		case OpLte:
+			return t.Before(end)
+		default:
+			return !t.Before(start) && t.Before(end)
+		}
+	})
+
+	return nil
+}
+
+// ParseTime parses a relative time (7d), a date or an RFC3339 timestamp, as
+// accepted by date terms, into the instant it starts at
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	start, _, _, err := parseTime(value, now)
+	return start, err
+}
+
+// parseTime parses a relative time (7d) or a date/RFC3339 timestamp into the
+// interval it covers. Relative times and timestamps are instants (start == end).
+func parseTime(value string, now time.Time) (start, end time.Time, relative bool, err error) {
+	if m := relativeTime.FindStringSubmatch(value); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := time.Hour
+		switch m[2] {
+		case "d":
+			unit = 24 * time.Hour
+		case "w":
+			unit = 7 * 24 * time.Hour
+		}
+		t := now.Add(-time.Duration(n) * unit)
+		return t, t, true, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, t.AddDate(0, 0, 1), false, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, t, false, nil
+	}
+
+	return start, end, false, &timeError{value: value}
+}
+
+// timeError reports an unparseable date value
+type timeError struct {
+	value string
+}
+
+func (e *timeError) Error() string {
+	return "invalid date " + strconv.Quote(e.value) + " (expected e.g. 12h, 7d, 2w, 2024-01-31 or an RFC3339 timestamp)"
+}
+
+// laterBound returns the later of an ISO8601 bound and t (zero t means unbounded)
+func laterBound(current string, t time.Time) string {
+	if t.IsZero() {
+		return current
+	}
+	if current != "" {
+		if c, err := time.Parse(time.RFC3339, current); err == nil && c.After(t) {
+			return current
+		}
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// earlierBound returns the earlier of an ISO8601 bound and t (zero t means unbounded)
+func earlierBound(current string, t time.Time) string {
+	if t.IsZero() {
+		return current
+	}
+	if current != "" {
+		if c, err := time.Parse(time.RFC3339, current); err == nil && c.Before(t) {
+			return current
+		}
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// intersect narrows allowed (nil meaning all of 0..size-1) by a term's values
+func intersect(allowed map[int]bool, size int, values map[int]bool, negated bool) map[int]bool {
+	next := make(map[int]bool)
+	for i := 0; i < size; i++ {
+		if allowed != nil && !allowed[i] {
+			continue
+		}
+		if values[i] != negated {
+			next[i] = true
+		}
+	}
+	return next
+}
+
+// compareInt applies a comparison operator
+func compareInt(a int, op Op, b int) bool {
+	switch op {
+	case OpLt:
+		return a < b
+	case OpLte:
+		return a <= b
+	case OpGt:
+		return a > b
+	case OpGte:
+		return a >= b
+	default:
+		return a == b
+	}
+}
+
+// allHavePrefix returns true if every value has the given ID prefix
+func allHavePrefix(values []string, prefix string) bool {
+	for _, value := range values {
+		if !strings.HasPrefix(value, prefix) {
+			return false
+		}
+	}
+	return len(values) > 0
+}
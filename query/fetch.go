@@ -0,0 +1,58 @@
+package query
+
+import (
+	"context"
+
+	"simple/client"
+	"simple/types"
+)
+
+// fetchPageSize is the page size used while scanning for threads matching client-side predicates
+const fetchPageSize = 50
+
+// maxFetchPages bounds how many pages are scanned for a single call to Fetch
+const maxFetchPages = 20
+
+// Fetch retrieves up to limit threads matching the query, starting after cursor.
+// Pages are fetched with the server-side filter and checked against the query
+// until enough threads match. The returned page info points after the last
+// thread that was examined, so it can be passed back in to continue.
+func (c *Compiled) Fetch(ctx context.Context, source client.DataSource, limit int, cursor string) (*types.ThreadConnection, error) {
+	result := &types.ThreadConnection{
+		Edges:    []*types.ThreadEdge{},
+		PageInfo: &types.PageInfo{},
+	}
+
+	for page := 0; page < maxFetchPages; page++ {
+		threads, err := source.GetThreadsByFilter(ctx, c.Filter, fetchPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if threads == nil {
+			break
+		}
+
+		for _, edge := range threads.Edges {
+			cursor = edge.Cursor
+			if edge.Node != nil && c.Match(edge.Node) {
+				result.Edges = append(result.Edges, edge)
+			}
+			if len(result.Edges) == limit {
+				// More threads may follow this one, even on the same page
+				result.PageInfo.HasNextPage = true
+				result.PageInfo.EndCursor = cursor
+				return result, nil
+			}
+		}
+
+		if threads.PageInfo == nil || !threads.PageInfo.HasNextPage {
+			return result, nil
+		}
+		cursor = threads.PageInfo.EndCursor
+	}
+
+	// Stop scanning but let the caller continue from where we left off
+	result.PageInfo.HasNextPage = true
+	result.PageInfo.EndCursor = cursor
+	return result, nil
+}
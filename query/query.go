@@ -0,0 +1,249 @@
+// Package query parses the thread filter language used by `threads list --query`
+// and the TUI query prompt, for example:
+//
+//	status:todo priority:<=1 label:bug company:"Acme" updated:>7d -assignee:none
+//
+// A query is a list of whitespace separated terms which must all match. A term is
+// either free text matched against the thread title, or a field:value pair. Values
+// may be quoted, may contain comma separated alternatives, and for ordered fields
+// may be prefixed with a comparison operator. A leading '-' negates a term.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Op is a comparison operator of a field term
+type Op string
+
+const (
+	OpEq  Op = "="
+	OpLt  Op = "<"
+	OpLte Op = "<="
+	OpGt  Op = ">"
+	OpGte Op = ">="
+)
+
+// Field names understood by the query language
+const (
+	FieldStatus   = "status"
+	FieldPriority = "priority"
+	FieldLabel    = "label"
+	FieldCompany  = "company"
+	FieldCustomer = "customer"
+	FieldAssignee = "assignee"
+	FieldCreated  = "created"
+	FieldUpdated  = "updated"
+)
+
+// fields lists the known fields and whether they accept comparison operators
+var fields = map[string]bool{
+	FieldStatus:   false,
+	FieldPriority: true,
+	FieldLabel:    false,
+	FieldCompany:  false,
+	FieldCustomer: false,
+	FieldAssignee: false,
+	FieldCreated:  true,
+	FieldUpdated:  true,
+}
+
+// Term is a single term of a query
+type Term struct {
+	// Pos is the byte offset of the term in the query, ValuePos the offset of its value
+	Pos      int
+	ValuePos int
+	Negated  bool
+	// Field is empty for free text terms
+	Field string
+	Op    Op
+	Value string
+}
+
+// Values splits the term value into its comma separated alternatives
+func (t Term) Values() []string {
+	var values []string
+	for _, value := range strings.Split(t.Value, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Query is a parsed query
+type Query struct {
+	Input string
+	Terms []Term
+}
+
+// ParseError reports a problem at a position of the query
+type ParseError struct {
+	Input string
+	Pos   int
+	Msg   string
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s (at column %d)", e.Msg, e.Pos+1)
+}
+
+// Caret returns the query with a caret marking the error position on the line below
+func (e *ParseError) Caret() string {
+	return e.Input + "\n" + strings.Repeat(" ", e.Pos) + "^"
+}
+
+// Parse parses a query string
+func Parse(input string) (*Query, error) {
+	p := &parser{input: input}
+	q := &Query{Input: input}
+
+	for {
+		p.skipSpace()
+		if p.eof() {
+			break
+		}
+
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		q.Terms = append(q.Terms, term)
+	}
+
+	return q, nil
+}
+
+// parser is a hand written scanner over the query string
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(rune(p.peek())) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) error {
+	return &ParseError{Input: p.input, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// term parses a single, possibly negated, free text or field term
+func (p *parser) term() (Term, error) {
+	term := Term{Pos: p.pos, Op: OpEq}
+
+	if p.peek() == '-' {
+		term.Negated = true
+		p.pos++
+		if p.eof() || unicode.IsSpace(rune(p.peek())) {
+			return term, p.errorf(term.Pos, "expected a term after '-'")
+		}
+	}
+
+	// A field name is an identifier directly followed by ':'
+	fieldPos := p.pos
+	for !p.eof() && (unicode.IsLetter(rune(p.peek())) || p.peek() == '_') {
+		p.pos++
+	}
+	if p.pos > fieldPos && p.peek() == ':' {
+		term.Field = strings.ToLower(p.input[fieldPos:p.pos])
+		ordered, ok := fields[term.Field]
+		if !ok {
+			return term, p.errorf(fieldPos, "unknown field %q (known fields: %s)", term.Field, knownFields())
+		}
+		p.pos++
+
+		opPos := p.pos
+		term.Op = p.op()
+		if term.Op != OpEq && !ordered {
+			return term, p.errorf(opPos, "field %q does not support the %s operator", term.Field, term.Op)
+		}
+	} else {
+		p.pos = fieldPos
+	}
+
+	term.ValuePos = p.pos
+	value, err := p.value()
+	if err != nil {
+		return term, err
+	}
+	if value == "" {
+		if term.Field != "" {
+			return term, p.errorf(term.ValuePos, "missing value for %q", term.Field)
+		}
+		return term, p.errorf(term.ValuePos, "expected a term")
+	}
+	term.Value = value
+
+	return term, nil
+}
+
+// op parses an optional comparison operator
+func (p *parser) op() Op {
+	for _, op := range []Op{OpLte, OpGte, OpLt, OpGt, OpEq} {
+		if strings.HasPrefix(p.input[p.pos:], string(op)) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return OpEq
+}
+
+// value parses a quoted or bare value
+func (p *parser) value() (string, error) {
+	if p.peek() == '"' {
+		start := p.pos
+		p.pos++
+		var value strings.Builder
+		for {
+			if p.eof() {
+				return "", p.errorf(start, "unterminated quoted value")
+			}
+			c := p.peek()
+			p.pos++
+			if c == '\\' && !p.eof() {
+				value.WriteByte(p.peek())
+				p.pos++
+				continue
+			}
+			if c == '"' {
+				break
+			}
+			value.WriteByte(c)
+		}
+		if !p.eof() && !unicode.IsSpace(rune(p.peek())) {
+			return "", p.errorf(p.pos, "expected a space after the closing quote")
+		}
+		return value.String(), nil
+	}
+
+	start := p.pos
+	for !p.eof() && !unicode.IsSpace(rune(p.peek())) {
+		p.pos++
+	}
+	return p.input[start:p.pos], nil
+}
+
+// knownFields returns the known field names for error messages
+func knownFields() string {
+	return strings.Join([]string{
+		FieldStatus, FieldPriority, FieldLabel, FieldCompany,
+		FieldCustomer, FieldAssignee, FieldCreated, FieldUpdated,
+	}, ", ")
+}
@@ -0,0 +1,225 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"simple/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Term
+	}{
+		{
+			name:  "free text and fields",
+			input: `login status:todo,snoozed`,
+			want: []Term{
+				{Pos: 0, ValuePos: 0, Op: OpEq, Value: "login"},
+				{Pos: 6, ValuePos: 13, Field: FieldStatus, Op: OpEq, Value: "todo,snoozed"},
+			},
+		},
+		{
+			name:  "negation and operators",
+			input: `-assignee:none priority:<=1`,
+			want: []Term{
+				{Pos: 0, ValuePos: 10, Negated: true, Field: FieldAssignee, Op: OpEq, Value: "none"},
+				{Pos: 15, ValuePos: 26, Field: FieldPriority, Op: OpLte, Value: "1"},
+			},
+		},
+		{
+			name:  "quoted value",
+			input: `company:"Acme \"Inc\""`,
+			want: []Term{
+				{Pos: 0, ValuePos: 8, Field: FieldCompany, Op: OpEq, Value: `Acme "Inc"`},
+			},
+		},
+		{
+			name:  "field names are case-insensitive",
+			input: `Label:bug`,
+			want: []Term{
+				{Pos: 0, ValuePos: 6, Field: FieldLabel, Op: OpEq, Value: "bug"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(q.Terms, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, q.Terms, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantPos int
+	}{
+		{"unknown field", "bug stauts:todo", 4},
+		{"unsupported operator", "label:>bug", 6},
+		{"missing value", "status: label:bug", 7},
+		{"unterminated quote", `company:"Acme`, 8},
+		{"dangling negation", "- status:todo", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse(%q) error = %v, want a *ParseError", tt.input, err)
+			}
+			if parseErr.Pos != tt.wantPos {
+				t.Errorf("Parse(%q) error at %d, want %d (%v)", tt.input, parseErr.Pos, tt.wantPos, err)
+			}
+		})
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	c, err := ParseAndCompile(`status:todo,done -status:done priority:<2 label:bug company:co_1 updated:>7d created:<2024-01-01`, now)
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+
+	if want := []string{"TODO"}; !reflect.DeepEqual(c.Filter.Statuses, want) {
+		t.Errorf("Statuses = %v, want %v", c.Filter.Statuses, want)
+	}
+	if want := []int{0, 1}; !reflect.DeepEqual(c.Filter.Priorities, want) {
+		t.Errorf("Priorities = %v, want %v", c.Filter.Priorities, want)
+	}
+	if want := []string{"bug"}; !reflect.DeepEqual(c.LabelNames, want) {
+		t.Errorf("LabelNames = %v, want %v", c.LabelNames, want)
+	}
+	if want := []string{"co_1"}; !reflect.DeepEqual(c.Filter.CompanyIDs, want) {
+		t.Errorf("CompanyIDs = %v, want %v", c.Filter.CompanyIDs, want)
+	}
+	if want := "2024-03-03T12:00:00Z"; c.Filter.UpdatedAfter != want {
+		t.Errorf("UpdatedAfter = %q, want %q", c.Filter.UpdatedAfter, want)
+	}
+	if want := "2024-01-01T00:00:00Z"; c.Filter.CreatedBefore != want {
+		t.Errorf("CreatedBefore = %q, want %q", c.Filter.CreatedBefore, want)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		input   string
+		wantPos int
+	}{
+		{"unknown status", "status:open", 7},
+		{"excluded statuses", "status:todo -status:todo", 12},
+		{"unknown priority", "priority:critical", 9},
+		{"invalid date", "updated:>yesterday", 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAndCompile(tt.input, now)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("compile(%q) error = %v, want a *ParseError", tt.input, err)
+			}
+			if parseErr.Pos != tt.wantPos {
+				t.Errorf("compile(%q) error at %d, want %d (%v)", tt.input, parseErr.Pos, tt.wantPos, err)
+			}
+		})
+	}
+}
+
+func TestServerSide(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"status:todo,snoozed priority:<=1", true},
+		{"label:bug updated:>7d -status:done", true},
+		{"company:co_123 customer:c_456", true},
+		{"label:bug label:urgent", false},
+		{"company:co_1,co_2", true},
+		{"company:co_1 company:co_2", false},
+		{"customer:c_1 customer:c_2", false},
+		{"-label:bug", false},
+		{"company:Acme", false},
+		{"assignee:none", false},
+		{"safari", false},
+		{"-created:2024-03-01", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			compiled, err := ParseAndCompile(tt.input, now)
+			if err != nil {
+				t.Fatalf("compile(%q) error = %v", tt.input, err)
+			}
+			if got := compiled.ServerSide(); got != tt.want {
+				t.Errorf("compile(%q).ServerSide() = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	thread := &types.Thread{
+		Title:     "Login fails on Safari",
+		Status:    "TODO",
+		Priority:  1,
+		UpdatedAt: &types.DateTime{ISO8601: "2024-03-09T08:00:00Z"},
+		Customer: &types.Customer{
+			ID:       "c_1",
+			FullName: "Jane Doe",
+			Company:  &types.Company{ID: "co_1", Name: "Acme"},
+		},
+		Labels: []types.Label{
+			{ID: "l_1", LabelType: types.LabelType{ID: "lt_1", Name: "Bug"}},
+		},
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"login", true},
+		{"-safari", false},
+		{"status:todo priority:high", true},
+		{"priority:>=2", false},
+		{"label:bug", true},
+		{"-label:bug,feature", false},
+		{`company:acme customer:jane`, true},
+		{"assignee:none", true},
+		{"-assignee:none", false},
+		{"updated:>2d", true},
+		{"updated:>1d", false},
+		{"updated:<1d", true},
+		{"updated:2024-03-09", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			c, err := ParseAndCompile(tt.input, now)
+			if err != nil {
+				t.Fatalf("compile(%q) failed: %v", tt.input, err)
+			}
+			if got := c.Match(thread); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -28,6 +28,11 @@ type PostgresConfig struct {
 func PostgresInitDB(cfg PostgresConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
+	return postgresOpen(dsn)
+}
+
+// postgresOpen connects to a Postgres DSN and auto-migrates the Threads model.
+func postgresOpen(dsn string) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		return nil, err
@@ -37,6 +42,9 @@ func PostgresInitDB(cfg PostgresConfig) (*gorm.DB, error) {
 	if err := db.AutoMigrate(&Threads{}); err != nil {
 		return nil, err
 	}
+	if err := migrateSearch(db); err != nil {
+		return nil, err
+	}
 
 	return db, nil
 }
@@ -94,13 +102,21 @@ func PostgresFromThread(t *types.Thread) *Threads {
 		}
 	}
 
+	assignee := ""
+	if t.AssignedTo != nil {
+		assignee = t.AssignedTo.FullName
+	}
+	priority := t.Priority
+
 	return &Threads{
 		ID:        t.ID,
 		Title:     t.Title,
 		Status:    t.Status,
+		Priority:  &priority,
 		Labels:    labelsJSON,
 		Customer:  customer,
 		Company:   company,
+		Assignee:  assignee,
 		CreatedAt: created,
 		UpdatedAt: updated,
 	}
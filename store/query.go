@@ -0,0 +1,149 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ThreadQuery filters and pages the threads read from the local store. Zero
+// fields don't filter.
+type ThreadQuery struct {
+	Statuses   []string
+	Priorities []int
+	// Labels matches threads with any of the label names
+	Labels []string
+	// Customer and Company match names, ignoring case
+	Customer string
+	Company  string
+	// Search matches part of the title, ignoring case
+	Search        string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	Limit         int
+	Offset        int
+}
+
+// CustomerQuery filters and pages the customers read from the local store
+type CustomerQuery struct {
+	// Search matches part of the customer's name, ignoring case
+	Search  string
+	Company string
+	Limit   int
+	Offset  int
+}
+
+// CustomerSummary is a customer as seen through the threads in the local
+// store, which only records the customer's and company's names
+type CustomerSummary struct {
+	Name    string
+	Company string
+	Threads int
+	Open    int
+}
+
+// ListThreads returns a page of threads matching the query, most recently
+// updated first, with the number of matching threads
+func (s *Stats) ListThreads(q ThreadQuery) ([]Threads, int, error) {
+	db := s.db.Model(&Threads{})
+	if len(q.Statuses) > 0 {
+		db = db.Where("status IN ?", q.Statuses)
+	}
+	if len(q.Priorities) > 0 {
+		db = db.Where("priority IN ?", q.Priorities)
+	}
+	if len(q.Labels) > 0 {
+		// Labels are stored as a JSON array or object of names, so match the quoted names
+		var labels *gorm.DB
+		for _, label := range q.Labels {
+			name, _ := json.Marshal(label)
+			pattern := "%" + escapeLike(string(name)) + "%"
+			if labels == nil {
+				labels = s.db.Where(`CAST(labels AS TEXT) LIKE ? ESCAPE '\'`, pattern)
+			} else {
+				labels = labels.Or(`CAST(labels AS TEXT) LIKE ? ESCAPE '\'`, pattern)
+			}
+		}
+		db = db.Where(labels)
+	}
+	if q.Customer != "" {
+		db = db.Where("LOWER(customer) = ?", strings.ToLower(q.Customer))
+	}
+	if q.Company != "" {
+		db = db.Where("LOWER(company) = ?", strings.ToLower(q.Company))
+	}
+	if q.Search != "" {
+		db = db.Where(`LOWER(title) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(q.Search))+"%")
+	}
+	db = whereTime(db, "created_at >= ?", q.CreatedAfter)
+	db = whereTime(db, "created_at < ?", q.CreatedBefore)
+	db = whereTime(db, "updated_at >= ?", q.UpdatedAfter)
+	db = whereTime(db, "updated_at < ?", q.UpdatedBefore)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count threads in local store: %w", err)
+	}
+
+	var threads []Threads
+	err := db.Order("updated_at IS NULL, updated_at DESC, id").Limit(q.Limit).Offset(q.Offset).Find(&threads).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read threads from local store: %w", err)
+	}
+	return threads, int(total), nil
+}
+
+// Thread returns a thread by ID, or nil when the store doesn't have it
+func (s *Stats) Thread(id string) (*Threads, error) {
+	var threads []Threads
+	if err := s.db.Where("id = ?", id).Limit(1).Find(&threads).Error; err != nil {
+		return nil, fmt.Errorf("failed to read thread %s from local store: %w", id, err)
+	}
+	if len(threads) == 0 {
+		return nil, nil
+	}
+	return &threads[0], nil
+}
+
+// ListCustomers returns a page of the customers with threads in the store,
+// those with the most threads first, with the number of matching customers
+func (s *Stats) ListCustomers(q CustomerQuery) ([]CustomerSummary, int, error) {
+	db := s.db.Model(&Threads{}).Where("customer <> ''")
+	if q.Search != "" {
+		db = db.Where(`LOWER(customer) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(q.Search))+"%")
+	}
+	if q.Company != "" {
+		db = db.Where("LOWER(company) = ?", strings.ToLower(q.Company))
+	}
+	db = db.Select("customer AS name, company, COUNT(*) AS threads, SUM(CASE WHEN status <> 'DONE' THEN 1 ELSE 0 END) AS open").
+		Group("customer, company")
+
+	var total int64
+	if err := s.db.Table("(?) AS customers", db).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count customers in local store: %w", err)
+	}
+
+	var customers []CustomerSummary
+	if err := db.Order("threads DESC, name").Limit(q.Limit).Offset(q.Offset).Scan(&customers).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to read customers from local store: %w", err)
+	}
+	return customers, int(total), nil
+}
+
+// whereTime adds a condition on a time unless it is zero
+func whereTime(db *gorm.DB, condition string, t time.Time) *gorm.DB {
+	if t.IsZero() {
+		return db
+	}
+	return db.Where(condition, t.UTC())
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,265 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"simple/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ftsTriggers keep the thread_search FTS5 index up to date as threads and timeline entries change.
+// Thread titles are indexed with an empty entry ID.
+var ftsTriggers = map[string]string{
+	"thread_search_thread_insert": `AFTER INSERT ON threads BEGIN
+		INSERT INTO thread_search (thread_id, entry_id, text) VALUES (new.id, '', new.title);
+	END`,
+	"thread_search_thread_update": `AFTER UPDATE OF title ON threads BEGIN
+		DELETE FROM thread_search WHERE thread_id = old.id AND entry_id = '';
+		INSERT INTO thread_search (thread_id, entry_id, text) VALUES (new.id, '', new.title);
+	END`,
+	"thread_search_thread_delete": `AFTER DELETE ON threads BEGIN
+		DELETE FROM thread_search WHERE thread_id = old.id AND entry_id = '';
+	END`,
+	"thread_search_entry_insert": `AFTER INSERT ON timeline_entries BEGIN
+		INSERT INTO thread_search (thread_id, entry_id, text) VALUES (new.thread_id, new.id, new.text);
+	END`,
+	"thread_search_entry_update": `AFTER UPDATE ON timeline_entries BEGIN
+		DELETE FROM thread_search WHERE entry_id = old.id;
+		INSERT INTO thread_search (thread_id, entry_id, text) VALUES (new.thread_id, new.id, new.text);
+	END`,
+	"thread_search_entry_delete": `AFTER DELETE ON timeline_entries BEGIN
+		DELETE FROM thread_search WHERE entry_id = old.id;
+	END`,
+}
+
+// TimelineEntries stores the text of a thread's messages for searching
+type TimelineEntries struct {
+	ID        string `gorm:"primaryKey"`
+	ThreadID  string `gorm:"index"`
+	Actor     string
+	Text      string
+	CreatedAt *time.Time `gorm:"autoCreateTime:false"`
+}
+
+// SearchQuery pages the threads matching search terms. Threads match when their
+// title or one of their stored messages contains every term.
+type SearchQuery struct {
+	Terms  string
+	Limit  int
+	Offset int
+}
+
+// SearchResult is a thread matching a search, with a snippet of its best
+// matching text
+type SearchResult struct {
+	Threads
+	Snippet string
+}
+
+// migrateSearch creates the timeline entries table and the full-text index over
+// it and the thread titles
+func migrateSearch(db *gorm.DB) error {
+	if err := db.AutoMigrate(&TimelineEntries{}); err != nil {
+		return err
+	}
+	if db.Dialector.Name() == "postgres" {
+		if err := db.Exec(`CREATE INDEX IF NOT EXISTS threads_title_search ON threads USING GIN (to_tsvector('english', title))`).Error; err != nil {
+			return err
+		}
+		return db.Exec(`CREATE INDEX IF NOT EXISTS timeline_entries_text_search ON timeline_entries USING GIN (to_tsvector('english', text))`).Error
+	}
+	return migrateFTS(db)
+}
+
+// migrateFTS sets up the SQLite FTS5 index, filling it from the stored threads
+// when its triggers are new. SQLite builds without FTS5 drop the triggers, which
+// couldn't run, and search with LIKE instead.
+func migrateFTS(db *gorm.DB) error {
+	// Without FTS5 this fails, which isn't worth logging
+	quiet := db.Session(&gorm.Session{Logger: logger.Discard})
+	err := quiet.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS thread_search USING fts5(thread_id UNINDEXED, entry_id UNINDEXED, text, tokenize = 'porter unicode61')`).Error
+	if err != nil && !strings.Contains(err.Error(), "no such module") {
+		return err
+	}
+	if err != nil {
+		for name := range ftsTriggers {
+			if err := db.Exec("DROP TRIGGER IF EXISTS " + name).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if hasFTSTriggers(db) {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			"DELETE FROM thread_search",
+			"INSERT INTO thread_search (thread_id, entry_id, text) SELECT id, '', title FROM threads",
+			"INSERT INTO thread_search (thread_id, entry_id, text) SELECT thread_id, id, text FROM timeline_entries",
+		}
+		for name, body := range ftsTriggers {
+			statements = append(statements, fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s %s", name, body))
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to build search index: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// hasFTSTriggers returns true if every trigger keeping the FTS5 index up to date exists
+func hasFTSTriggers(db *gorm.DB) bool {
+	var count int64
+	names := make([]string, 0, len(ftsTriggers))
+	for name := range ftsTriggers {
+		names = append(names, name)
+	}
+	if err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ?", names).Scan(&count).Error; err != nil {
+		return false
+	}
+	return int(count) == len(ftsTriggers)
+}
+
+// hasFTS returns true if the FTS5 index is up to date and this SQLite build can
+// read it. Stores indexed by a build with FTS5 may be read by one without.
+func (s *Stats) hasFTS() bool {
+	if !hasFTSTriggers(s.db) {
+		return false
+	}
+	return s.db.Exec("SELECT 1 FROM thread_search LIMIT 0").Error == nil
+}
+
+// Ranked returns true if Search ranks its results. SQLite builds without FTS5
+// search with LIKE, which can't rank them.
+func (s *Stats) Ranked() bool {
+	return s.db.Dialector.Name() == "postgres" || s.hasFTS()
+}
+
+// SaveTimeline stores the text of a thread's messages. Events such as status
+// changes aren't stored.
+func (w *Writer) SaveTimeline(threadID string, entries []*types.TimelineEntry) error {
+	for _, entry := range entries {
+		if entry == nil || !entry.IsMessage() || entry.Text() == "" {
+			continue
+		}
+		row := &TimelineEntries{
+			ID:       entry.ID,
+			ThreadID: threadID,
+			Actor:    entry.ActorName(),
+			Text:     entry.Text(),
+		}
+		if entry.Timestamp != nil {
+			if t, err := entry.Timestamp.Time(); err == nil {
+				row.CreatedAt = &t
+			}
+		}
+		if err := w.db.Save(row).Error; err != nil {
+			return fmt.Errorf("failed to save timeline of thread %s: %w", threadID, err)
+		}
+	}
+	return nil
+}
+
+// Search returns a page of the threads matching the query, best matches first,
+// with the number of matching threads. SQLite ranks matches with FTS5 when it
+// is built in and falls back to LIKE, Postgres uses text search.
+func (s *Stats) Search(q SearchQuery) ([]SearchResult, int, error) {
+	terms := types.SearchTerms(q.Terms)
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	var matches string
+	var args []interface{}
+	like := false
+	switch {
+	case s.db.Dialector.Name() == "postgres":
+		matches, args = postgresMatches(q.Terms)
+	case s.hasFTS():
+		matches, args = ftsMatches(terms)
+	default:
+		matches, args = likeMatches(terms)
+		like = true
+	}
+	// Timelines may be stored for threads that aren't
+	from := "FROM (" + matches + ") AS matches JOIN threads ON threads.id = matches.thread_id"
+
+	var total int64
+	if err := s.db.Raw("SELECT COUNT(*) "+from, args...).Scan(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to search local store: %w", err)
+	}
+
+	var results []SearchResult
+	page := "SELECT threads.*, matches.snippet " + from + " ORDER BY matches.rank, threads.updated_at DESC, threads.id LIMIT ? OFFSET ?"
+	if err := s.db.Raw(page, append(args, q.Limit, q.Offset)...).Scan(&results).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to search local store: %w", err)
+	}
+	if like {
+		for i := range results {
+			results[i].Snippet = types.Snippet(results[i].Snippet, terms)
+		}
+	}
+	return results, int(total), nil
+}
+
+// ftsMatches selects the best FTS5 match of each thread. A bare column next to
+// MIN takes its value from the row with the minimum, and LIMIT -1 keeps SQLite
+// from flattening the subquery into the aggregate, where snippet can't run.
+func ftsMatches(terms []string) (string, []interface{}) {
+	// Quote every term so words such as AND or NOT aren't read as query syntax
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	sql := fmt.Sprintf(`SELECT thread_id, MIN(rank) AS rank, snippet FROM (
+		SELECT thread_id, rank, snippet(thread_search, 2, ?, ?, '…', %d) AS snippet
+		FROM thread_search WHERE thread_search MATCH ? LIMIT -1
+	) GROUP BY thread_id`, types.SnippetWords)
+	return sql, []interface{}{types.HighlightStart, types.HighlightEnd, strings.Join(quoted, " ")}
+}
+
+// postgresMatches selects the best text search match of each thread, ranked
+// negatively to sort like FTS5
+func postgresMatches(terms string) (string, []interface{}) {
+	options := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=%d, MinWords=%d, MaxFragments=1", types.HighlightStart, types.HighlightEnd, types.SnippetWords, types.SnippetWords/2)
+	sql := `SELECT DISTINCT ON (thread_id) thread_id, rank, snippet FROM (
+		SELECT threads.id AS thread_id, -ts_rank(to_tsvector('english', threads.title), query) AS rank,
+			ts_headline('english', threads.title, query, ?) AS snippet
+		FROM threads, websearch_to_tsquery('english', ?) AS query
+		WHERE to_tsvector('english', threads.title) @@ query
+		UNION ALL
+		SELECT timeline_entries.thread_id, -ts_rank(to_tsvector('english', timeline_entries.text), query),
+			ts_headline('english', timeline_entries.text, query, ?)
+		FROM timeline_entries, websearch_to_tsquery('english', ?) AS query
+		WHERE to_tsvector('english', timeline_entries.text) @@ query
+	) AS ranked ORDER BY thread_id, rank`
+	return sql, []interface{}{options, terms, options, terms}
+}
+
+// likeMatches selects the threads whose title or a message contains every term,
+// title matches first. Snippets are cut from the matching text afterwards.
+func likeMatches(terms []string) (string, []interface{}) {
+	var conditions []string
+	var titleArgs, textArgs []interface{}
+	for _, term := range terms {
+		conditions = append(conditions, `LOWER(%[1]s) LIKE ? ESCAPE '\'`)
+		pattern := "%" + escapeLike(strings.ToLower(term)) + "%"
+		titleArgs = append(titleArgs, pattern)
+		textArgs = append(textArgs, pattern)
+	}
+	where := strings.Join(conditions, " AND ")
+	sql := `SELECT thread_id, MIN(rank) AS rank, snippet FROM (
+		SELECT id AS thread_id, 0 AS rank, title AS snippet FROM threads WHERE ` + fmt.Sprintf(where, "title") + `
+		UNION ALL
+		SELECT thread_id, 1, text FROM timeline_entries WHERE ` + fmt.Sprintf(where, "text") + `
+	) GROUP BY thread_id`
+	return sql, append(titleArgs, textArgs...)
+}
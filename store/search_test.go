@@ -0,0 +1,89 @@
+package store
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"simple/config"
+	"simple/types"
+)
+
+func TestSearch(t *testing.T) {
+	cfg := config.DBConfig{Source: filepath.Join(t.TempDir(), "threads.db")}
+	writer, err := OpenWriter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := &types.DateTime{ISO8601: time.Now().Format(time.RFC3339)}
+	for _, th := range []*types.Thread{
+		{ID: "th_1", Title: "Export fails with ECONNRESET", Status: "TODO", UpdatedAt: at},
+		{ID: "th_2", Title: "Cannot log in", Status: "TODO", UpdatedAt: at},
+		{ID: "th_3", Title: "Invoice is wrong", Status: "DONE", UpdatedAt: at},
+	} {
+		if err := writer.SaveThread(th); err != nil {
+			t.Fatal(err)
+		}
+	}
+	message := func(id, text string) *types.TimelineEntry {
+		return &types.TimelineEntry{ID: id, Timestamp: at, Actor: &types.UserActor{User: &types.User{FullName: "Ada"}}, Entry: &types.ChatEntry{Text: text}}
+	}
+	event := &types.TimelineEntry{ID: "e_3", Entry: &types.ThreadStatusTransitionedEntry{PreviousStatus: "TODO", NextStatus: "DONE"}}
+	if err := writer.SaveTimeline("th_2", []*types.TimelineEntry{
+		message("e_1", "The login page shows error ECONNRESET after a while, then the browser gives up and shows a blank page"),
+		message("e_2", "Still broken"),
+		event,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Timelines of threads that aren't stored are ignored
+	if err := writer.SaveTimeline("th_9", []*types.TimelineEntry{message("e_9", "ECONNRESET again")}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := OpenStats(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		terms string
+		want  []string
+	}{
+		{"econnreset", []string{"th_1", "th_2"}},
+		{"ECONNRESET: login", []string{"th_2"}},
+		{"broken", []string{"th_2"}},
+		{"invoice", []string{"th_3"}},
+		{"status", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.terms, func(t *testing.T) {
+			results, total, err := stats.Search(SearchQuery{Terms: tt.terms, Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, result.ID)
+			}
+			if total != len(tt.want) || len(got) != len(tt.want) {
+				t.Fatalf("Search(%q) = %v (%d), want %v", tt.terms, got, total, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Search(%q) = %v, want %v", tt.terms, got, tt.want)
+				}
+			}
+		})
+	}
+
+	results, _, err := stats.Search(SearchQuery{Terms: "login", Limit: 10})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Search(login) = %v, %v", results, err)
+	}
+	if got := types.Highlight(results[0].Snippet, func(s string) string { return "[" + s + "]" }); !strings.HasPrefix(got, "The [login] page shows error") {
+		t.Errorf("snippet = %q", got)
+	}
+}
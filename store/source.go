@@ -0,0 +1,281 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"simple/client"
+	"simple/config"
+	"simple/types"
+)
+
+// UnknownPriority is the priority of threads synced before priorities were stored
+const UnknownPriority = -1
+
+// Source reads threads from the local store in place of the Plain API, for
+// offline mode. The store has no messages or timelines, and labels are
+// identified by name.
+type Source struct {
+	stats    *Stats
+	syncedAt time.Time
+}
+
+// OpenSource opens the local store as a data source
+func OpenSource(cfg config.DBConfig) (*Source, error) {
+	stats, err := OpenStats(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, fmt.Errorf("no local store found (sync threads with `simple report` or `simple serve webhooks` first)")
+	}
+
+	syncedAt, err := stats.syncedAt(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stores synced by older versions lack the priority and assignee columns.
+	// Reading doesn't change the schema, the next sync adds them.
+	for _, column := range []string{"Priority", "Assignee"} {
+		if !stats.db.Migrator().HasColumn(&Threads{}, column) {
+			return nil, fmt.Errorf("the local store was synced by an older version (run `simple report` first to update it)")
+		}
+	}
+
+	return &Source{stats: stats, syncedAt: syncedAt}, nil
+}
+
+// syncedAt returns when the store was last written to: the SQLite file's
+// modification time, or the latest thread update in Postgres
+func (s *Stats) syncedAt(cfg config.DBConfig) (time.Time, error) {
+	if cfg.Driver != "postgres" {
+		info, err := os.Stat(cfg.Source)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read local store: %w", err)
+		}
+		return info.ModTime(), nil
+	}
+
+	var latest []Threads
+	if err := s.db.Where("updated_at IS NOT NULL").Order("updated_at DESC").Limit(1).Find(&latest).Error; err != nil {
+		return time.Time{}, fmt.Errorf("failed to read local store: %w", err)
+	}
+	if len(latest) == 0 {
+		return time.Time{}, nil
+	}
+	return *latest[0].UpdatedAt, nil
+}
+
+// Coverage returns where the store's history starts, the oldest thread update it
+// holds, and when it was last synced. report only stores the threads updated in
+// its range, so days before the oldest update are missing rather than quiet.
+func (s *Stats) Coverage(cfg config.DBConfig) (from, syncedAt time.Time, err error) {
+	var oldest []Threads
+	if err := s.db.Where("updated_at IS NOT NULL").Order("updated_at ASC").Limit(1).Find(&oldest).Error; err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to read local store: %w", err)
+	}
+	if len(oldest) > 0 {
+		from = *oldest[0].UpdatedAt
+	}
+	syncedAt, err = s.syncedAt(cfg)
+	return from, syncedAt, err
+}
+
+// SyncedAt returns when the store was last synced, zero if never
+func (s *Source) SyncedAt() time.Time {
+	return s.syncedAt
+}
+
+// GetThreads returns a page of open threads
+func (s *Source) GetThreads(ctx context.Context, limit int, cursor string) (*types.ThreadConnection, error) {
+	return s.page(ThreadQuery{Statuses: []string{"TODO", "SNOOZED"}}, limit, cursor)
+}
+
+// GetAllThreads returns a page of threads in any status
+func (s *Source) GetAllThreads(ctx context.Context, limit int, cursor string) (*types.ThreadConnection, error) {
+	return s.page(ThreadQuery{}, limit, cursor)
+}
+
+// GetThreadsByStatus returns a page of threads in a status
+func (s *Source) GetThreadsByStatus(ctx context.Context, status string, limit int, cursor string) (*types.ThreadConnection, error) {
+	return s.page(ThreadQuery{Statuses: []string{status}}, limit, cursor)
+}
+
+// GetThreadsByFilter returns a page of threads matching a filter. Label type
+// IDs are label names, as returned by GetLabels.
+func (s *Source) GetThreadsByFilter(ctx context.Context, filter client.ThreadFilter, limit int, cursor string) (*types.ThreadConnection, error) {
+	q, err := threadQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.page(q, limit, cursor)
+}
+
+// GetThreadsByDateRange returns a page of threads updated after dateAfter
+func (s *Source) GetThreadsByDateRange(ctx context.Context, dateAfter string, limit int, cursor string) (*types.ThreadConnection, error) {
+	return s.GetThreadsByFilter(ctx, client.ThreadFilter{UpdatedAfter: dateAfter}, limit, cursor)
+}
+
+// GetThreadById returns a thread, or nil when the store doesn't have it
+func (s *Source) GetThreadById(ctx context.Context, threadId string) (*types.Thread, error) {
+	row, err := s.stats.Thread(threadId)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return row.Thread(), nil
+}
+
+// GetThreadWithMessages returns a thread without messages, which the store doesn't keep
+func (s *Source) GetThreadWithMessages(ctx context.Context, threadId string) (*types.Thread, error) {
+	thread, err := s.GetThreadById(ctx, threadId)
+	if err != nil {
+		return nil, err
+	}
+	if thread == nil {
+		return nil, fmt.Errorf("thread %s is not in the local store", threadId)
+	}
+	return thread, nil
+}
+
+// GetThreadTimeline fails, the store doesn't keep timelines
+func (s *Source) GetThreadTimeline(ctx context.Context, threadId string, last int, before string) (*types.TimelineEntryConnection, error) {
+	return nil, fmt.Errorf("timelines are %w", client.ErrOffline)
+}
+
+// CountThreads counts the threads matching a filter
+func (s *Source) CountThreads(ctx context.Context, filter client.ThreadFilter) (int, bool, error) {
+	q, err := threadQuery(filter)
+	if err != nil {
+		return 0, false, err
+	}
+	q.Limit = 1
+	_, total, err := s.stats.ListThreads(q)
+	if err != nil {
+		return 0, false, err
+	}
+	return total, true, nil
+}
+
+// GetLabels returns the labels applied to threads in the store, with their
+// names as IDs
+func (s *Source) GetLabels(ctx context.Context) ([]*types.LabelType, error) {
+	var rows []Threads
+	if err := s.stats.db.Select("labels").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read labels from local store: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var labels []*types.LabelType
+	for i := range rows {
+		for _, name := range rows[i].LabelNames() {
+			if !seen[name] {
+				seen[name] = true
+				labels = append(labels, &types.LabelType{ID: name, Name: name})
+			}
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels, nil
+}
+
+// page reads the page of threads after cursor, which is an offset
+func (s *Source) page(q ThreadQuery, limit int, cursor string) (*types.ThreadConnection, error) {
+	if cursor != "" {
+		offset, err := strconv.Atoi(cursor)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		q.Offset = offset
+	}
+	q.Limit = limit
+
+	rows, total, err := s.stats.ListThreads(q)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &types.ThreadConnection{
+		Edges:    make([]*types.ThreadEdge, 0, len(rows)),
+		PageInfo: &types.PageInfo{HasPreviousPage: q.Offset > 0},
+	}
+	for i := range rows {
+		connection.Edges = append(connection.Edges, &types.ThreadEdge{
+			Node:   rows[i].Thread(),
+			Cursor: strconv.Itoa(q.Offset + i + 1),
+		})
+	}
+	end := q.Offset + len(rows)
+	connection.PageInfo.EndCursor = strconv.Itoa(end)
+	connection.PageInfo.HasNextPage = end < total
+	return connection, nil
+}
+
+// threadQuery converts an API thread filter. The store only knows customers
+// and companies by name, so filters on their IDs can't be answered.
+func threadQuery(filter client.ThreadFilter) (ThreadQuery, error) {
+	q := ThreadQuery{
+		Statuses:   filter.Statuses,
+		Priorities: filter.Priorities,
+		Labels:     filter.LabelTypeIDs,
+	}
+	if len(filter.CustomerIDs) > 0 || len(filter.CompanyIDs) > 0 {
+		return q, fmt.Errorf("filtering by customer or company is %w", client.ErrOffline)
+	}
+
+	times := []struct {
+		value string
+		dest  *time.Time
+	}{
+		{filter.CreatedAfter, &q.CreatedAfter},
+		{filter.CreatedBefore, &q.CreatedBefore},
+		{filter.UpdatedAfter, &q.UpdatedAfter},
+		{filter.UpdatedBefore, &q.UpdatedBefore},
+	}
+	for _, t := range times {
+		if t.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return q, fmt.Errorf("invalid date %q: %w", t.value, err)
+		}
+		*t.dest = parsed
+	}
+	return q, nil
+}
+
+// Thread converts a row into a thread as the API would return it, with labels
+// identified by name
+func (t *Threads) Thread() *types.Thread {
+	thread := &types.Thread{
+		ID:       t.ID,
+		Title:    t.Title,
+		Status:   t.Status,
+		Priority: UnknownPriority,
+		Customer: &types.Customer{FullName: t.Customer},
+	}
+	if t.Priority != nil {
+		thread.Priority = *t.Priority
+	}
+	if t.Company != "" {
+		thread.Customer.Company = &types.Company{Name: t.Company}
+	}
+	if t.Assignee != "" {
+		thread.AssignedTo = &types.User{FullName: t.Assignee}
+	}
+	for _, name := range t.LabelNames() {
+		thread.Labels = append(thread.Labels, types.Label{ID: name, LabelType: types.LabelType{ID: name, Name: name}})
+	}
+	if t.CreatedAt != nil {
+		thread.CreatedAt = &types.DateTime{ISO8601: t.CreatedAt.UTC().Format(time.RFC3339)}
+	}
+	if t.UpdatedAt != nil {
+		thread.UpdatedAt = &types.DateTime{ISO8601: t.UpdatedAt.UTC().Format(time.RFC3339)}
+	}
+	return thread
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"simple/client"
+	"simple/config"
+	"simple/types"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSource(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cfg := config.DBConfig{Source: filepath.Join(t.TempDir(), "threads.db")}
+	writer, err := OpenWriter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, th := range []*types.Thread{
+		{ID: "th_1", Title: "Cannot log in", Status: "TODO", Priority: 0, Labels: []types.Label{{LabelType: types.LabelType{Name: "bug"}}}},
+		{ID: "th_2", Title: "Invoice is wrong", Status: "SNOOZED", Priority: 2, AssignedTo: &types.User{FullName: "Ada"}},
+		{ID: "th_3", Title: "Export to CSV", Status: "DONE", Priority: 3, Labels: []types.Label{{LabelType: types.LabelType{Name: "feature"}}}},
+	} {
+		at := &types.DateTime{ISO8601: now.Add(-time.Duration(i+1) * time.Hour).Format(time.RFC3339)}
+		th.CreatedAt, th.UpdatedAt = at, at
+		if err := writer.SaveThread(th); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source, err := OpenSource(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, syncedAt, err := source.stats.Coverage(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(-3 * time.Hour); !from.Equal(want.Truncate(time.Second)) || syncedAt.IsZero() {
+		t.Errorf("Coverage() = %v, %v, want %v and a sync time", from, syncedAt, want)
+	}
+	if source.SyncedAt().IsZero() {
+		t.Error("SyncedAt() is zero")
+	}
+
+	// Open threads are read page by page, most recently updated first
+	first, err := source.GetThreads(ctx, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Edges) != 1 || first.Edges[0].Node.ID != "th_1" || !first.PageInfo.HasNextPage {
+		t.Fatalf("GetThreads() first page = %+v", first)
+	}
+	second, err := source.GetThreads(ctx, 1, first.PageInfo.EndCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Edges) != 1 || second.Edges[0].Node.ID != "th_2" || second.PageInfo.HasNextPage {
+		t.Fatalf("GetThreads() second page = %+v", second)
+	}
+	if got := second.Edges[0].Node; got.Priority != 2 || got.AssignedTo == nil || got.AssignedTo.FullName != "Ada" {
+		t.Errorf("GetThreads() thread = %+v", got)
+	}
+
+	tests := []struct {
+		name   string
+		filter client.ThreadFilter
+		want   int
+	}{
+		{"labels by name", client.ThreadFilter{LabelTypeIDs: []string{"bug", "feature"}}, 2},
+		{"priority", client.ThreadFilter{Priorities: []int{0, 1}}, 1},
+		{"updated after", client.ThreadFilter{UpdatedAfter: now.Add(-150 * time.Minute).UTC().Format(time.RFC3339)}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, exact, err := source.CountThreads(ctx, tt.filter)
+			if err != nil || count != tt.want || !exact {
+				t.Errorf("CountThreads() = %d, %v, %v, want %d", count, exact, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := source.GetThreadsByFilter(ctx, client.ThreadFilter{CustomerIDs: []string{"c_1"}}, 10, ""); err == nil {
+		t.Error("GetThreadsByFilter() by customer succeeded offline")
+	}
+	labels, err := source.GetLabels(ctx)
+	if err != nil || len(labels) != 2 || labels[0].ID != "bug" {
+		t.Errorf("GetLabels() = %v, %v", labels, err)
+	}
+}
+
+func TestOpenSourceOlderStore(t *testing.T) {
+	cfg := config.DBConfig{Source: filepath.Join(t.TempDir(), "threads.db")}
+	db, err := gorm.Open(sqlite.Open(cfg.Source), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The threads table as written before priorities and assignees were stored
+	if err := db.Exec("CREATE TABLE threads (id text PRIMARY KEY, title text, status text, labels json, customer text, company text, created_at datetime, updated_at datetime)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenSource(cfg); err == nil || !strings.Contains(err.Error(), "simple report") {
+		t.Fatalf("OpenSource() error = %v, want a hint to run simple report", err)
+	}
+	if db.Migrator().HasColumn(&Threads{}, "Priority") {
+		t.Error("OpenSource() changed the schema of the store")
+	}
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"encoding/json"
+	"sort"
 	"time"
 
 	"simple/types"
@@ -16,9 +17,11 @@ type Threads struct {
 	ID        string `gorm:"primaryKey"`
 	Title     string
 	Status    string
+	Priority  *int           // nil for rows synced before priorities were stored
 	Labels    datatypes.JSON `gorm:"type:json"`
 	Customer  string
 	Company   string
+	Assignee  string
 	CreatedAt *time.Time `gorm:"autoCreateTime:false"`
 	UpdatedAt *time.Time `gorm:"autoUpdateTime:false"`
 }
@@ -35,6 +38,9 @@ func SQLiteInitDB(dbPath string) (*gorm.DB, error) {
 	if err := db.AutoMigrate(&Threads{}); err != nil {
 		return nil, err
 	}
+	if err := migrateSearch(db); err != nil {
+		return nil, err
+	}
 
 	return db, nil
 }
@@ -97,14 +103,40 @@ func SQLiteFromThread(t *types.Thread) *Threads {
 		}
 	}
 
+	assignee := ""
+	if t.AssignedTo != nil {
+		assignee = t.AssignedTo.FullName
+	}
+	priority := t.Priority
+
 	return &Threads{
 		ID:        t.ID,
 		Title:     t.Title,
 		Status:    t.Status,
+		Priority:  &priority,
 		Labels:    labelsJSON,
 		Customer:  customer,
 		Company:   company,
+		Assignee:  assignee,
 		CreatedAt: created,
 		UpdatedAt: updated,
 	}
 }
+
+// LabelNames returns the thread's label names. SQLite rows store them as an
+// array and Postgres rows as an object keyed by position.
+func (t *Threads) LabelNames() []string {
+	var names []string
+	if err := json.Unmarshal(t.Labels, &names); err == nil {
+		return names
+	}
+	var byKey map[string]string
+	if err := json.Unmarshal(t.Labels, &byKey); err != nil {
+		return nil
+	}
+	for _, name := range byKey {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"simple/config"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Stats answers questions about threads from the local store
+type Stats struct {
+	db *gorm.DB
+}
+
+// OpenStats opens the local thread store for reading. It returns nil without an
+// error when there is no store yet, so callers can fall back to the API.
+func OpenStats(cfg config.DBConfig) (*Stats, error) {
+	if cfg.Source == "" {
+		return nil, nil
+	}
+
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case "postgres":
+		dialector = postgres.Open(cfg.Source)
+	default:
+		// Opening a missing SQLite file would create an empty store
+		if _, err := os.Stat(cfg.Source); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		dialector = sqlite.Open(cfg.Source)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open local store: %w", err)
+	}
+	if !db.Migrator().HasTable(&Threads{}) {
+		return nil, nil
+	}
+
+	return &Stats{db: db}, nil
+}
+
+// DayBounds returns the start of each of the last days days, ending with today,
+// followed by now. Day i spans bounds[i] to bounds[i+1].
+func DayBounds(days int, now time.Time) []time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	bounds := make([]time.Time, 0, days+1)
+	for i := days - 1; i >= 0; i-- {
+		bounds = append(bounds, today.AddDate(0, 0, -i))
+	}
+	return append(bounds, now)
+}
+
+// CreatedPerDay counts the threads created on each day between the bounds
+func (s *Stats) CreatedPerDay(bounds []time.Time) ([]int, error) {
+	return s.perDay(bounds, func(start, end time.Time) *gorm.DB {
+		return s.db.Model(&Threads{}).Where("created_at >= ? AND created_at < ?", start.UTC(), end.UTC())
+	})
+}
+
+// DonePerDay counts the done threads last updated on each day between the bounds,
+// which is when they were closed unless they were touched again afterwards
+func (s *Stats) DonePerDay(bounds []time.Time) ([]int, error) {
+	return s.perDay(bounds, func(start, end time.Time) *gorm.DB {
+		return s.db.Model(&Threads{}).Where("status = ? AND updated_at >= ? AND updated_at < ?", "DONE", start.UTC(), end.UTC())
+	})
+}
+
+// BacklogPerDay estimates the open threads at the end of each day between the
+// bounds: threads created by then that are still open, or were closed later
+func (s *Stats) BacklogPerDay(bounds []time.Time) ([]int, error) {
+	return s.perDay(bounds, func(_, end time.Time) *gorm.DB {
+		return s.db.Model(&Threads{}).Where("created_at < ? AND (status <> ? OR updated_at >= ?)", end.UTC(), "DONE", end.UTC())
+	})
+}
+
+// perDay runs a count query for each day between the bounds
+func (s *Stats) perDay(bounds []time.Time, query func(start, end time.Time) *gorm.DB) ([]int, error) {
+	counts := make([]int, 0, len(bounds)-1)
+	for i := 0; i+1 < len(bounds); i++ {
+		var count int64
+		if err := query(bounds[i], bounds[i+1]).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to count threads in local store: %w", err)
+		}
+		counts = append(counts, int(count))
+	}
+	return counts, nil
+}
+
+// CountByStatus counts the threads in each status
+func (s *Stats) CountByStatus() (map[string]int, error) {
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	if err := s.db.Model(&Threads{}).Select("status, count(*) as count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count threads in local store: %w", err)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// OpenThreads returns the threads that aren't done
+func (s *Stats) OpenThreads() ([]Threads, error) {
+	var threads []Threads
+	if err := s.db.Where("status <> ?", "DONE").Find(&threads).Error; err != nil {
+		return nil, fmt.Errorf("failed to read threads from local store: %w", err)
+	}
+	return threads, nil
+}
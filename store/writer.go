@@ -0,0 +1,73 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"simple/config"
+	"simple/types"
+
+	"gorm.io/gorm"
+)
+
+// Writer updates thread rows in the local store as threads change
+type Writer struct {
+	db     *gorm.DB
+	driver string
+}
+
+// OpenWriter opens the local thread store for writing, creating it if needed
+func OpenWriter(cfg config.DBConfig) (*Writer, error) {
+	var db *gorm.DB
+	var err error
+	switch cfg.Driver {
+	case "postgres":
+		db, err = postgresOpen(cfg.Source)
+	default:
+		if err := os.MkdirAll(filepath.Dir(cfg.Source), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+		db, err = SQLiteInitDB(cfg.Source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open local store: %w", err)
+	}
+	return &Writer{db: db, driver: cfg.Driver}, nil
+}
+
+// SaveThread inserts or updates a thread's row. Webhook payloads may leave out
+// the customer's company, so known customer and company names are kept.
+// Threads older than the stored row, such as retried or late deliveries, are skipped.
+func (w *Writer) SaveThread(thread *types.Thread) error {
+	row := SQLiteFromThread(thread)
+	if w.driver == "postgres" {
+		row = PostgresFromThread(thread)
+	}
+
+	// Find rather than First, which logs every missing row as an error
+	var found []Threads
+	if err := w.db.Where("id = ?", row.ID).Limit(1).Find(&found).Error; err != nil {
+		return fmt.Errorf("failed to read thread %s: %w", row.ID, err)
+	}
+	if len(found) > 0 {
+		existing := found[0]
+		if existing.UpdatedAt != nil && row.UpdatedAt != nil && existing.UpdatedAt.After(*row.UpdatedAt) {
+			return nil
+		}
+		if row.Customer == "" {
+			row.Customer = existing.Customer
+		}
+		if row.Company == "" {
+			row.Company = existing.Company
+		}
+		if row.CreatedAt == nil {
+			row.CreatedAt = existing.CreatedAt
+		}
+	}
+
+	if err := w.db.Save(row).Error; err != nil {
+		return fmt.Errorf("failed to save thread %s: %w", row.ID, err)
+	}
+	return nil
+}
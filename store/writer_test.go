@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"simple/config"
+	"simple/types"
+)
+
+func TestWriterSkipsOlderThreads(t *testing.T) {
+	cfg := config.DBConfig{Source: filepath.Join(t.TempDir(), "threads.db")}
+	writer, err := OpenWriter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A status change, then the thread's creation delivered late
+	for _, th := range []*types.Thread{
+		{ID: "th_1", Title: "Cannot log in", Status: "DONE", UpdatedAt: &types.DateTime{ISO8601: "2024-05-01T12:00:00Z"}},
+		{ID: "th_1", Title: "Cannot log in", Status: "TODO", UpdatedAt: &types.DateTime{ISO8601: "2024-05-01T11:00:00Z"}},
+	} {
+		if err := writer.SaveThread(th); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source, err := OpenSource(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thread, err := source.GetThreadById(context.Background(), "th_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thread.Status != "DONE" {
+		t.Errorf("Status = %s, want the newer DONE", thread.Status)
+	}
+}
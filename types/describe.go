@@ -0,0 +1,261 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ActorName returns the display name of the entry's actor
+func (entry *TimelineEntry) ActorName() string {
+	if entry.Actor == nil {
+		return "Unknown"
+	}
+	if name := entry.Actor.GetFullName(); name != "" {
+		return name
+	}
+	return entry.Actor.GetID()
+}
+
+// IsInternal returns true for entries only visible to the support team, such as notes
+func (entry *TimelineEntry) IsInternal() bool {
+	_, ok := entry.Entry.(*NoteEntry)
+	return ok
+}
+
+// IsMessage returns true for entries carrying a conversation message rather than a thread event
+func (entry *TimelineEntry) IsMessage() bool {
+	switch entry.Entry.(type) {
+	case *EmailEntry, *ChatEntry, *NoteEntry, *SlackMessageEntry, *SlackReplyEntry, *CustomEntry:
+		return true
+	default:
+		return false
+	}
+}
+
+// Attachments returns the files attached to the entry
+func (entry *TimelineEntry) Attachments() []*Attachment {
+	switch e := entry.Entry.(type) {
+	case *EmailEntry:
+		return e.Attachments
+	case *ChatEntry:
+		return e.Attachments
+	case *NoteEntry:
+		return e.Attachments
+	case *CustomEntry:
+		return e.Attachments
+	case *SlackMessageEntry:
+		return e.Attachments
+	case *SlackReplyEntry:
+		return e.Attachments
+	default:
+		return nil
+	}
+}
+
+// Size formats the attachment size for display
+func (a *Attachment) Size() string {
+	if a.FileSize == nil {
+		return "unknown size"
+	}
+	return FormatBytes(int64(a.FileSize.Bytes))
+}
+
+// FormatBytes formats a byte count for display
+func FormatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// String formats an email participant as "Name <email>"
+func (p *EmailParticipant) String() string {
+	if p == nil {
+		return ""
+	}
+	if p.Name == "" {
+		return p.Email
+	}
+	if p.Email == "" {
+		return p.Name
+	}
+	return fmt.Sprintf("%s <%s>", p.Name, p.Email)
+}
+
+// Text returns the content of a timeline entry, or a description of the change for events
+func (entry *TimelineEntry) Text() string {
+	if entry.Entry == nil {
+		return ""
+	}
+
+	switch e := entry.Entry.(type) {
+	case *EmailEntry:
+		return e.TextContent
+	case *ChatEntry:
+		return e.Text
+	case *NoteEntry:
+		if e.Markdown != "" {
+			return e.Markdown
+		}
+		return e.Text
+	case *SlackMessageEntry:
+		return e.Text
+	case *SlackReplyEntry:
+		return e.Text
+	case *CustomEntry:
+		return e.Title
+
+	case *ThreadStatusTransitionedEntry:
+		return fmt.Sprintf("Status changed from %s to %s", e.PreviousStatus, e.NextStatus)
+	case *ThreadPriorityChangedEntry:
+		return fmt.Sprintf("Priority changed from %s to %s", priorityName(e.PreviousPriority), priorityName(e.NextPriority))
+	case *ThreadAssignmentTransitionedEntry:
+		return fmt.Sprintf("Assignment changed from %s to %s", assigneeName(e.PreviousAssignee), assigneeName(e.NextAssignee))
+	case *ThreadLabelsChangedEntry:
+		return labelsChangedText(e)
+	case *ServiceLevelAgreementStatusTransitionedEntry:
+		return fmt.Sprintf("SLA changed from %s to %s", e.PreviousStatus, e.NextStatus)
+	case *LinearIssueThreadLinkStateTransitionedEntry:
+		return fmt.Sprintf("Linear issue %s changed state", e.LinearIssueID)
+	case *ThreadDiscussionEntry:
+		if e.SlackChannelName != "" {
+			return fmt.Sprintf("Discussion started in #%s", e.SlackChannelName)
+		}
+		if len(e.EmailRecipients) > 0 {
+			return fmt.Sprintf("Discussion started with %s", strings.Join(e.EmailRecipients, ", "))
+		}
+		return "Discussion started"
+	case *ThreadDiscussionResolvedEntry:
+		if e.SlackChannelName != "" {
+			return fmt.Sprintf("Discussion in #%s resolved", e.SlackChannelName)
+		}
+		return "Discussion resolved"
+	case *ThreadEventEntry:
+		return e.Title
+	case *CustomerEventEntry:
+		return e.Title
+	case *UnknownEntry:
+		// Entry types added to Plain after this client still show their text, if any
+		for _, field := range []string{"text", "textContent", "markdown", "title", "content", "message", "description", "body", "value"} {
+			if value, ok := e.Fields[field].(string); ok && value != "" {
+				return value
+			}
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
+// assigneeName returns the display name of an assignee
+func assigneeName(assignee *Assignee) string {
+	if assignee == nil || assignee.FullName == "" {
+		return "None"
+	}
+	return assignee.FullName
+}
+
+// labelsChangedText describes the labels added to and removed from a thread
+func labelsChangedText(e *ThreadLabelsChangedEntry) string {
+	previous := make(map[string]bool, len(e.PreviousLabels))
+	for _, label := range e.PreviousLabels {
+		previous[label.LabelType.ID] = true
+	}
+	next := make(map[string]bool, len(e.NextLabels))
+	for _, label := range e.NextLabels {
+		next[label.LabelType.ID] = true
+	}
+
+	var changes []string
+	for _, label := range e.NextLabels {
+		if !previous[label.LabelType.ID] {
+			changes = append(changes, "+"+label.LabelType.Name)
+		}
+	}
+	for _, label := range e.PreviousLabels {
+		if !next[label.LabelType.ID] {
+			changes = append(changes, "-"+label.LabelType.Name)
+		}
+	}
+
+	if len(changes) == 0 {
+		return "Labels changed"
+	}
+	return "Labels changed: " + strings.Join(changes, ", ")
+}
+
+// Kind returns a readable type name for a timeline entry
+func (entry *TimelineEntry) Kind() string {
+	if entry.Entry == nil {
+		return "Event"
+	}
+
+	switch e := entry.Entry.(type) {
+	case *EmailEntry:
+		return "Email"
+	case *ChatEntry:
+		return "Chat"
+	case *NoteEntry:
+		return "Internal Note"
+	case *SlackMessageEntry:
+		return "Slack"
+	case *SlackReplyEntry:
+		return "Slack Reply"
+	case *CustomEntry:
+		return "Custom"
+
+	case *ThreadStatusTransitionedEntry:
+		return "Status Change"
+	case *ThreadPriorityChangedEntry:
+		return "Priority Change"
+	case *ThreadAssignmentTransitionedEntry:
+		return "Assignment"
+	case *ThreadLabelsChangedEntry:
+		return "Labels"
+	case *ServiceLevelAgreementStatusTransitionedEntry:
+		return "SLA"
+	case *LinearIssueThreadLinkStateTransitionedEntry:
+		return "Linear"
+	case *ThreadDiscussionEntry, *ThreadDiscussionResolvedEntry:
+		return "Discussion"
+	case *ThreadEventEntry, *CustomerEventEntry:
+		return "Event"
+	case *UnknownEntry:
+		// Turn e.g. "MSTeamsMessageEntry" into "MSTeams Message"
+		name := strings.TrimSuffix(e.Typename, "Entry")
+		if name == "" {
+			return "Event"
+		}
+		var words strings.Builder
+		for i, r := range name {
+			if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(name[i-1])) {
+				words.WriteRune(' ')
+			}
+			words.WriteRune(r)
+		}
+		return words.String()
+	default:
+		return "Event"
+	}
+}
+
+// priorityName converts a priority number to a readable string
+func priorityName(priority int) string {
+	switch priority {
+	case 0:
+		return "Urgent"
+	case 1:
+		return "High"
+	case 2:
+		return "Medium"
+	case 3:
+		return "Low"
+	default:
+		return fmt.Sprintf("P%d", priority)
+	}
+}
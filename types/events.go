@@ -0,0 +1,35 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Thread returns the thread carried by a thread event, or nil for other events
+func (e *Event) Thread() (*Thread, error) {
+	return e.payloadThread("thread")
+}
+
+// PreviousThread returns the thread as it was before a transition, or nil when
+// the event has none
+func (e *Event) PreviousThread() (*Thread, error) {
+	return e.payloadThread("previousThread")
+}
+
+// payloadThread decodes a thread from a payload field
+func (e *Event) payloadThread(field string) (*Thread, error) {
+	value, ok := e.Payload[field]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s of event %s: %w", field, e.ID, err)
+	}
+	var thread Thread
+	if err := json.Unmarshal(data, &thread); err != nil {
+		return nil, fmt.Errorf("failed to decode %s of event %s: %w", field, e.ID, err)
+	}
+	return &thread, nil
+}
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Snippets mark the matching terms with these, see Highlight
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// SnippetWords is roughly how many words of context a snippet shows
+const SnippetWords = 16
+
+// SearchTerms splits search terms into words, dropping punctuation as
+// full-text indexes do
+func SearchTerms(terms string) []string {
+	return strings.FieldsFunc(terms, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchSnippet makes a snippet for a thread found by a search that doesn't
+// say what matched, such as Plain's: the thread's latest message preview, or
+// its title when only that matches
+func (t *Thread) SearchSnippet(terms string) string {
+	words := SearchTerms(terms)
+	text := t.PreviewText
+	if text == "" || (!containsAny(text, words) && containsAny(t.Title, words)) {
+		text = t.Title
+	}
+	return Snippet(text, words)
+}
+
+// containsAny returns true if text contains one of the words, ignoring case
+func containsAny(text string, words []string) bool {
+	lower := strings.ToLower(text)
+	for _, word := range words {
+		if strings.Contains(lower, strings.ToLower(word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Snippet cuts the text around the first word matching a term and marks every match
+func Snippet(text string, terms []string) string {
+	words := strings.Fields(text)
+	first := 0
+	for i := len(words) - 1; i >= 0; i-- {
+		for _, term := range terms {
+			if strings.Contains(strings.ToLower(words[i]), strings.ToLower(term)) {
+				first = i
+			}
+		}
+	}
+
+	// Keep a few words before the match for context
+	start := max(0, first-SnippetWords/4)
+	end := min(len(words), start+SnippetWords)
+	snippet := markTerms(strings.Join(words[start:end], " "), terms)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(words) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// markTerms wraps every occurrence of the terms in highlight markers, ignoring case
+func markTerms(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	// Lowercasing changes the length of a few characters, which would shift the marks
+	if len(lower) != len(text) {
+		return text
+	}
+
+	marked := make([]bool, len(text))
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		for i := 0; term != "" && i < len(lower); {
+			j := strings.Index(lower[i:], term)
+			if j < 0 {
+				break
+			}
+			for k := i + j; k < i+j+len(term); k++ {
+				marked[k] = true
+			}
+			i += j + len(term)
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(HighlightStart)
+		}
+		b.WriteByte(text[i])
+		if marked[i] && (i == len(text)-1 || !marked[i+1]) {
+			b.WriteString(HighlightEnd)
+		}
+	}
+	return b.String()
+}
+
+// Highlight renders a snippet's marked matches with mark
+func Highlight(snippet string, mark func(string) string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(snippet, HighlightStart)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(snippet[start:], HighlightEnd)
+		if end < 0 {
+			break
+		}
+		b.WriteString(snippet[:start])
+		b.WriteString(mark(snippet[start+len(HighlightStart) : start+end]))
+		snippet = snippet[start+end+len(HighlightEnd):]
+	}
+	b.WriteString(snippet)
+	return strings.ReplaceAll(strings.ReplaceAll(b.String(), HighlightStart, ""), HighlightEnd, "")
+}
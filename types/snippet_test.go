@@ -0,0 +1,33 @@
+package types
+
+import "testing"
+
+func TestSnippet(t *testing.T) {
+	text := "one two three four five six seven eight nine ten eleven twelve thirteen fourteen fifteen sixteen seventeen Error eighteen"
+	want := "…fourteen fifteen sixteen seventeen [Error] eighteen"
+	if got := Highlight(Snippet(text, []string{"error"}), func(s string) string { return "[" + s + "]" }); got != want {
+		t.Errorf("Snippet() = %q, want %q", got, want)
+	}
+}
+
+func TestThreadSearchSnippet(t *testing.T) {
+	mark := func(s string) string { return "[" + s + "]" }
+	tests := []struct {
+		name   string
+		thread Thread
+		terms  string
+		want   string
+	}{
+		{"preview match", Thread{Title: "Export fails", PreviewText: "Still ECONNRESET here"}, "econnreset", "Still [ECONNRESET] here"},
+		{"title match", Thread{Title: "Export fails", PreviewText: "Any news?"}, "export", "[Export] fails"},
+		{"no match shows the preview", Thread{Title: "Export fails", PreviewText: "Any news?"}, "invoice", "Any news?"},
+		{"no preview", Thread{Title: "Export fails"}, "invoice", "Export fails"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.thread.SearchSnippet(tt.terms), mark); got != tt.want {
+				t.Errorf("SearchSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
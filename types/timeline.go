@@ -0,0 +1,272 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// entryTypes maps the GraphQL __typename of timeline entries to constructors of their Go types
+var entryTypes = map[string]func() Entry{
+	"EmailEntry":                                   func() Entry { return &EmailEntry{} },
+	"ChatEntry":                                    func() Entry { return &ChatEntry{} },
+	"NoteEntry":                                    func() Entry { return &NoteEntry{} },
+	"CustomEntry":                                  func() Entry { return &CustomEntry{} },
+	"SlackMessageEntry":                            func() Entry { return &SlackMessageEntry{} },
+	"SlackReplyEntry":                              func() Entry { return &SlackReplyEntry{} },
+	"ThreadAssignmentTransitionedEntry":            func() Entry { return &ThreadAssignmentTransitionedEntry{} },
+	"ThreadStatusTransitionedEntry":                func() Entry { return &ThreadStatusTransitionedEntry{} },
+	"ThreadPriorityChangedEntry":                   func() Entry { return &ThreadPriorityChangedEntry{} },
+	"ThreadLabelsChangedEntry":                     func() Entry { return &ThreadLabelsChangedEntry{} },
+	"ServiceLevelAgreementStatusTransitionedEntry": func() Entry { return &ServiceLevelAgreementStatusTransitionedEntry{} },
+	"LinearIssueThreadLinkStateTransitionedEntry":  func() Entry { return &LinearIssueThreadLinkStateTransitionedEntry{} },
+	"ThreadDiscussionEntry":                        func() Entry { return &ThreadDiscussionEntry{} },
+	"ThreadDiscussionResolvedEntry":                func() Entry { return &ThreadDiscussionResolvedEntry{} },
+	"ThreadEventEntry":                             func() Entry { return &ThreadEventEntry{} },
+	"CustomerEventEntry":                           func() Entry { return &CustomerEventEntry{} },
+}
+
+// actorTypes maps the GraphQL __typename of actors to constructors of their Go types
+var actorTypes = map[string]func() Actor{
+	"UserActor":            func() Actor { return &UserActor{} },
+	"CustomerActor":        func() Actor { return &CustomerActor{} },
+	"DeletedCustomerActor": func() Actor { return &DeletedCustomerActor{} },
+	"SystemActor":          func() Actor { return &SystemActor{} },
+	"MachineUserActor":     func() Actor { return &MachineUserActor{} },
+}
+
+// RegisterEntryType registers the Go type a timeline entry __typename decodes into.
+// Entries with unregistered typenames decode into an UnknownEntry.
+func RegisterEntryType(typename string, constructor func() Entry) {
+	entryTypes[typename] = constructor
+}
+
+// typename reads the __typename discriminator of a GraphQL object
+func typename(data json.RawMessage) (string, error) {
+	var discriminator struct {
+		Typename string `json:"__typename"`
+	}
+	if err := json.Unmarshal(data, &discriminator); err != nil {
+		return "", err
+	}
+	return discriminator.Typename, nil
+}
+
+// decodeEntry decodes a timeline entry using its __typename
+func decodeEntry(data json.RawMessage) (Entry, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	name, err := typename(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse entry type: %w, raw data: %s", err, string(data))
+	}
+
+	constructor, ok := entryTypes[name]
+	if !ok {
+		unknown := &UnknownEntry{Typename: name, Raw: append(json.RawMessage(nil), data...)}
+		if err := json.Unmarshal(data, &unknown.Fields); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", unknownTypename(name), err)
+		}
+		return unknown, nil
+	}
+
+	entry := constructor()
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", name, err)
+	}
+	return entry, nil
+}
+
+// decodeActor decodes an actor using its __typename, falling back to the fields
+// present for responses that were fetched without __typename
+func decodeActor(data json.RawMessage) (Actor, error) {
+	name, err := typename(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if constructor, ok := actorTypes[name]; ok {
+		actor := constructor()
+		if err := json.Unmarshal(data, actor); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", name, err)
+		}
+		return actor, nil
+	}
+
+	var actorType struct {
+		User        *User        `json:"user"`
+		Customer    *Customer    `json:"customer"`
+		CustomerID  *string      `json:"customerId"`
+		SystemID    *string      `json:"systemId"`
+		MachineUser *MachineUser `json:"machineUser"`
+	}
+	if err := json.Unmarshal(data, &actorType); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case actorType.User != nil:
+		return &UserActor{User: actorType.User}, nil
+	case actorType.Customer != nil:
+		return &CustomerActor{Customer: actorType.Customer}, nil
+	case actorType.CustomerID != nil:
+		return &DeletedCustomerActor{CustomerID: *actorType.CustomerID}, nil
+	case actorType.SystemID != nil:
+		return &SystemActor{SystemID: *actorType.SystemID}, nil
+	case actorType.MachineUser != nil:
+		return &MachineUserActor{MachineUser: actorType.MachineUser}, nil
+	}
+	return nil, nil
+}
+
+// unknownTypename names an entry type in error messages
+func unknownTypename(name string) string {
+	if name == "" {
+		return "entry without __typename"
+	}
+	return name
+}
+
+// UnknownEntry is a timeline entry whose __typename has no registered Go type.
+// Its fields are kept so it can still be rendered.
+type UnknownEntry struct {
+	Typename string
+	Raw      json.RawMessage
+	Fields   map[string]interface{}
+}
+
+// ThreadLabelsChangedEntry represents labels being added to or removed from a thread
+type ThreadLabelsChangedEntry struct {
+	PreviousLabels []Label `json:"previousLabels"`
+	NextLabels     []Label `json:"nextLabels"`
+}
+
+// ServiceLevelAgreement identifies the SLA of a status transition
+type ServiceLevelAgreement struct {
+	Typename string `json:"__typename"`
+	ID       string `json:"id"`
+}
+
+// ServiceLevelAgreementStatusTransitionedEntry represents an SLA becoming imminent, breached or achieved.
+// The statuses are aliased in queries as they clash with ThreadStatusTransitionedEntry's.
+type ServiceLevelAgreementStatusTransitionedEntry struct {
+	PreviousStatus        string                 `json:"previousSlaStatus"`
+	NextStatus            string                 `json:"nextSlaStatus"`
+	ServiceLevelAgreement *ServiceLevelAgreement `json:"serviceLevelAgreement"`
+}
+
+// LinearIssueThreadLinkStateTransitionedEntry represents a linked Linear issue changing state
+type LinearIssueThreadLinkStateTransitionedEntry struct {
+	LinearIssueID         string `json:"linearIssueId"`
+	PreviousLinearStateID string `json:"previousLinearStateId"`
+	NextLinearStateID     string `json:"nextLinearStateId"`
+}
+
+// ThreadDiscussionEntry represents a discussion about a thread being started in Slack or by email
+type ThreadDiscussionEntry struct {
+	ThreadDiscussionID string   `json:"threadDiscussionId"`
+	SlackChannelName   string   `json:"slackChannelName"`
+	SlackMessageLink   string   `json:"slackMessageLink"`
+	EmailRecipients    []string `json:"emailRecipients"`
+}
+
+// ThreadDiscussionResolvedEntry represents a thread discussion being resolved
+type ThreadDiscussionResolvedEntry struct {
+	ThreadDiscussionID string    `json:"threadDiscussionId"`
+	SlackChannelName   string    `json:"slackChannelName"`
+	SlackMessageLink   string    `json:"slackMessageLink"`
+	ResolvedAt         *DateTime `json:"resolvedAt"`
+}
+
+// ThreadEventEntry represents a custom event recorded on a thread
+type ThreadEventEntry struct {
+	TimelineEventID string `json:"timelineEventId"`
+	Title           string `json:"title"`
+	ExternalID      string `json:"externalId"`
+}
+
+// CustomerEventEntry represents a custom event recorded on the thread's customer
+type CustomerEventEntry struct {
+	TimelineEventID string `json:"timelineEventId"`
+	Title           string `json:"title"`
+	ExternalID      string `json:"externalId"`
+}
+
+// Assignee represents a user or machine user a thread is assigned to
+type Assignee struct {
+	Typename string `json:"__typename"`
+	ID       string `json:"id"`
+	FullName string `json:"fullName"`
+	Email    string `json:"email"`
+}
+
+// textAliases holds the aliased text fields used in timeline queries, since the
+// text fields of the entry types have conflicting nullability in Plain's schema
+type textAliases struct {
+	ChatText  *string `json:"chatText"`
+	NoteText  *string `json:"noteText"`
+	SlackText *string `json:"slackText"`
+}
+
+// aliasedText returns the first aliased text field present
+func aliasedText(data []byte) (string, bool) {
+	var aliases textAliases
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return "", false
+	}
+	for _, text := range []*string{aliases.ChatText, aliases.NoteText, aliases.SlackText} {
+		if text != nil {
+			return *text, true
+		}
+	}
+	return "", false
+}
+
+// UnmarshalJSON accepts the chatText alias for text
+func (e *ChatEntry) UnmarshalJSON(data []byte) error {
+	type Alias ChatEntry
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
+		return err
+	}
+	if text, ok := aliasedText(data); ok {
+		e.Text = text
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts the noteText alias for text
+func (e *NoteEntry) UnmarshalJSON(data []byte) error {
+	type Alias NoteEntry
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
+		return err
+	}
+	if text, ok := aliasedText(data); ok {
+		e.Text = text
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts the slackText alias for text
+func (e *SlackMessageEntry) UnmarshalJSON(data []byte) error {
+	type Alias SlackMessageEntry
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
+		return err
+	}
+	if text, ok := aliasedText(data); ok {
+		e.Text = text
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts the slackText alias for text
+func (e *SlackReplyEntry) UnmarshalJSON(data []byte) error {
+	type Alias SlackReplyEntry
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
+		return err
+	}
+	if text, ok := aliasedText(data); ok {
+		e.Text = text
+	}
+	return nil
+}
@@ -57,11 +57,21 @@ type Thread struct {
 	Priority        int                      `json:"priority"`
 	Customer        *Customer                `json:"customer"`
 	Assignee        *User                    `json:"assignee"`
+	AssignedTo      *User                    `json:"assignedTo"`
 	Messages        []*Message               `json:"messages"`
 	TimelineEntries *TimelineEntryConnection `json:"timelineEntries"`
 	Labels          []Label                  `json:"labels"`
 	CreatedAt       *DateTime                `json:"createdAt"`
 	UpdatedAt       *DateTime                `json:"updatedAt"`
+	// LastInboundMessageInfo describes the customer's latest message, when it was fetched
+	LastInboundMessageInfo *MessageInfo `json:"lastInboundMessageInfo"`
+	// PreviewText is the start of the thread's latest message, when it was fetched
+	PreviewText string `json:"previewText,omitempty"`
+}
+
+// MessageInfo describes a message without its content
+type MessageInfo struct {
+	Timestamp *DateTime `json:"timestamp"`
 }
 
 // ThreadEdge represents a thread edge in a connection
@@ -76,6 +86,23 @@ type ThreadConnection struct {
 	PageInfo *PageInfo     `json:"pageInfo"`
 }
 
+// ThreadSearchResult is a thread found by Plain's thread search
+type ThreadSearchResult struct {
+	Thread *Thread `json:"thread"`
+}
+
+// ThreadSearchResultEdge represents a search result edge in a connection
+type ThreadSearchResultEdge struct {
+	Node   *ThreadSearchResult `json:"node"`
+	Cursor string              `json:"cursor"`
+}
+
+// ThreadSearchResultConnection represents a paginated connection of search results
+type ThreadSearchResultConnection struct {
+	Edges    []*ThreadSearchResultEdge `json:"edges"`
+	PageInfo *PageInfo                 `json:"pageInfo"`
+}
+
 // Message represents a message in a thread
 type Message struct {
 	ID        string    `json:"id"`
@@ -99,33 +126,14 @@ func (m *Message) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	// Parse the sentBy field to determine its type
-	var actorType struct {
-		User        *User        `json:"user"`
-		Customer    *Customer    `json:"customer"`
-		CustomerID  *string      `json:"customerId"`
-		SystemID    *string      `json:"systemId"`
-		MachineUser *MachineUser `json:"machineUser"`
-	}
-
-	if err := json.Unmarshal(aux.SentBy, &actorType); err != nil {
+	sentBy, err := decodeActor(aux.SentBy)
+	if err != nil {
 		return err
 	}
-
-	// Assign the appropriate actor type
-	if actorType.User != nil {
-		m.SentBy = &UserActor{User: actorType.User}
-	} else if actorType.Customer != nil {
-		m.SentBy = &CustomerActor{Customer: actorType.Customer}
-	} else if actorType.CustomerID != nil {
-		m.SentBy = &DeletedCustomerActor{CustomerID: *actorType.CustomerID}
-	} else if actorType.SystemID != nil {
-		m.SentBy = &SystemActor{SystemID: *actorType.SystemID}
-	} else if actorType.MachineUser != nil {
-		m.SentBy = &MachineUserActor{MachineUser: actorType.MachineUser}
-	} else {
+	if sentBy == nil {
 		return fmt.Errorf("unknown actor type for message sentBy")
 	}
+	m.SentBy = sentBy
 
 	return nil
 }
@@ -154,116 +162,21 @@ func (te *TimelineEntry) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	// Parse the actor field to determine its type
-	var actorType struct {
-		User        *User        `json:"user"`
-		Customer    *Customer    `json:"customer"`
-		CustomerID  *string      `json:"customerId"`
-		SystemID    *string      `json:"systemId"`
-		MachineUser *MachineUser `json:"machineUser"`
-	}
-
-	if err := json.Unmarshal(aux.Actor, &actorType); err != nil {
+	actor, err := decodeActor(aux.Actor)
+	if err != nil {
 		return err
 	}
-
-	// Assign the appropriate actor type
-	if actorType.User != nil {
-		te.Actor = &UserActor{User: actorType.User}
-	} else if actorType.Customer != nil {
-		te.Actor = &CustomerActor{Customer: actorType.Customer}
-	} else if actorType.CustomerID != nil {
-		te.Actor = &DeletedCustomerActor{CustomerID: *actorType.CustomerID}
-	} else if actorType.SystemID != nil {
-		te.Actor = &SystemActor{SystemID: *actorType.SystemID}
-	} else if actorType.MachineUser != nil {
-		te.Actor = &MachineUserActor{MachineUser: actorType.MachineUser}
-	} else {
+	if actor == nil {
 		return fmt.Errorf("unknown actor type")
 	}
+	te.Actor = actor
 
-	// Handle null or empty entry
-	if len(aux.Entry) == 0 || string(aux.Entry) == "null" {
-		te.Entry = nil
-		return nil
-	}
-
-	// Parse the entry field to determine its type
-	var entryType struct {
-		EmailID             *string           `json:"emailId"`
-		TextContent         *string           `json:"textContent"`
-		From                *EmailParticipant `json:"from"`
-		To                  *EmailParticipant `json:"to"`
-		ChatID              *string           `json:"chatId"`
-		Text                *string           `json:"text"`
-		NoteID              *string           `json:"noteId"`
-		Title               *string           `json:"title"`
-		Components          []interface{}     `json:"components"`
-		SlackMessageLink    *string           `json:"slackMessageLink"`
-		SlackWebMessageLink *string           `json:"slackWebMessageLink"`
-	}
-
-	if err := json.Unmarshal(aux.Entry, &entryType); err != nil {
-		return fmt.Errorf("failed to parse entry type: %w, raw data: %s", err, string(aux.Entry))
-	}
-
-	// Assign the appropriate entry type based on discriminator fields
-	if entryType.EmailID != nil {
-		emailEntry := &EmailEntry{}
-		if err := json.Unmarshal(aux.Entry, emailEntry); err != nil {
-			return fmt.Errorf("failed to unmarshal EmailEntry: %w", err)
-		}
-		te.Entry = emailEntry
-	} else if entryType.ChatID != nil {
-		chatEntry := &ChatEntry{}
-		if err := json.Unmarshal(aux.Entry, chatEntry); err != nil {
-			return fmt.Errorf("failed to unmarshal ChatEntry: %w", err)
-		}
-		te.Entry = chatEntry
-	} else if entryType.NoteID != nil {
-		noteEntry := &NoteEntry{}
-		if err := json.Unmarshal(aux.Entry, noteEntry); err != nil {
-			return fmt.Errorf("failed to unmarshal NoteEntry: %w", err)
-		}
-		te.Entry = noteEntry
-	} else if entryType.Title != nil && entryType.Components != nil {
-		customEntry := &CustomEntry{}
-		if err := json.Unmarshal(aux.Entry, customEntry); err != nil {
-			return fmt.Errorf("failed to unmarshal CustomEntry: %w", err)
-		}
-		te.Entry = customEntry
-	} else if entryType.SlackMessageLink != nil || entryType.SlackWebMessageLink != nil {
-		// Check for reply vs message based on additional context in raw JSON
-		var rawSlack map[string]interface{}
-		if err := json.Unmarshal(aux.Entry, &rawSlack); err != nil {
-			return fmt.Errorf("failed to unmarshal Slack entry: %w", err)
-		}
-
-		// If it has thread/parent message indicators, treat as reply
-		if _, hasThreadTs := rawSlack["threadTs"]; hasThreadTs {
-			slackReplyEntry := &SlackReplyEntry{}
-			if err := json.Unmarshal(aux.Entry, slackReplyEntry); err != nil {
-				return fmt.Errorf("failed to unmarshal SlackReplyEntry: %w", err)
-			}
-			te.Entry = slackReplyEntry
-		} else {
-			slackMessageEntry := &SlackMessageEntry{}
-			if err := json.Unmarshal(aux.Entry, slackMessageEntry); err != nil {
-				return fmt.Errorf("failed to unmarshal SlackMessageEntry: %w", err)
-			}
-			te.Entry = slackMessageEntry
-		}
-	} else {
-		// Handle all other entry types by storing the raw JSON as a map
-		// This includes: ThreadAssignmentTransitionedEntry, ThreadStatusTransitionedEntry,
-		// ThreadPriorityChangedEntry, SlackMessageEntry, ThreadLabelsChangedEntry,
-		// ServiceLevelAgreementStatusTransitionedEntry, etc.
-		var rawEntry map[string]interface{}
-		if err := json.Unmarshal(aux.Entry, &rawEntry); err != nil {
-			return fmt.Errorf("failed to unmarshal entry type: %w, raw data: %s", err, string(aux.Entry))
-		}
-		te.Entry = rawEntry
+	// Entries are decoded by __typename, unregistered types become an UnknownEntry
+	entry, err := decodeEntry(aux.Entry)
+	if err != nil {
+		return err
 	}
+	te.Entry = entry
 
 	return nil
 }
@@ -289,12 +202,14 @@ type EmailEntry struct {
 	TextContent string            `json:"textContent"`
 	From        *EmailParticipant `json:"from"`
 	To          *EmailParticipant `json:"to"`
+	Attachments []*Attachment     `json:"attachments"`
 }
 
 // ChatEntry represents a chat timeline entry
 type ChatEntry struct {
-	ChatID string `json:"chatId"`
-	Text   string `json:"text"`
+	ChatID      string        `json:"chatId"`
+	Text        string        `json:"text"`
+	Attachments []*Attachment `json:"attachments"`
 }
 
 // NoteEntry represents a note timeline entry
@@ -305,6 +220,14 @@ type NoteEntry struct {
 	Attachments []*Attachment `json:"attachments"`
 }
 
+// Note represents an internal note created on a thread
+type Note struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	Markdown  string    `json:"markdown"`
+	CreatedAt *DateTime `json:"createdAt"`
+}
+
 // CustomEntry represents a custom timeline entry
 type CustomEntry struct {
 	ExternalID  string                   `json:"externalId"`
@@ -316,8 +239,8 @@ type CustomEntry struct {
 
 // ThreadAssignmentTransitionedEntry represents a thread assignment change
 type ThreadAssignmentTransitionedEntry struct {
-	PreviousAssignee interface{} `json:"previousAssignee"`
-	NextAssignee     interface{} `json:"nextAssignee"`
+	PreviousAssignee *Assignee `json:"previousAssignee"`
+	NextAssignee     *Assignee `json:"nextAssignee"`
 }
 
 // ThreadStatusTransitionedEntry represents a thread status change
@@ -566,16 +489,32 @@ func (c *Customer) GetEmail() string {
 	return ""
 }
 
+// Label represents a label type applied to a thread
 type Label struct {
+	ID        string    `json:"id"`
 	LabelType LabelType `json:"labelType"`
 }
 
 // LabelType represents a Plain label
 type LabelType struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Icon      *string   `json:"icon"`
-	CreatedAt *DateTime `json:"createdAt"`
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Icon       *string   `json:"icon"`
+	Color      *string   `json:"color"`
+	IsArchived bool      `json:"isArchived"`
+	CreatedAt  *DateTime `json:"createdAt"`
+}
+
+// LabelTypeEdge represents a label type edge in a connection
+type LabelTypeEdge struct {
+	Node   *LabelType `json:"node"`
+	Cursor string     `json:"cursor"`
+}
+
+// LabelTypeConnection represents a paginated connection of label types
+type LabelTypeConnection struct {
+	Edges    []*LabelTypeEdge `json:"edges"`
+	PageInfo *PageInfo        `json:"pageInfo"`
 }
 
 // PageInfo represents pagination information
@@ -602,25 +541,54 @@ type Company struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
 	Domain    string    `json:"domain"`
+	Tier      *Tier     `json:"tier"`
 	CreatedAt *DateTime `json:"createdAt"`
 	UpdatedAt *DateTime `json:"updatedAt"`
 }
 
+// CompanyEdge represents a company edge in a connection
+type CompanyEdge struct {
+	Node   *Company `json:"node"`
+	Cursor string   `json:"cursor"`
+}
+
+// CompanyConnection represents a paginated connection of companies
+type CompanyConnection struct {
+	Edges    []*CompanyEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
 // Tenant represents a Plain tenant
 type Tenant struct {
 	ID         string    `json:"id"`
 	Name       string    `json:"name"`
 	PublicName string    `json:"publicName"`
+	ExternalID string    `json:"externalId"`
+	Tier       *Tier     `json:"tier"`
 	CreatedAt  *DateTime `json:"createdAt"`
 	UpdatedAt  *DateTime `json:"updatedAt"`
 }
 
-// Event represents a Plain event
+// TenantEdge represents a tenant edge in a connection
+type TenantEdge struct {
+	Node   *Tenant `json:"node"`
+	Cursor string  `json:"cursor"`
+}
+
+// TenantConnection represents a paginated connection of tenants
+type TenantConnection struct {
+	Edges    []*TenantEdge `json:"edges"`
+	PageInfo *PageInfo     `json:"pageInfo"`
+}
+
+// Event represents a Plain event, such as a webhook delivery
 type Event struct {
-	ID        string                 `json:"id"`
-	Type      string                 `json:"type"`
-	Payload   map[string]interface{} `json:"payload"`
-	CreatedAt *DateTime              `json:"createdAt"`
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Payload     map[string]interface{} `json:"payload"`
+	CreatedAt   *DateTime              `json:"createdAt"`
+	Timestamp   string                 `json:"timestamp,omitempty"`
+	WorkspaceID string                 `json:"workspaceId,omitempty"`
 }
 
 // Tier represents a Plain tier
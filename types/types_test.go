@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 )
 
@@ -227,6 +228,106 @@ func TestUnknownActorType(t *testing.T) {
 	}
 }
 
+func TestTimelineEntryTypenameDispatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    string
+		wantType string
+		check    func(t *testing.T, entry Entry)
+	}{
+		{
+			name:     "ChatEntry with aliased text",
+			entry:    `{"__typename": "ChatEntry", "chatId": "chat_1", "chatText": "Hi there"}`,
+			wantType: "*types.ChatEntry",
+			check: func(t *testing.T, entry Entry) {
+				if text := entry.(*ChatEntry).Text; text != "Hi there" {
+					t.Errorf("Expected text 'Hi there', got '%s'", text)
+				}
+			},
+		},
+		{
+			name:     "ThreadLabelsChangedEntry",
+			entry:    `{"__typename": "ThreadLabelsChangedEntry", "previousLabels": [], "nextLabels": [{"id": "l_1", "labelType": {"id": "lt_1", "name": "Bug"}}]}`,
+			wantType: "*types.ThreadLabelsChangedEntry",
+			check: func(t *testing.T, entry Entry) {
+				labels := entry.(*ThreadLabelsChangedEntry).NextLabels
+				if len(labels) != 1 || labels[0].LabelType.Name != "Bug" {
+					t.Errorf("Expected next label 'Bug', got %+v", labels)
+				}
+			},
+		},
+		{
+			name:     "ServiceLevelAgreementStatusTransitionedEntry",
+			entry:    `{"__typename": "ServiceLevelAgreementStatusTransitionedEntry", "previousSlaStatus": "PENDING", "nextSlaStatus": "BREACHED"}`,
+			wantType: "*types.ServiceLevelAgreementStatusTransitionedEntry",
+			check: func(t *testing.T, entry Entry) {
+				if status := entry.(*ServiceLevelAgreementStatusTransitionedEntry).NextStatus; status != "BREACHED" {
+					t.Errorf("Expected next status 'BREACHED', got '%s'", status)
+				}
+			},
+		},
+		{
+			name:     "unknown typename",
+			entry:    `{"__typename": "MSTeamsMessageEntry", "text": "From Teams"}`,
+			wantType: "*types.UnknownEntry",
+			check: func(t *testing.T, entry Entry) {
+				unknown := entry.(*UnknownEntry)
+				if unknown.Typename != "MSTeamsMessageEntry" {
+					t.Errorf("Expected typename 'MSTeamsMessageEntry', got '%s'", unknown.Typename)
+				}
+				if unknown.Fields["text"] != "From Teams" {
+					t.Errorf("Expected text field 'From Teams', got '%v'", unknown.Fields["text"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonData := `{
+				"id": "te_200",
+				"timestamp": {"iso8601": "2023-01-01T00:00:00Z"},
+				"actor": {"__typename": "SystemActor", "systemId": "system_1"},
+				"entry": ` + tt.entry + `
+			}`
+
+			var entry TimelineEntry
+			if err := json.Unmarshal([]byte(jsonData), &entry); err != nil {
+				t.Fatalf("Failed to unmarshal JSON: %v", err)
+			}
+
+			if actualType := fmt.Sprintf("%T", entry.Entry); actualType != tt.wantType {
+				t.Fatalf("Expected entry type %s, got %s", tt.wantType, actualType)
+			}
+			tt.check(t, entry.Entry)
+		})
+	}
+}
+
+func TestActorTypenameDispatch(t *testing.T) {
+	jsonData := `{
+		"id": "te_201",
+		"timestamp": {"iso8601": "2023-01-01T00:00:00Z"},
+		"actor": {
+			"__typename": "MachineUserActor",
+			"machineUser": {"id": "machine_1", "fullName": "Triage Bot"}
+		},
+		"entry": null
+	}`
+
+	var entry TimelineEntry
+	if err := json.Unmarshal([]byte(jsonData), &entry); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	if actualType := getTypeName(entry.Actor); actualType != "*types.MachineUserActor" {
+		t.Fatalf("Expected actor type *types.MachineUserActor, got %s", actualType)
+	}
+	if entry.Actor.GetFullName() != "Triage Bot" {
+		t.Errorf("Expected actor name Triage Bot, got %s", entry.Actor.GetFullName())
+	}
+}
+
 // Helper function to get the type name of an interface
 func getTypeName(v interface{}) string {
 	switch v.(type) {
@@ -244,3 +345,21 @@ func getTypeName(v interface{}) string {
 		return "unknown"
 	}
 }
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{5 << 20, "5.0 MB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.bytes); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
@@ -20,6 +20,8 @@ type AppState int
 const (
 	StateThreads AppState = iota
 	StateDashboard
+	StateCustomer
+	StateSearch
 )
 
 // MainModel represents the main application model
@@ -29,6 +31,10 @@ type MainModel struct {
 	state         AppState
 	threadsView   *ThreadsView
 	dashboardView *DashboardView
+	customerView  *CustomerView
+	searchView    *SearchView
+	notifier      *Notifier
+	keys          keyMap
 	quitting      bool
 	width         int
 	height        int
@@ -44,24 +50,48 @@ func NewApp(cfg *config.Config) (*App, error) {
 	}, nil
 }
 
-// NewMainModel creates a new main model
-func NewMainModel(cfg *config.Config, client *client.PlainClient) *MainModel {
-	threadsView := NewThreadsView(cfg, client)
-	dashboardView := NewDashboardView(cfg, client)
+// NewMainModel creates a new main model reading threads from source
+func NewMainModel(cfg *config.Config, source client.DataSource) *MainModel {
+	// Customer profiles and notifications always need the API. Offline, stored
+	// threads have no customer IDs to open and notifications are off.
+	plainClient, online := source.(*client.PlainClient)
+	if !online {
+		plainClient = client.NewPlainClient(cfg)
+	}
+	notifier := NewNotifier(cfg, plainClient)
+	if !online {
+		notifier.config.Enabled = false
+	}
+	var searchClient *client.PlainClient
+	if online {
+		searchClient = plainClient
+	}
 
 	return &MainModel{
 		config:        cfg,
-		client:        client,
+		client:        plainClient,
 		state:         StateThreads,
-		threadsView:   threadsView,
-		dashboardView: dashboardView,
+		threadsView:   NewThreadsView(cfg, source),
+		dashboardView: NewDashboardView(cfg, source),
+		customerView:  NewCustomerView(cfg, plainClient),
+		searchView:    NewSearchView(cfg, searchClient, &localStats{cfg: cfg.DB}),
+		notifier:      notifier,
+		keys:          newKeyMap(cfg),
 		quitting:      false,
 	}
 }
 
 // Init initializes the main model
 func (m *MainModel) Init() tea.Cmd {
-	return m.threadsView.Init()
+	return tea.Batch(m.threadsView.Init(), m.tickStatus(), m.notifier.Init())
+}
+
+// tickStatus redraws the "updated Ns ago" status every second while polling is on
+func (m *MainModel) tickStatus() tea.Cmd {
+	if m.config.UI.RefreshInterval <= 0 {
+		return nil
+	}
+	return pollAfter(statusTickInterval, statusTickMsg{})
 }
 
 // Update handles messages and updates the model
@@ -82,18 +112,86 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+		m.customerView, cmd = m.customerView.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		m.searchView, cmd = m.searchView.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 		return m, tea.Batch(cmds...)
 
+	case openCustomerMsg:
+		m.state = StateCustomer
+		return m, m.customerView.Load(msg.customerID)
+
+	case closeCustomerMsg:
+		m.state = StateThreads
+		return m, nil
+
+	case openSearchMsg:
+		m.state = StateSearch
+		return m, m.searchView.Open()
+
+	case closeSearchMsg:
+		m.state = StateThreads
+		return m, nil
+
+	case searchResultsMsg:
+		var cmd tea.Cmd
+		m.searchView, cmd = m.searchView.Update(msg)
+		return m, cmd
+
+	case statusTickMsg:
+		return m, m.tickStatus()
+
+	case threadsPollMsg:
+		// The list only refreshes while it is shown, but keeps its schedule
+		if m.state != StateThreads {
+			return m, m.threadsView.schedulePoll()
+		}
+		var cmd tea.Cmd
+		m.threadsView, cmd = m.threadsView.Update(msg)
+		return m, cmd
+
+	case notifyPollMsg, notificationsMsg, toastExpiredMsg, hookFailedMsg:
+		cmd, threadIDs := m.notifier.Update(msg)
+		m.threadsView.Highlight(threadIDs)
+		return m, cmd
+
+	case threadsLoadedMsg:
+		// Pages can finish loading after switching to another view
+		var cmd tea.Cmd
+		m.threadsView, cmd = m.threadsView.Update(msg)
+		return m, cmd
+
+	case openThreadMsg:
+		m.state = StateThreads
+		return m, m.threadsView.OpenThread(msg.threadID)
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		// Text inputs get every key, global bindings only apply outside of them
+		if m.state == StateThreads && m.threadsView.IsCapturingInput() {
+			var cmd tea.Cmd
+			m.threadsView, cmd = m.threadsView.Update(msg)
+			return m, cmd
+		}
+		if m.state == StateSearch && m.searchView.IsCapturingInput() {
+			var cmd tea.Cmd
+			m.searchView, cmd = m.searchView.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case m.keys.matches(msg, config.KeyQuit):
 			if m.state == StateThreads && m.threadsView.IsInDetailView() {
 				// If in detail view, go back to list
 				var cmd tea.Cmd
 				m.threadsView, cmd = m.threadsView.Update(msg)
 				return m, cmd
-			} else if m.state == StateDashboard {
-				// Go back to threads view from dashboard
+			} else if m.state == StateDashboard || m.state == StateCustomer || m.state == StateSearch {
+				// Go back to threads view from dashboard, customer profile or search
 				m.state = StateThreads
 				return m, nil
 			} else {
@@ -101,13 +199,13 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.quitting = true
 				return m, tea.Quit
 			}
-		case "d":
+		case m.keys.matches(msg, config.KeyDashboard):
 			// Switch to dashboard if not already there
 			if m.state != StateDashboard {
 				m.state = StateDashboard
 				return m, m.dashboardView.Init()
 			}
-		case "t":
+		case m.keys.matches(msg, config.KeyThreads):
 			// Switch to threads view if not already there
 			if m.state != StateThreads {
 				m.state = StateThreads
@@ -125,6 +223,14 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var cmd tea.Cmd
 			m.dashboardView, cmd = m.dashboardView.Update(msg)
 			return m, cmd
+		case StateCustomer:
+			var cmd tea.Cmd
+			m.customerView, cmd = m.customerView.Update(msg)
+			return m, cmd
+		case StateSearch:
+			var cmd tea.Cmd
+			m.searchView, cmd = m.searchView.Update(msg)
+			return m, cmd
 		}
 	}
 
@@ -138,6 +244,14 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.dashboardView, cmd = m.dashboardView.Update(msg)
 		return m, cmd
+	case StateCustomer:
+		var cmd tea.Cmd
+		m.customerView, cmd = m.customerView.Update(msg)
+		return m, cmd
+	case StateSearch:
+		var cmd tea.Cmd
+		m.searchView, cmd = m.searchView.Update(msg)
+		return m, cmd
 	}
 
 	return m, nil
@@ -156,6 +270,14 @@ func (m *MainModel) View() string {
 		content = m.threadsView.View()
 	case StateDashboard:
 		content = m.dashboardView.View()
+	case StateCustomer:
+		content = m.customerView.View()
+	case StateSearch:
+		content = m.searchView.View()
+	}
+
+	if toast := m.notifier.View(); toast != "" {
+		content = toast + "\n" + content
 	}
 
 	// Add some styling
@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"simple/client"
+	"simple/config"
+	"simple/store"
+)
+
+// chartHeight is the number of rows used by bar charts
+const chartHeight = 4
+
+// sparkBlocks are the levels of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// barBlocks are the partial fills of a bar chart cell, empty first
+var barBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// localStats opens the local thread store on first use and keeps it open
+type localStats struct {
+	once  sync.Once
+	cfg   config.DBConfig
+	stats *store.Stats
+	err   error
+}
+
+// get returns the local store, or nil when there is none
+func (l *localStats) get() (*store.Stats, error) {
+	l.once.Do(func() {
+		l.stats, l.err = store.OpenStats(l.cfg)
+	})
+	return l.stats, l.err
+}
+
+// chartWidget loads a per-day series for a chart widget, from the local store
+// when there is one and from Plain's thread counts otherwise
+func chartWidget(source client.DataSource, stats *localStats, kind string, days int) func(context.Context) (widgetResult, error) {
+	return func(ctx context.Context) (widgetResult, error) {
+		bounds := store.DayBounds(days, time.Now())
+
+		series, exact, from, err := localSeries(stats, kind, bounds)
+		if err != nil {
+			return widgetResult{}, err
+		}
+		if series == nil {
+			series, exact, err = apiSeries(ctx, source, kind, bounds)
+			if err != nil {
+				return widgetResult{}, err
+			}
+			from = "Plain"
+		}
+
+		last := series[len(series)-1]
+		value := fmt.Sprintf("%d today", last)
+		if kind == config.WidgetBacklogChart {
+			value = fmt.Sprintf("%d open", last)
+		}
+
+		peak := 0
+		for _, count := range series {
+			peak = max(peak, count)
+		}
+
+		return widgetResult{
+			value:  value,
+			series: series,
+			lines:  []string{fmt.Sprintf("%d days • max %d • %s", days, peak, from)},
+			exact:  exact,
+		}, nil
+	}
+}
+
+// localSeries reads a series from the local store, returning nil without a store
+// or when the store's history starts after the first day
+func localSeries(stats *localStats, kind string, bounds []time.Time) ([]int, bool, string, error) {
+	local, err := stats.get()
+	if err != nil || local == nil {
+		// A broken local store shouldn't hide the chart, Plain can answer instead
+		return nil, false, "", nil
+	}
+
+	from, syncedAt, err := local.Coverage(stats.cfg)
+	if err != nil || from.IsZero() || from.After(bounds[0]) {
+		// Days before the oldest stored update would show as zeros
+		return nil, false, "", nil
+	}
+
+	var series []int
+	switch kind {
+	case config.WidgetCreatedChart:
+		series, err = local.CreatedPerDay(bounds)
+	case config.WidgetDoneChart:
+		series, err = local.DonePerDay(bounds)
+	default:
+		series, err = local.BacklogPerDay(bounds)
+	}
+	if err != nil {
+		return nil, false, "", err
+	}
+	// Counts from the store are only as fresh as the last sync, so a store
+	// that wasn't synced today is missing today's threads
+	now := bounds[len(bounds)-1]
+	exact := !syncedAt.Before(bounds[len(bounds)-2])
+	return series, exact, "local store, " + happenedAgo("synced", syncedAt, now), nil
+}
+
+// apiSeries counts a series with one count query per day
+func apiSeries(ctx context.Context, source client.DataSource, kind string, bounds []time.Time) ([]int, bool, error) {
+	allExact := true
+	count := func(filter client.ThreadFilter) (int, error) {
+		count, exact, err := source.CountThreads(ctx, filter)
+		allExact = allExact && exact
+		return count, err
+	}
+
+	series := make([]int, 0, len(bounds)-1)
+	for i := 0; i+1 < len(bounds); i++ {
+		start, end := bounds[i].UTC().Format(time.RFC3339), bounds[i+1].UTC().Format(time.RFC3339)
+
+		var total int
+		var err error
+		switch kind {
+		case config.WidgetCreatedChart:
+			total, err = count(client.ThreadFilter{Statuses: []string{"TODO", "SNOOZED", "DONE"}, CreatedAfter: start, CreatedBefore: end})
+		case config.WidgetDoneChart:
+			total, err = count(client.ThreadFilter{Statuses: []string{"DONE"}, UpdatedAfter: start, UpdatedBefore: end})
+		default:
+			// Open now and created by then, plus done since then but created by then
+			var open, closedLater int
+			if open, err = count(client.ThreadFilter{Statuses: []string{"TODO", "SNOOZED"}, CreatedBefore: end}); err == nil {
+				closedLater, err = count(client.ThreadFilter{Statuses: []string{"DONE"}, CreatedBefore: end, UpdatedAfter: end})
+			}
+			total = open + closedLater
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		series = append(series, total)
+	}
+
+	return series, allExact, nil
+}
+
+// chartColumns spreads values over width columns, keeping the most recent values
+// when there are more values than columns
+func chartColumns(values []int, width int) []int {
+	if width <= 0 || len(values) == 0 {
+		return nil
+	}
+	if width < len(values) {
+		values = values[len(values)-width:]
+	}
+	columns := make([]int, width)
+	for i := range columns {
+		columns[i] = values[i*len(values)/width]
+	}
+	return columns
+}
+
+// sparkline renders values as a single line of blocks, scaled between their minimum and maximum
+func sparkline(values []int, width int) string {
+	columns := chartColumns(values, width)
+	if len(columns) == 0 {
+		return ""
+	}
+
+	low, high := columns[0], columns[0]
+	for _, value := range columns {
+		low, high = min(low, value), max(high, value)
+	}
+
+	var line strings.Builder
+	for _, value := range columns {
+		level := 0
+		if high > low {
+			level = (value - low) * (len(sparkBlocks) - 1) / (high - low)
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
+
+// barChart renders values as vertical bars height rows tall, one bar per value
+// with a gap between bars when there is room
+func barChart(values []int, width, height int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	if width < len(values) {
+		values = values[len(values)-width:]
+	}
+
+	barWidth := width / len(values)
+	gap := 0
+	if barWidth >= 3 {
+		gap = 1
+	}
+
+	peak := 0
+	for _, value := range values {
+		peak = max(peak, value)
+	}
+
+	steps := len(barBlocks) - 1
+	rows := make([]string, height)
+	for row := range rows {
+		var line strings.Builder
+		// Eighths of a cell below this row
+		base := (height - 1 - row) * steps
+		for _, value := range values {
+			fill := 0
+			if peak > 0 {
+				fill = (value*height*steps + peak - 1) / peak
+			}
+			fill = min(steps, max(0, fill-base))
+			line.WriteString(strings.Repeat(string(barBlocks[fill]), barWidth-gap))
+			line.WriteString(strings.Repeat(" ", gap))
+		}
+		rows[row] = line.String()
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"simple/client"
+	"simple/config"
+	"simple/types"
+)
+
+// recentDoneThreadsLimit is the number of done threads shown on a customer profile
+const recentDoneThreadsLimit = 10
+
+// CustomerView represents the customer profile view
+type CustomerView struct {
+	config     *config.Config
+	client     *client.PlainClient
+	customerID string
+	customer   *types.Customer
+	list       list.Model
+	keys       keyMap
+	openCount  int
+	doneCount  int
+	loading    bool
+	error      string
+	width      int
+	height     int
+}
+
+// openCustomerMsg is sent to switch to the customer profile view
+type openCustomerMsg struct {
+	customerID string
+}
+
+// closeCustomerMsg is sent to leave the customer profile view
+type closeCustomerMsg struct{}
+
+// openThreadMsg is sent to open a thread in the threads detail view
+type openThreadMsg struct {
+	threadID string
+}
+
+// customerLoadedMsg is sent when a customer and their threads are loaded
+type customerLoadedMsg struct {
+	customer    *types.Customer
+	openThreads []*types.Thread
+	doneThreads []*types.Thread
+	error       string
+}
+
+// NewCustomerView creates a new customer view
+func NewCustomerView(cfg *config.Config, client *client.PlainClient) *CustomerView {
+	l := list.New([]list.Item{}, threadDelegate{}, 0, 0)
+	l.Title = "Threads"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+
+	return &CustomerView{
+		config: cfg,
+		client: client,
+		list:   l,
+		keys:   newKeyMap(cfg),
+	}
+}
+
+// Load loads the profile and threads of the given customer
+func (cv *CustomerView) Load(customerID string) tea.Cmd {
+	cv.customerID = customerID
+	cv.customer = nil
+	cv.error = ""
+	cv.loading = true
+	cv.list.SetItems([]list.Item{})
+	return cv.loadCustomer(customerID)
+}
+
+// loadCustomer loads the customer and their open and recently done threads from the API
+func (cv *CustomerView) loadCustomer(customerID string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx := context.Background()
+
+		customer, err := cv.client.GetCustomerById(ctx, customerID)
+		if err != nil {
+			return customerLoadedMsg{error: err.Error()}
+		}
+		if customer == nil {
+			return customerLoadedMsg{error: fmt.Sprintf("Customer %s not found", customerID)}
+		}
+
+		open, err := cv.client.GetThreadsByCustomer(ctx, customerID, []string{"TODO", "SNOOZED"}, cv.config.UI.PageSize, "")
+		if err != nil {
+			return customerLoadedMsg{error: err.Error()}
+		}
+
+		done, err := cv.client.GetThreadsByCustomer(ctx, customerID, []string{"DONE"}, recentDoneThreadsLimit, "")
+		if err != nil {
+			return customerLoadedMsg{error: err.Error()}
+		}
+
+		return customerLoadedMsg{
+			customer:    customer,
+			openThreads: threadsFromConnection(open),
+			doneThreads: threadsFromConnection(done),
+		}
+	})
+}
+
+// threadsFromConnection flattens a thread connection into a slice of threads
+func threadsFromConnection(conn *types.ThreadConnection) []*types.Thread {
+	if conn == nil {
+		return nil
+	}
+
+	var threads []*types.Thread
+	for _, edge := range conn.Edges {
+		if edge.Node != nil {
+			threads = append(threads, edge.Node)
+		}
+	}
+	return threads
+}
+
+// Update handles messages and updates the model
+func (cv *CustomerView) Update(msg tea.Msg) (*CustomerView, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cv.width = msg.Width
+		cv.height = msg.Height
+		cv.resizeList()
+		return cv, nil
+
+	case customerLoadedMsg:
+		cv.loading = false
+		if msg.error != "" {
+			cv.error = msg.error
+			return cv, nil
+		}
+
+		cv.error = ""
+		cv.customer = msg.customer
+		cv.openCount = len(msg.openThreads)
+		cv.doneCount = len(msg.doneThreads)
+
+		items := make([]list.Item, 0, cv.openCount+cv.doneCount)
+		for _, thread := range msg.openThreads {
+			items = append(items, ThreadItem{Thread: thread})
+		}
+		for _, thread := range msg.doneThreads {
+			items = append(items, ThreadItem{Thread: thread})
+		}
+		cv.list.SetItems(items)
+		cv.list.Select(0)
+		cv.list.Title = fmt.Sprintf("Threads (%d open, %d recently done)", cv.openCount, cv.doneCount)
+		cv.resizeList()
+		return cv, nil
+
+	case tea.KeyMsg:
+		switch {
+		case cv.keys.matches(msg, config.KeyBack):
+			return cv, func() tea.Msg { return closeCustomerMsg{} }
+		case cv.keys.matches(msg, config.KeyRefresh):
+			if cv.customerID != "" {
+				return cv, cv.Load(cv.customerID)
+			}
+		case cv.keys.matches(msg, config.KeyOpen):
+			if item, ok := cv.list.SelectedItem().(ThreadItem); ok {
+				threadID := item.Thread.ID
+				return cv, func() tea.Msg { return openThreadMsg{threadID: threadID} }
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	cv.list, cmd = cv.list.Update(msg)
+	return cv, cmd
+}
+
+// resizeList fits the thread list below the profile header
+func (cv *CustomerView) resizeList() {
+	headerHeight := lipgloss.Height(cv.renderProfile())
+	helpHeight := 2
+	cv.list.SetWidth(cv.width)
+	cv.list.SetHeight(max(3, cv.height-headerHeight-helpHeight-4)) // Account for padding
+}
+
+// View renders the customer view
+func (cv *CustomerView) View() string {
+	if cv.loading {
+		style := lipgloss.NewStyle().
+			Foreground(theme.Loading).
+			Padding(2)
+		return style.Render("Loading customer...")
+	}
+
+	if cv.error != "" {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(theme.Error).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Error).
+			Padding(1, 2)
+
+		content := fmt.Sprintf("Error: %s\n\nPress 'r' to retry or 'q' to go back.", cv.error)
+		return errorStyle.Render(content)
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", cv.renderProfile(), cv.list.View(), cv.renderHelpText())
+}
+
+// renderProfile renders the customer profile header
+func (cv *CustomerView) renderProfile() string {
+	customer := cv.customer
+	if customer == nil {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Title)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Label)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(theme.Text)
+
+	var content strings.Builder
+
+	content.WriteString(titleStyle.Render(fmt.Sprintf("Customer: %s", customer.FullName)))
+	content.WriteString("\n\n")
+
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		content.WriteString(labelStyle.Render(label + ": "))
+		content.WriteString(valueStyle.Render(value))
+		content.WriteString("\n")
+	}
+
+	field("ID", customer.ID)
+	field("Email", customer.GetEmail())
+	if customer.Company != nil {
+		field("Company", customer.Company.Name)
+	}
+	field("Status", customer.Status)
+	if customer.CreatedAt != nil {
+		if t, err := customer.CreatedAt.Time(); err == nil {
+			field("Created", t.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	borderStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	content.WriteString("\n")
+	content.WriteString(borderStyle.Render(strings.Repeat("─", max(0, cv.width-2))))
+
+	return content.String()
+}
+
+// renderHelpText renders the help text for the customer view
+func (cv *CustomerView) renderHelpText() string {
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Muted)
+
+	helpItems := []string{
+		cv.keys.help(config.KeyOpen, "View thread"),
+		cv.keys.help(config.KeyRefresh, "Refresh"),
+		cv.keys.keys(config.KeyQuit) + "/" + cv.keys.help(config.KeyBack, "Back"),
+	}
+
+	return helpStyle.Render(strings.Join(helpItems, " • "))
+}
@@ -11,16 +11,21 @@ import (
 
 	"simple/client"
 	"simple/config"
-	"simple/types"
 )
 
 // DashboardView represents the dashboard view
 type DashboardView struct {
 	config     *config.Config
-	client     *client.PlainClient
+	client     client.DataSource
 	loading    bool
 	error      string
 	components []DashboardComponent
+	refresh    []time.Duration
+	layout     [][]int
+	columns    int
+	generation int
+	updatedAt  time.Time
+	keys       keyMap
 	width      int
 	height     int
 }
@@ -34,14 +39,42 @@ type DashboardComponent interface {
 	Update(msg tea.Msg) (DashboardComponent, tea.Cmd)
 }
 
+// countLoader is implemented by components that load their value from the API
+type countLoader interface {
+	loadCount() tea.Cmd
+}
+
+// estimatedComponent is implemented by components whose value may be an estimate
+type estimatedComponent interface {
+	Estimated() bool
+}
+
+// chartComponent is implemented by components that draw a chart of their values
+type chartComponent interface {
+	Chart(width int) string
+}
+
+// lineComponent is implemented by components that show rows below their value
+type lineComponent interface {
+	Lines() []string
+}
+
+// dashboardRefreshMsg reloads a component on its refresh interval. Ticks from an
+// earlier generation are dropped once the dashboard is reloaded.
+type dashboardRefreshMsg struct {
+	index      int
+	generation int
+}
+
 // ThreadCountComponent shows count of threads for a specific status
 type ThreadCountComponent struct {
 	title   string
 	status  string
 	count   int
+	exact   bool
 	loading bool
 	error   string
-	client  *client.PlainClient
+	client  client.DataSource
 	config  *config.Config
 }
 
@@ -49,9 +82,10 @@ type ThreadCountComponent struct {
 type ThreadsCreatedTodayComponent struct {
 	title   string
 	count   int
+	exact   bool
 	loading bool
 	error   string
-	client  *client.PlainClient
+	client  client.DataSource
 	config  *config.Config
 }
 
@@ -59,9 +93,10 @@ type ThreadsCreatedTodayComponent struct {
 type UnassignedThreadsComponent struct {
 	title   string
 	count   int
+	exact   bool
 	loading bool
 	error   string
-	client  *client.PlainClient
+	client  client.DataSource
 	config  *config.Config
 }
 
@@ -69,41 +104,133 @@ type UnassignedThreadsComponent struct {
 type threadCountMsg struct {
 	status string
 	count  int
+	exact  bool
 	error  string
 }
 
 // threadsCreatedTodayMsg represents the result of loading threads created today
 type threadsCreatedTodayMsg struct {
 	count int
+	exact bool
 	error string
 }
 
 // unassignedThreadsMsg represents the result of loading unassigned threads
 type unassignedThreadsMsg struct {
 	count int
+	exact bool
 	error string
 }
 
-// NewDashboardView creates a new dashboard view
-func NewDashboardView(cfg *config.Config, client *client.PlainClient) *DashboardView {
-	// Create dashboard components
-	components := []DashboardComponent{
-		NewThreadCountComponent("📋 TODO Threads", "TODO", cfg, client),
-		NewThreadCountComponent("😴 Snoozed Threads", "SNOOZED", cfg, client),
-		NewThreadsCreatedTodayComponent("📅 Created Today", cfg, client),
-		NewUnassignedThreadsComponent("👤 Unassigned", cfg, client),
+// NewDashboardView creates a new dashboard view from the ui.dashboard config,
+// or with the default widgets when none are configured
+func NewDashboardView(cfg *config.Config, plainClient client.DataSource) *DashboardView {
+	widgets := cfg.UI.Dashboard.Widgets
+	if len(widgets) == 0 {
+		widgets = defaultWidgets
+	}
+
+	labelCache := client.NewLabelCache(plainClient)
+	stats := &localStats{cfg: cfg.DB}
+	components := make([]DashboardComponent, 0, len(widgets))
+	refresh := make([]time.Duration, 0, len(widgets))
+	for i, widget := range widgets {
+		components = append(components, newDashboardComponent(i, widget, cfg, plainClient, labelCache, stats))
+		interval := widget.Refresh
+		if interval == 0 {
+			interval = cfg.UI.RefreshInterval
+		}
+		refresh = append(refresh, interval)
 	}
 
+	columns := cfg.UI.Dashboard.GridColumns()
+
 	return &DashboardView{
 		config:     cfg,
-		client:     client,
+		client:     plainClient,
 		loading:    false,
 		components: components,
+		refresh:    refresh,
+		layout:     dashboardLayout(widgets, columns),
+		columns:    columns,
+		keys:       newKeyMap(cfg),
+	}
+}
+
+// defaultWidgets is the dashboard shown when no widgets are configured
+var defaultWidgets = []config.WidgetConfig{
+	{Type: config.WidgetStatus, Title: "📋 TODO Threads", Status: "TODO"},
+	{Type: config.WidgetStatus, Title: "😴 Snoozed Threads", Status: "SNOOZED"},
+	{Type: config.WidgetCreatedToday, Title: "📅 Created Today"},
+	{Type: config.WidgetUnassigned, Title: "👤 Unassigned"},
+}
+
+// newDashboardComponent creates the component for a configured widget
+func newDashboardComponent(id int, widget config.WidgetConfig, cfg *config.Config, plainClient client.DataSource, labelCache *client.LabelCache, stats *localStats) DashboardComponent {
+	switch widget.Type {
+	case config.WidgetStatus:
+		status := strings.ToUpper(widget.Status)
+		title := widget.Title
+		if title == "" {
+			title = "📋 " + status + " Threads"
+		}
+		return NewThreadCountComponent(title, status, cfg, plainClient)
+	case config.WidgetCreatedToday:
+		title := widget.Title
+		if title == "" {
+			title = "📅 Created Today"
+		}
+		return NewThreadsCreatedTodayComponent(title, cfg, plainClient)
+	case config.WidgetUnassigned:
+		title := widget.Title
+		if title == "" {
+			title = "👤 Unassigned"
+		}
+		return NewUnassignedThreadsComponent(title, cfg, plainClient)
+	default:
+		return newWidget(id, widget, plainClient, labelCache, stats)
+	}
+}
+
+// dashboardLayout places the widgets in grid rows of component indexes, -1 marking
+// an empty cell. Widgets with a row and column go there, the others fill the
+// first free cells in order.
+func dashboardLayout(widgets []config.WidgetConfig, columns int) [][]int {
+	var grid [][]int
+	cell := func(row, column int) *int {
+		for len(grid) <= row {
+			empty := make([]int, columns)
+			for i := range empty {
+				empty[i] = -1
+			}
+			grid = append(grid, empty)
+		}
+		return &grid[row][column]
 	}
+
+	for i, widget := range widgets {
+		if widget.Row > 0 && widget.Column > 0 && widget.Column <= columns {
+			*cell(widget.Row-1, widget.Column-1) = i
+		}
+	}
+
+	next := 0
+	for i, widget := range widgets {
+		if widget.Row > 0 && widget.Column > 0 && widget.Column <= columns {
+			continue
+		}
+		for *cell(next/columns, next%columns) != -1 {
+			next++
+		}
+		*cell(next/columns, next%columns) = i
+		next++
+	}
+
+	return grid
 }
 
 // NewThreadCountComponent creates a new thread count component
-func NewThreadCountComponent(title, status string, cfg *config.Config, client *client.PlainClient) *ThreadCountComponent {
+func NewThreadCountComponent(title, status string, cfg *config.Config, client client.DataSource) *ThreadCountComponent {
 	return &ThreadCountComponent{
 		title:   title,
 		status:  status,
@@ -115,7 +242,7 @@ func NewThreadCountComponent(title, status string, cfg *config.Config, client *c
 }
 
 // NewThreadsCreatedTodayComponent creates a new threads created today component
-func NewThreadsCreatedTodayComponent(title string, cfg *config.Config, client *client.PlainClient) *ThreadsCreatedTodayComponent {
+func NewThreadsCreatedTodayComponent(title string, cfg *config.Config, client client.DataSource) *ThreadsCreatedTodayComponent {
 	return &ThreadsCreatedTodayComponent{
 		title:   title,
 		count:   0,
@@ -126,7 +253,7 @@ func NewThreadsCreatedTodayComponent(title string, cfg *config.Config, client *c
 }
 
 // NewUnassignedThreadsComponent creates a new unassigned threads component
-func NewUnassignedThreadsComponent(title string, cfg *config.Config, client *client.PlainClient) *UnassignedThreadsComponent {
+func NewUnassignedThreadsComponent(title string, cfg *config.Config, client client.DataSource) *UnassignedThreadsComponent {
 	return &UnassignedThreadsComponent{
 		title:   title,
 		count:   0,
@@ -138,32 +265,33 @@ func NewUnassignedThreadsComponent(title string, cfg *config.Config, client *cli
 
 // Init initializes the dashboard view
 func (dv *DashboardView) Init() tea.Cmd {
-	var cmds []tea.Cmd
+	dv.generation++
 
-	// Initialize all components
+	var cmds []tea.Cmd
 	for i, component := range dv.components {
-		if threadComp, ok := component.(*ThreadCountComponent); ok {
-			cmd := threadComp.loadCount()
-			if cmd != nil {
-				cmds = append(cmds, cmd)
-			}
-		} else if todayComp, ok := component.(*ThreadsCreatedTodayComponent); ok {
-			cmd := todayComp.loadCount()
-			if cmd != nil {
-				cmds = append(cmds, cmd)
-			}
-		} else if unassignedComp, ok := component.(*UnassignedThreadsComponent); ok {
-			cmd := unassignedComp.loadCount()
-			if cmd != nil {
+		if loader, ok := component.(countLoader); ok {
+			if cmd := loader.loadCount(); cmd != nil {
 				cmds = append(cmds, cmd)
 			}
 		}
-		dv.components[i] = component
+		cmds = append(cmds, dv.scheduleRefresh(i))
 	}
 
 	return tea.Batch(cmds...)
 }
 
+// scheduleRefresh reloads a component after its refresh interval, if it has one
+func (dv *DashboardView) scheduleRefresh(index int) tea.Cmd {
+	interval := dv.refresh[index]
+	if interval <= 0 {
+		return nil
+	}
+	generation := dv.generation
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return dashboardRefreshMsg{index: index, generation: generation}
+	})
+}
+
 // Update handles messages and updates the dashboard
 func (dv *DashboardView) Update(msg tea.Msg) (*DashboardView, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -172,55 +300,29 @@ func (dv *DashboardView) Update(msg tea.Msg) (*DashboardView, tea.Cmd) {
 		dv.height = msg.Height
 		return dv, nil
 
-	case threadCountMsg:
-		// Update the appropriate component
-		var cmds []tea.Cmd
-		for i, component := range dv.components {
-			if threadComp, ok := component.(*ThreadCountComponent); ok {
-				if threadComp.status == msg.status {
-					threadComp.count = msg.count
-					threadComp.loading = false
-					threadComp.error = msg.error
-					dv.components[i] = threadComp
-				}
-			}
-		}
-		return dv, tea.Batch(cmds...)
-
-	case threadsCreatedTodayMsg:
-		// Update threads created today component
-		var cmds []tea.Cmd
-		for i, component := range dv.components {
-			if todayComp, ok := component.(*ThreadsCreatedTodayComponent); ok {
-				todayComp.count = msg.count
-				todayComp.loading = false
-				todayComp.error = msg.error
-				dv.components[i] = todayComp
-			}
+	case dashboardRefreshMsg:
+		if msg.generation != dv.generation || msg.index >= len(dv.components) {
+			return dv, nil
 		}
-		return dv, tea.Batch(cmds...)
-
-	case unassignedThreadsMsg:
-		// Update unassigned threads component
 		var cmds []tea.Cmd
-		for i, component := range dv.components {
-			if unassignedComp, ok := component.(*UnassignedThreadsComponent); ok {
-				unassignedComp.count = msg.count
-				unassignedComp.loading = false
-				unassignedComp.error = msg.error
-				dv.components[i] = unassignedComp
-			}
+		if loader, ok := dv.components[msg.index].(countLoader); ok {
+			cmds = append(cmds, loader.loadCount())
 		}
+		cmds = append(cmds, dv.scheduleRefresh(msg.index))
 		return dv, tea.Batch(cmds...)
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r", "R":
+		switch {
+		case dv.keys.matches(msg, config.KeyRefresh):
 			// Refresh dashboard
 			return dv, dv.Init()
 		}
 	}
 
+	if componentLoaded(msg) {
+		dv.updatedAt = time.Now()
+	}
+
 	// Update all components
 	var cmds []tea.Cmd
 	for i, component := range dv.components {
@@ -234,6 +336,15 @@ func (dv *DashboardView) Update(msg tea.Msg) (*DashboardView, tea.Cmd) {
 	return dv, tea.Batch(cmds...)
 }
 
+// componentLoaded returns true for messages carrying a component's data
+func componentLoaded(msg tea.Msg) bool {
+	switch msg.(type) {
+	case widgetLoadedMsg, threadCountMsg, threadsCreatedTodayMsg, unassignedThreadsMsg:
+		return true
+	}
+	return false
+}
+
 // View renders the dashboard
 func (dv *DashboardView) View() string {
 	if dv.loading {
@@ -260,7 +371,7 @@ func (dv *DashboardView) renderLoading() string {
 // renderError renders the error state
 func (dv *DashboardView) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("9")).
+		Foreground(theme.Error).
 		Width(dv.width).
 		Height(dv.height).
 		AlignHorizontal(lipgloss.Center).
@@ -276,7 +387,7 @@ func (dv *DashboardView) renderDashboard() string {
 	// Dashboard header
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("12")).
+		Foreground(theme.Heading).
 		Padding(0, 1).
 		MarginBottom(1)
 
@@ -288,6 +399,20 @@ func (dv *DashboardView) renderDashboard() string {
 
 	// Footer with help
 	content.WriteString("\n\n")
+	var status []string
+	if offline := offlineStatus(dv.client, time.Now()); offline != "" {
+		status = append(status, "⚠ "+offline)
+	}
+	if dv.config.UI.RefreshInterval > 0 {
+		status = append(status, fmt.Sprintf("⟳ %s • every %s", updatedAgo(dv.updatedAt, time.Now()), dv.config.UI.RefreshInterval))
+	}
+	if len(status) > 0 {
+		statusStyle := lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Align(lipgloss.Center)
+		content.WriteString(statusStyle.Render(strings.Join(status, " • ")))
+		content.WriteString("\n")
+	}
 	content.WriteString(dv.renderHelpText())
 
 	return content.String()
@@ -301,30 +426,25 @@ func (dv *DashboardView) renderComponentGrid() string {
 
 	var rows []string
 
-	// Calculate component width (2 components per row)
-	componentWidth := (dv.width - 4) / 2
+	// Calculate component width from the configured number of columns
+	componentWidth := (dv.width - 2*dv.columns) / dv.columns
 	if componentWidth < 20 {
 		componentWidth = 20
 	}
 
-	// Group components into rows of 2
-	for i := 0; i < len(dv.components); i += 2 {
-		var row strings.Builder
-
-		// First component
-		comp1 := dv.components[i]
-		card1 := dv.renderComponentCard(comp1, componentWidth)
-		row.WriteString(card1)
-
-		// Second component (if exists)
-		if i+1 < len(dv.components) {
-			row.WriteString("  ") // Spacing between components
-			comp2 := dv.components[i+1]
-			card2 := dv.renderComponentCard(comp2, componentWidth)
-			row.WriteString(card2)
+	for _, indexes := range dv.layout {
+		var cards []string
+		for column, index := range indexes {
+			if column > 0 {
+				cards = append(cards, "  ") // Spacing between components
+			}
+			if index < 0 {
+				cards = append(cards, lipgloss.NewStyle().Width(componentWidth).Render(""))
+				continue
+			}
+			cards = append(cards, dv.renderComponentCard(dv.components[index], componentWidth))
 		}
-
-		rows = append(rows, row.String())
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cards...))
 	}
 
 	return strings.Join(rows, "\n\n")
@@ -333,23 +453,23 @@ func (dv *DashboardView) renderComponentGrid() string {
 // renderComponentCard renders a single component as a card
 func (dv *DashboardView) renderComponentCard(component DashboardComponent, width int) string {
 	// Different colors for different component types
-	var borderColor string
+	var borderColor lipgloss.TerminalColor
 	switch {
 	case strings.Contains(component.Title(), "TODO"):
-		borderColor = "3" // Yellow
+		borderColor = theme.CardTodo
 	case strings.Contains(component.Title(), "Snoozed"):
-		borderColor = "4" // Blue
+		borderColor = theme.CardSnoozed
 	case strings.Contains(component.Title(), "Created"):
-		borderColor = "2" // Green
+		borderColor = theme.CardCreated
 	case strings.Contains(component.Title(), "Unassigned"):
-		borderColor = "1" // Red
+		borderColor = theme.CardUnassigned
 	default:
-		borderColor = "6" // Cyan
+		borderColor = theme.CardOther
 	}
 
 	cardStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(borderColor)).
+		BorderForeground(borderColor).
 		Padding(1).
 		Width(width).
 		Height(7).
@@ -357,16 +477,16 @@ func (dv *DashboardView) renderComponentCard(component DashboardComponent, width
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("15")).
+		Foreground(theme.Text).
 		Align(lipgloss.Center)
 
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("9")).
+		Foreground(theme.Error).
 		Align(lipgloss.Center).
 		Width(width - 4)
 
 	loadingStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("11")).
+		Foreground(theme.Loading).
 		Align(lipgloss.Center).
 		Width(width - 4)
 
@@ -382,13 +502,43 @@ func (dv *DashboardView) renderComponentCard(component DashboardComponent, width
 		// Make the number larger and more prominent
 		bigValueStyle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("10")).
+			Foreground(theme.Value).
 			Align(lipgloss.Center).
 			Width(width - 4).
 			MarginTop(1)
 
 		bigNumber := fmt.Sprintf("   %s   ", component.Value())
 		content.WriteString(bigValueStyle.Render(bigNumber))
+
+		if estimated, ok := component.(estimatedComponent); ok && estimated.Estimated() {
+			estimatedStyle := lipgloss.NewStyle().
+				Foreground(theme.Muted).
+				Italic(true).
+				Align(lipgloss.Center).
+				Width(width - 4)
+			content.WriteString("\n")
+			content.WriteString(estimatedStyle.Render("estimated"))
+		}
+
+		if chart, ok := component.(chartComponent); ok {
+			if rendered := chart.Chart(width - 4); rendered != "" {
+				chartStyle := lipgloss.NewStyle().
+					Foreground(theme.Highlight).
+					Align(lipgloss.Center).
+					Width(width - 4)
+				content.WriteString("\n")
+				content.WriteString(chartStyle.Render(rendered))
+			}
+		}
+
+		if lines, ok := component.(lineComponent); ok && len(lines.Lines()) > 0 {
+			lineStyle := lipgloss.NewStyle().
+				Foreground(theme.Text).
+				Align(lipgloss.Center).
+				Width(width - 4)
+			content.WriteString("\n")
+			content.WriteString(lineStyle.Render(strings.Join(lines.Lines(), "\n")))
+		}
 	}
 
 	return cardStyle.Render(content.String())
@@ -397,11 +547,12 @@ func (dv *DashboardView) renderComponentCard(component DashboardComponent, width
 // renderHelpText renders help text
 func (dv *DashboardView) renderHelpText() string {
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
+		Foreground(theme.Muted).
 		Italic(true).
 		Align(lipgloss.Center)
 
-	return helpStyle.Render("🔄 Press 'r' to refresh • ⬅️  Press 'q' or 'Ctrl+C' to go back • 📋 Press 't' for threads")
+	return helpStyle.Render(fmt.Sprintf("🔄 Press '%s' to refresh • ⬅️  Press '%s' to go back • 📋 Press '%s' for threads",
+		dv.keys.keys(config.KeyRefresh), dv.keys.keys(config.KeyQuit), dv.keys.keys(config.KeyThreads)))
 }
 
 // ThreadCountComponent methods
@@ -413,7 +564,12 @@ func (tc *ThreadCountComponent) Title() string {
 
 // Value returns the component value
 func (tc *ThreadCountComponent) Value() string {
-	return fmt.Sprintf("%d", tc.count)
+	return countString(tc.count, !tc.exact)
+}
+
+// Estimated returns true if the count stopped short of the total
+func (tc *ThreadCountComponent) Estimated() bool {
+	return !tc.exact
 }
 
 // Loading returns the loading state
@@ -432,6 +588,7 @@ func (tc *ThreadCountComponent) Update(msg tea.Msg) (DashboardComponent, tea.Cmd
 	case threadCountMsg:
 		if msg.status == tc.status {
 			tc.count = msg.count
+			tc.exact = msg.exact
 			tc.loading = false
 			tc.error = msg.error
 		}
@@ -442,81 +599,20 @@ func (tc *ThreadCountComponent) Update(msg tea.Msg) (DashboardComponent, tea.Cmd
 // loadCount loads the thread count for this component
 func (tc *ThreadCountComponent) loadCount() tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		ctx := context.Background()
-
-		// Get a reasonable page size to get better count estimation
-		pageSize := 50
-		var threads *types.ThreadConnection
-		var err error
-
-		switch tc.status {
-		case "TODO":
-			threads, err = tc.client.GetThreadsByStatus(ctx, "TODO", pageSize, "")
-		case "SNOOZED":
-			threads, err = tc.client.GetThreadsByStatus(ctx, "SNOOZED", pageSize, "")
-		default:
-			threads, err = tc.client.GetAllThreads(ctx, pageSize, "")
+		filter := client.ThreadFilter{}
+		if tc.status != "" {
+			filter.Statuses = []string{tc.status}
 		}
 
+		count, exact, err := tc.client.CountThreads(context.Background(), filter)
 		if err != nil {
-			return threadCountMsg{
-				status: tc.status,
-				count:  0,
-				error:  err.Error(),
-			}
-		}
-
-		count := 0
-		if threads != nil && threads.Edges != nil {
-			count = len(threads.Edges)
-
-			// If there are more pages, fetch a few more to get a better estimate
-			if threads.PageInfo.HasNextPage {
-				// Fetch 2-3 more pages to get a better count estimate
-				maxPages := 3
-				currentPage := 1
-				cursor := threads.PageInfo.EndCursor
-
-				for currentPage < maxPages && cursor != "" {
-					var moreThreads *types.ThreadConnection
-					switch tc.status {
-					case "TODO":
-						moreThreads, err = tc.client.GetThreadsByStatus(ctx, "TODO", pageSize, cursor)
-					case "SNOOZED":
-						moreThreads, err = tc.client.GetThreadsByStatus(ctx, "SNOOZED", pageSize, cursor)
-					default:
-						moreThreads, err = tc.client.GetAllThreads(ctx, pageSize, cursor)
-					}
-
-					if err != nil {
-						break // Stop on error, use what we have
-					}
-
-					if moreThreads != nil && moreThreads.Edges != nil {
-						count += len(moreThreads.Edges)
-						cursor = moreThreads.PageInfo.EndCursor
-						if !moreThreads.PageInfo.HasNextPage {
-							break // No more pages
-						}
-					} else {
-						break
-					}
-					currentPage++
-				}
-
-				// If we still have more pages after sampling, add a "+" indicator
-				if cursor != "" && currentPage >= maxPages {
-					// We'll return the count with a flag to show it's an estimate
-					// For now, just return the count we have
-					fmt.Printf("+")
-				}
-			}
+			return threadCountMsg{status: tc.status, error: err.Error()}
 		}
 
 		return threadCountMsg{
 			status: tc.status,
 			count:  count,
-			error:  "",
+			exact:  exact,
 		}
 	})
 }
@@ -530,7 +626,12 @@ func (tc *ThreadsCreatedTodayComponent) Title() string {
 
 // Value returns the component value
 func (tc *ThreadsCreatedTodayComponent) Value() string {
-	return fmt.Sprintf("%d", tc.count)
+	return countString(tc.count, !tc.exact)
+}
+
+// Estimated returns true if the count stopped short of the total
+func (tc *ThreadsCreatedTodayComponent) Estimated() bool {
+	return !tc.exact
 }
 
 // Loading returns the loading state
@@ -548,6 +649,7 @@ func (tc *ThreadsCreatedTodayComponent) Update(msg tea.Msg) (DashboardComponent,
 	switch msg := msg.(type) {
 	case threadsCreatedTodayMsg:
 		tc.count = msg.count
+		tc.exact = msg.exact
 		tc.loading = false
 		tc.error = msg.error
 	}
@@ -557,38 +659,20 @@ func (tc *ThreadsCreatedTodayComponent) Update(msg tea.Msg) (DashboardComponent,
 // loadCount loads the count of threads created today
 func (tc *ThreadsCreatedTodayComponent) loadCount() tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		ctx := context.Background()
-
-		// Get threads and filter by creation date (today)
-		pageSize := 100
-		threads, err := tc.client.GetAllThreads(ctx, pageSize, "")
+		now := time.Now()
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
+		count, exact, err := tc.client.CountThreads(context.Background(), client.ThreadFilter{
+			Statuses:     []string{"TODO", "SNOOZED", "DONE"},
+			CreatedAfter: startOfDay.UTC().Format(time.RFC3339),
+		})
 		if err != nil {
-			return threadsCreatedTodayMsg{
-				count: 0,
-				error: err.Error(),
-			}
-		}
-
-		count := 0
-		if threads != nil && threads.Edges != nil {
-			// Get today's date for comparison
-			now := time.Now()
-			today := fmt.Sprintf("%d-%02d-%02d", now.Year(), int(now.Month()), now.Day())
-
-			for _, edge := range threads.Edges {
-				if edge.Node != nil && edge.Node.CreatedAt != nil {
-					createdDate := edge.Node.CreatedAt.ISO8601
-					if strings.HasPrefix(createdDate, today) {
-						count++
-					}
-				}
-			}
+			return threadsCreatedTodayMsg{error: err.Error()}
 		}
 
 		return threadsCreatedTodayMsg{
 			count: count,
-			error: "",
+			exact: exact,
 		}
 	})
 }
@@ -602,7 +686,12 @@ func (uc *UnassignedThreadsComponent) Title() string {
 
 // Value returns the component value
 func (uc *UnassignedThreadsComponent) Value() string {
-	return fmt.Sprintf("%d", uc.count)
+	return countString(uc.count, !uc.exact)
+}
+
+// Estimated returns true if the count stopped short of the total
+func (uc *UnassignedThreadsComponent) Estimated() bool {
+	return !uc.exact
 }
 
 // Loading returns the loading state
@@ -620,50 +709,25 @@ func (uc *UnassignedThreadsComponent) Update(msg tea.Msg) (DashboardComponent, t
 	switch msg := msg.(type) {
 	case unassignedThreadsMsg:
 		uc.count = msg.count
+		uc.exact = msg.exact
 		uc.loading = false
 		uc.error = msg.error
 	}
 	return uc, nil
 }
 
-// loadCount loads the count of unassigned threads
+// loadCount loads the count of open threads without an assignee. Plain can't
+// filter on the assignee, so open threads are checked on the client side.
 func (uc *UnassignedThreadsComponent) loadCount() tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		ctx := context.Background()
-
-		// Get active threads (TODO and SNOOZED) and count unassigned ones
-		pageSize := 100
-		threads, err := uc.client.GetThreads(ctx, pageSize, "")
-
+		count, exact, err := countThreads(context.Background(), uc.client, nil, openThreadsQuery+" assignee:none")
 		if err != nil {
-			return unassignedThreadsMsg{
-				count: 0,
-				error: err.Error(),
-			}
-		}
-
-		count := 0
-		if threads != nil && threads.Edges != nil {
-			// Count threads based on priority - higher priority threads are more likely unassigned
-			// This is a heuristic since we don't have assignee data in the current schema
-			for _, edge := range threads.Edges {
-				if edge.Node != nil {
-					// Priority 1 (high) threads are often unassigned initially
-					if edge.Node.Priority >= 3 {
-						count++
-					}
-				}
-			}
-
-			// If we don't have any high priority threads, provide a reasonable estimate
-			if count == 0 && len(threads.Edges) > 0 {
-				count = len(threads.Edges) / 4 // Estimate 25% are unassigned
-			}
+			return unassignedThreadsMsg{error: err.Error()}
 		}
 
 		return unassignedThreadsMsg{
 			count: count,
-			error: "",
+			exact: exact,
 		}
 	})
 }
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"simple/config"
+)
+
+func TestDashboardLayout(t *testing.T) {
+	widgets := []config.WidgetConfig{
+		{Type: config.WidgetQuery},
+		{Type: config.WidgetLabel, Row: 1, Column: 2},
+		{Type: config.WidgetOldestOpen},
+		{Type: config.WidgetTopCompanies, Row: 3, Column: 3},
+		{Type: config.WidgetAssigneeLoad},
+	}
+
+	got := dashboardLayout(widgets, 3)
+	want := [][]int{
+		{0, 1, 2},
+		{4, -1, -1},
+		{-1, -1, 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dashboardLayout() = %v, want %v", got, want)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		width  int
+		want   string
+	}{
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, 8, "▁▂▃▄▅▆▇█"},
+		{[]int{0, 7}, 4, "▁▁██"},
+		{[]int{3, 3, 3}, 3, "▁▁▁"},
+		// Only the most recent values fit
+		{[]int{7, 0, 0, 7}, 2, "▁█"},
+		{nil, 10, ""},
+	}
+
+	for _, tt := range tests {
+		if got := sparkline(tt.values, tt.width); got != tt.want {
+			t.Errorf("sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	got := barChart([]int{0, 1, 2, 4}, 12, 2)
+	want := "         ██ \n   ▄▄ ██ ██ "
+	if got != want {
+		t.Errorf("barChart() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"simple/config"
+)
+
+// keyMap holds the key bindings of every action, built from the config
+type keyMap struct {
+	bindings map[string]key.Binding
+}
+
+// newKeyMap builds the key map from the defaults and the ui.keys config section
+func newKeyMap(cfg *config.Config) keyMap {
+	bindings := make(map[string]key.Binding)
+	for action, keys := range cfg.UI.KeyBindings() {
+		bindings[action] = key.NewBinding(key.WithKeys(keys...))
+	}
+	return keyMap{bindings: bindings}
+}
+
+// matches returns true if msg is one of the keys bound to action
+func (k keyMap) matches(msg tea.KeyMsg, action string) bool {
+	return key.Matches(msg, k.bindings[action])
+}
+
+// binding returns the key binding of action
+func (k keyMap) binding(action string) key.Binding {
+	return k.bindings[action]
+}
+
+// keys returns the keys bound to action for display, e.g. "q/ctrl+c"
+func (k keyMap) keys(action string) string {
+	return strings.Join(k.bindings[action].Keys(), "/")
+}
+
+// help returns a help text item for action, e.g. "b: Open in browser"
+func (k keyMap) help(action, description string) string {
+	return k.keys(action) + ": " + description
+}
@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+
+	"simple/client"
+	"simple/config"
+	"simple/types"
+)
+
+// LabelPickerMode represents what the label picker selection is used for
+type LabelPickerMode int
+
+const (
+	// PickerFilter filters the thread list by the selected labels
+	PickerFilter LabelPickerMode = iota
+	// PickerApply sets the labels of a thread to the selected labels
+	PickerApply
+)
+
+// labelPickerMaxRows is the number of labels shown at once in the picker
+const labelPickerMaxRows = 10
+
+// LabelPicker is an overlay for fuzzy searching and multi-selecting workspace labels
+type LabelPicker struct {
+	labelCache *client.LabelCache
+	mode       LabelPickerMode
+	threadID   string
+	labels     []*types.LabelType
+	matches    fuzzy.Matches
+	selected   map[string]bool
+	cursor     int
+	input      textinput.Model
+	active     bool
+	loading    bool
+	error      string
+	width      int
+	keys       keyMap
+}
+
+// labelsLoadedMsg is sent when workspace labels are loaded for the picker
+type labelsLoadedMsg struct {
+	labels []*types.LabelType
+	error  string
+}
+
+// labelPickerDoneMsg is sent when a label selection is confirmed
+type labelPickerDoneMsg struct {
+	mode     LabelPickerMode
+	threadID string
+	labels   []*types.LabelType
+}
+
+// NewLabelPicker creates a new label picker
+func NewLabelPicker(labelCache *client.LabelCache, keys keyMap) *LabelPicker {
+	input := textinput.New()
+	input.Placeholder = "Search labels"
+	input.Prompt = "🔍 "
+
+	return &LabelPicker{
+		labelCache: labelCache,
+		input:      input,
+		selected:   make(map[string]bool),
+		keys:       keys,
+	}
+}
+
+// Open shows the picker with the given labels preselected
+func (lp *LabelPicker) Open(mode LabelPickerMode, threadID string, preselected []string) tea.Cmd {
+	lp.mode = mode
+	lp.threadID = threadID
+	lp.active = true
+	lp.cursor = 0
+	lp.error = ""
+	lp.selected = make(map[string]bool)
+	for _, id := range preselected {
+		lp.selected[id] = true
+	}
+	lp.input.SetValue("")
+	lp.input.Focus()
+
+	if lp.labels != nil {
+		lp.search()
+		return textinput.Blink
+	}
+
+	lp.loading = true
+	return tea.Batch(textinput.Blink, lp.loadLabels())
+}
+
+// Close hides the picker
+func (lp *LabelPicker) Close() {
+	lp.active = false
+	lp.input.Blur()
+}
+
+// Active returns true while the picker is shown
+func (lp *LabelPicker) Active() bool {
+	return lp.active
+}
+
+// loadLabels loads workspace labels through the label cache
+func (lp *LabelPicker) loadLabels() tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		labels, err := lp.labelCache.Labels(context.Background())
+		if err != nil {
+			return labelsLoadedMsg{error: err.Error()}
+		}
+		return labelsLoadedMsg{labels: labels}
+	})
+}
+
+// search refreshes the matching labels for the current search input
+func (lp *LabelPicker) search() {
+	query := strings.TrimSpace(lp.input.Value())
+	if query == "" {
+		lp.matches = make(fuzzy.Matches, len(lp.labels))
+		for i, label := range lp.labels {
+			lp.matches[i] = fuzzy.Match{Str: label.Name, Index: i}
+		}
+	} else {
+		names := make([]string, len(lp.labels))
+		for i, label := range lp.labels {
+			names[i] = label.Name
+		}
+		lp.matches = fuzzy.Find(query, names)
+	}
+
+	if lp.cursor >= len(lp.matches) {
+		lp.cursor = max(0, len(lp.matches)-1)
+	}
+}
+
+// Update handles messages for the label picker
+func (lp *LabelPicker) Update(msg tea.Msg) (*LabelPicker, tea.Cmd) {
+	switch msg := msg.(type) {
+	case labelsLoadedMsg:
+		lp.loading = false
+		if msg.error != "" {
+			lp.error = msg.error
+			return lp, nil
+		}
+		lp.labels = msg.labels
+		lp.search()
+		return lp, nil
+
+	case tea.KeyMsg:
+		switch {
+		case lp.keys.matches(msg, config.KeyCancel):
+			lp.Close()
+			return lp, nil
+		case lp.keys.matches(msg, config.KeySelectPrevious):
+			if lp.cursor > 0 {
+				lp.cursor--
+			}
+			return lp, nil
+		case lp.keys.matches(msg, config.KeySelectNext):
+			if lp.cursor < len(lp.matches)-1 {
+				lp.cursor++
+			}
+			return lp, nil
+		case lp.keys.matches(msg, config.KeyToggle):
+			if lp.cursor < len(lp.matches) {
+				id := lp.labels[lp.matches[lp.cursor].Index].ID
+				lp.selected[id] = !lp.selected[id]
+			}
+			return lp, nil
+		case lp.keys.matches(msg, config.KeySubmit):
+			lp.Close()
+			done := labelPickerDoneMsg{
+				mode:     lp.mode,
+				threadID: lp.threadID,
+				labels:   lp.Selected(),
+			}
+			return lp, func() tea.Msg { return done }
+		}
+	}
+
+	var cmd tea.Cmd
+	previous := lp.input.Value()
+	lp.input, cmd = lp.input.Update(msg)
+	if lp.input.Value() != previous {
+		lp.cursor = 0
+		lp.search()
+	}
+	return lp, cmd
+}
+
+// Selected returns the selected labels in workspace order
+func (lp *LabelPicker) Selected() []*types.LabelType {
+	var selected []*types.LabelType
+	for _, label := range lp.labels {
+		if lp.selected[label.ID] {
+			selected = append(selected, label)
+		}
+	}
+	return selected
+}
+
+// SetWidth sets the width available to the picker
+func (lp *LabelPicker) SetWidth(width int) {
+	lp.width = width
+}
+
+// View renders the label picker
+func (lp *LabelPicker) View() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Label).
+		Padding(0, 1).
+		Width(min(60, max(30, lp.width-4)))
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Title)
+
+	cursorStyle := theme.selectedStyle()
+
+	matchStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Highlight)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Muted)
+
+	var content strings.Builder
+
+	title := "Filter by labels"
+	if lp.mode == PickerApply {
+		title = "Set thread labels"
+	}
+	content.WriteString(titleStyle.Render(title))
+	content.WriteString("\n")
+	content.WriteString(lp.input.View())
+	content.WriteString("\n\n")
+
+	switch {
+	case lp.loading:
+		content.WriteString("Loading labels...")
+	case lp.error != "":
+		content.WriteString(lipgloss.NewStyle().Foreground(theme.Error).Render("Error: " + lp.error))
+	case len(lp.matches) == 0:
+		content.WriteString(helpStyle.Render("No matching labels"))
+	default:
+		// Keep the cursor inside the visible window
+		start := 0
+		if lp.cursor >= labelPickerMaxRows {
+			start = lp.cursor - labelPickerMaxRows + 1
+		}
+		end := min(len(lp.matches), start+labelPickerMaxRows)
+
+		for i := start; i < end; i++ {
+			match := lp.matches[i]
+			label := lp.labels[match.Index]
+
+			check := "[ ]"
+			if lp.selected[label.ID] {
+				check = "[x]"
+			}
+
+			// Highlight the characters matched by the fuzzy search
+			var name strings.Builder
+			matched := make(map[int]bool, len(match.MatchedIndexes))
+			for _, idx := range match.MatchedIndexes {
+				matched[idx] = true
+			}
+			for idx, r := range label.Name {
+				if matched[idx] {
+					name.WriteString(matchStyle.Render(string(r)))
+				} else {
+					name.WriteRune(r)
+				}
+			}
+
+			line := fmt.Sprintf("%s %s", check, name.String())
+			if i == lp.cursor {
+				line = cursorStyle.Render(fmt.Sprintf("%s %s", check, label.Name))
+			}
+			content.WriteString(line)
+			content.WriteString("\n")
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render(strings.Join([]string{
+		lp.keys.help(config.KeyToggle, "Toggle"),
+		lp.keys.help(config.KeySubmit, "Apply"),
+		lp.keys.help(config.KeyCancel, "Cancel"),
+	}, " • ")))
+
+	return boxStyle.Render(content.String())
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+)
+
+// quoteHeader matches the lines mail clients put above a quoted reply chain
+var quoteHeader = regexp.MustCompile(`(?i)^(on .+ wrote:|-+ ?original message ?-+|-+ ?forwarded message ?-+)$`)
+
+// fromHeader and mailHeader match the From: line and the header lines after it
+// that Outlook puts above a quoted reply chain
+var (
+	fromHeader = regexp.MustCompile(`(?i)^from: .+$`)
+	mailHeader = regexp.MustCompile(`(?i)^(sent|date|to|cc|subject): .*$`)
+)
+
+// signatureStart matches the start of an email signature or a client footer
+var signatureStart = regexp.MustCompile(`(?i)^(-- ?|sent from my .+|get outlook for .+)$`)
+
+// splitQuoted separates a message body from its trailing quoted reply chain and
+// signature. Everything from the first quote header, signature delimiter or
+// block of '>' quoted lines onwards counts as quoted.
+func splitQuoted(text string) (body string, quoted string) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if quoteHeader.MatchString(trimmed) || signatureStart.MatchString(trimmed) || isMailHeaders(lines[i:]) || isQuotedBlock(lines[i:]) {
+			// Never hide the whole message
+			if strings.TrimSpace(strings.Join(lines[:i], "\n")) == "" {
+				break
+			}
+			return strings.TrimRight(strings.Join(lines[:i], "\n"), "\n "), strings.Join(lines[i:], "\n")
+		}
+	}
+
+	return text, ""
+}
+
+// isMailHeaders returns true if the lines start with a From: line followed by
+// Sent:, To: or Subject: style headers, rather than a sentence starting with "From:"
+func isMailHeaders(lines []string) bool {
+	if len(lines) < 2 || !fromHeader.MatchString(strings.TrimSpace(lines[0])) {
+		return false
+	}
+	return mailHeader.MatchString(strings.TrimSpace(lines[1]))
+}
+
+// isQuotedBlock returns true if the remaining non-blank lines are all '>' quoted
+func isQuotedBlock(lines []string) bool {
+	if !strings.HasPrefix(strings.TrimSpace(lines[0]), ">") {
+		return false
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, ">") {
+			return false
+		}
+	}
+	return true
+}
+
+// markdownRenderer renders message bodies as Markdown, reusing the glamour
+// renderer until the wrap width or theme changes
+type markdownRenderer struct {
+	renderer *glamour.TermRenderer
+	width    int
+	style    string
+	cache    map[string]string
+}
+
+// render renders markdown wrapped to width, falling back to the raw text on errors
+func (mr *markdownRenderer) render(text string, width int) string {
+	width = max(20, width)
+	if mr.renderer == nil || mr.width != width || mr.style != theme.Markdown {
+		renderer, err := glamour.NewTermRenderer(
+			// The theme's style avoids querying the terminal background while the TUI runs
+			glamour.WithStandardStyle(theme.Markdown),
+			glamour.WithWordWrap(width),
+		)
+		if err != nil {
+			return text
+		}
+		mr.renderer = renderer
+		mr.width = width
+		mr.style = theme.Markdown
+		mr.cache = make(map[string]string)
+	}
+
+	if rendered, ok := mr.cache[text]; ok {
+		return rendered
+	}
+
+	rendered, err := mr.renderer.Render(text)
+	if err != nil {
+		return text
+	}
+	rendered = strings.Trim(rendered, "\n")
+	mr.cache[text] = rendered
+	return rendered
+}
@@ -0,0 +1,24 @@
+package ui
+
+import "testing"
+
+func TestSplitQuoted(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantBody string
+	}{
+		{"outlook headers", "Thanks!\n\nFrom: Support <help@example.com>\nSent: Monday\nTo: Ada\nSubject: Re: Export\n\nEarlier reply", "Thanks!"},
+		{"sentence starting with from", "Hi,\nFrom: the billing page I get an error.\nIt says 500.", "Hi,\nFrom: the billing page I get an error.\nIt says 500."},
+		{"reply header", "Sounds good\n\nOn Mon, Ada wrote:\n> Can we ship it?", "Sounds good"},
+		{"signature", "See attached\n-- \nAda", "See attached"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if body, _ := splitQuoted(tt.text); body != tt.wantBody {
+				t.Errorf("splitQuoted() body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"simple/config"
+)
+
+// NoteEditor is an overlay for writing an internal note on a thread
+type NoteEditor struct {
+	input      textarea.Model
+	threadID   string
+	customerID string
+	active     bool
+	width      int
+	height     int
+	keys       keyMap
+}
+
+// noteSubmitMsg is sent when a note is submitted from the editor
+type noteSubmitMsg struct {
+	threadID   string
+	customerID string
+	text       string
+}
+
+// NewNoteEditor creates a new note editor
+func NewNoteEditor(keys keyMap) *NoteEditor {
+	input := textarea.New()
+	input.Placeholder = "Write an internal note (Markdown). Only your team can see it."
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+
+	return &NoteEditor{input: input, keys: keys}
+}
+
+// Open shows the editor for a thread
+func (ne *NoteEditor) Open(threadID, customerID string) tea.Cmd {
+	ne.threadID = threadID
+	ne.customerID = customerID
+	ne.active = true
+	ne.input.Reset()
+	return ne.input.Focus()
+}
+
+// Close hides the editor
+func (ne *NoteEditor) Close() {
+	ne.active = false
+	ne.input.Blur()
+}
+
+// Active returns true while the editor is shown
+func (ne *NoteEditor) Active() bool {
+	return ne.active
+}
+
+// Update handles messages for the note editor
+func (ne *NoteEditor) Update(msg tea.Msg) (*NoteEditor, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case ne.keys.matches(msg, config.KeyCancel):
+			ne.Close()
+			return ne, nil
+		case ne.keys.matches(msg, config.KeySaveNote):
+			text := strings.TrimSpace(ne.input.Value())
+			if text == "" {
+				return ne, nil
+			}
+			ne.Close()
+			submit := noteSubmitMsg{threadID: ne.threadID, customerID: ne.customerID, text: text}
+			return ne, func() tea.Msg { return submit }
+		}
+	}
+
+	var cmd tea.Cmd
+	ne.input, cmd = ne.input.Update(msg)
+	return ne, cmd
+}
+
+// SetSize sets the space available to the editor
+func (ne *NoteEditor) SetSize(width, height int) {
+	ne.width = width
+	ne.height = height
+	ne.input.SetWidth(max(20, width-8))
+	ne.input.SetHeight(max(3, min(15, height-10)))
+}
+
+// View renders the note editor
+func (ne *NoteEditor) View() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Note).
+		Padding(0, 1)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Note)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Muted)
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render("🔒 New internal note"))
+	content.WriteString("\n\n")
+	content.WriteString(ne.input.View())
+	content.WriteString("\n\n")
+	content.WriteString(helpStyle.Render(ne.keys.help(config.KeySaveNote, "Save note") + " • " + ne.keys.help(config.KeyCancel, "Cancel")))
+
+	return boxStyle.Render(content.String())
+}
@@ -0,0 +1,312 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"simple/client"
+	"simple/config"
+	"simple/types"
+)
+
+// notifyPageSize is the page size used when fetching updated threads
+const notifyPageSize = 50
+
+// toastDuration is how long a notification stays on screen
+const toastDuration = 6 * time.Second
+
+// hookTimeout bounds how long a notification hook may run
+const hookTimeout = 30 * time.Second
+
+// Notification kinds, passed to hooks as SIMPLE_NOTIFICATION
+const (
+	notifyNewThread = "new_thread"
+	notifyReply     = "reply"
+)
+
+// notification is a new thread or reply found while polling
+type notification struct {
+	kind   string
+	thread *types.Thread
+	// key identifies the event, so it is only notified once
+	key string
+}
+
+// notifyPollMsg is sent when it is time to check for notifications
+type notifyPollMsg struct{}
+
+// notificationsMsg carries the result of a check
+type notificationsMsg struct {
+	notifications []notification
+	checkedAt     time.Time
+	me            string
+	error         string
+}
+
+// toastExpiredMsg hides a toast once it has been shown long enough
+type toastExpiredMsg struct {
+	id int
+}
+
+// hookFailedMsg is sent when a notification hook exits with an error
+type hookFailedMsg struct {
+	error string
+}
+
+// Notifier checks for new TODO threads and customer replies on threads assigned
+// to the current user, and shows them as toasts
+type Notifier struct {
+	config   config.NotificationConfig
+	interval time.Duration
+	client   *client.PlainClient
+	// me is the user ID or email replies are notified for, "" until resolved
+	me    string
+	since time.Time
+	seen  map[string]bool
+	toast string
+	// toastID drops expiry messages for toasts that were already replaced
+	toastID int
+}
+
+// NewNotifier creates a notifier for the ui.notifications config
+func NewNotifier(cfg *config.Config, plainClient *client.PlainClient) *Notifier {
+	return &Notifier{
+		config:   cfg.UI.Notifications,
+		interval: cfg.UI.RefreshInterval,
+		client:   plainClient,
+		me:       cfg.UI.Notifications.Me,
+		since:    time.Now(),
+		seen:     make(map[string]bool),
+	}
+}
+
+// Init schedules the first check, if notifications are enabled
+func (n *Notifier) Init() tea.Cmd {
+	return n.schedule()
+}
+
+// schedule schedules the next check
+func (n *Notifier) schedule() tea.Cmd {
+	if !n.config.Enabled {
+		return nil
+	}
+	return pollAfter(n.interval, notifyPollMsg{})
+}
+
+// check looks for threads updated since the last check
+func (n *Notifier) check() tea.Cmd {
+	since, me := n.since, n.me
+	return tea.Cmd(func() tea.Msg {
+		ctx := context.Background()
+		checkedAt := time.Now()
+
+		if me == "" {
+			// Without a user, only new threads can be notified
+			if user, err := n.client.GetMyUser(ctx); err == nil {
+				me = user.ID
+			}
+		}
+
+		// Every page is read, so since only moves past a complete window
+		var threads []*types.Thread
+		cursor := ""
+		for {
+			page, err := n.client.GetUpdatedThreads(ctx, since.UTC().Format(time.RFC3339), notifyPageSize, cursor)
+			if err != nil {
+				return notificationsMsg{me: me, error: err.Error()}
+			}
+			if page == nil {
+				break
+			}
+			for _, edge := range page.Edges {
+				if edge.Node != nil {
+					threads = append(threads, edge.Node)
+				}
+			}
+			if page.PageInfo == nil || !page.PageInfo.HasNextPage {
+				break
+			}
+			cursor = page.PageInfo.EndCursor
+		}
+
+		return notificationsMsg{
+			notifications: detectNotifications(threads, since, me),
+			checkedAt:     checkedAt,
+			me:            me,
+		}
+	})
+}
+
+// Update handles notification messages. It returns the IDs of threads with new
+// notifications so they can be highlighted.
+func (n *Notifier) Update(msg tea.Msg) (tea.Cmd, []string) {
+	switch msg := msg.(type) {
+	case notifyPollMsg:
+		return n.check(), nil
+
+	case notificationsMsg:
+		n.me = msg.me
+		if msg.error != "" {
+			// Try the same window again on the next tick
+			return n.schedule(), nil
+		}
+		n.since = msg.checkedAt
+
+		var fresh []notification
+		for _, notification := range msg.notifications {
+			if !n.seen[notification.key] {
+				n.seen[notification.key] = true
+				fresh = append(fresh, notification)
+			}
+		}
+		if len(fresh) == 0 {
+			return n.schedule(), nil
+		}
+
+		cmds := []tea.Cmd{n.schedule(), n.showToast(toastText(fresh))}
+		if n.config.Bell {
+			cmds = append(cmds, ringBell)
+		}
+		threadIDs := make([]string, 0, len(fresh))
+		for _, notification := range fresh {
+			threadIDs = append(threadIDs, notification.thread.ID)
+			if n.config.Hook != "" {
+				cmds = append(cmds, runHook(n.config.Hook, notification))
+			}
+		}
+		return tea.Batch(cmds...), threadIDs
+
+	case hookFailedMsg:
+		return n.showToast("⚠️  Notification hook failed: " + msg.error), nil
+
+	case toastExpiredMsg:
+		if msg.id == n.toastID {
+			n.toast = ""
+		}
+	}
+	return nil, nil
+}
+
+// showToast shows text until it expires or is replaced
+func (n *Notifier) showToast(text string) tea.Cmd {
+	n.toastID++
+	n.toast = text
+	id := n.toastID
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// View renders the current toast, or "" when there is none
+func (n *Notifier) View() string {
+	if n.toast == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(theme.Highlight).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Highlight).
+		Padding(0, 1).
+		Render(n.toast)
+}
+
+// detectNotifications finds new TODO threads and customer replies on threads
+// assigned to me among threads updated since the last check
+func detectNotifications(threads []*types.Thread, since time.Time, me string) []notification {
+	var notifications []notification
+	for _, thread := range threads {
+		if createdAt, ok := parseDateTime(thread.CreatedAt); ok && thread.Status == "TODO" && createdAt.After(since) {
+			notifications = append(notifications, notification{kind: notifyNewThread, thread: thread, key: notifyNewThread + ":" + thread.ID})
+			continue
+		}
+		if me == "" || !assignedTo(thread, me) || thread.LastInboundMessageInfo == nil {
+			continue
+		}
+		if repliedAt, ok := parseDateTime(thread.LastInboundMessageInfo.Timestamp); ok && repliedAt.After(since) {
+			key := notifyReply + ":" + thread.ID + ":" + thread.LastInboundMessageInfo.Timestamp.ISO8601
+			notifications = append(notifications, notification{kind: notifyReply, thread: thread, key: key})
+		}
+	}
+	return notifications
+}
+
+// assignedTo returns true if the thread is assigned to the user with the given ID or email
+func assignedTo(thread *types.Thread, me string) bool {
+	if thread.AssignedTo == nil {
+		return false
+	}
+	return thread.AssignedTo.ID == me || (thread.AssignedTo.Email != "" && strings.EqualFold(thread.AssignedTo.Email, me))
+}
+
+// parseDateTime parses an optional Plain timestamp
+func parseDateTime(dt *types.DateTime) (time.Time, bool) {
+	if dt == nil {
+		return time.Time{}, false
+	}
+	t, err := dt.Time()
+	return t, err == nil
+}
+
+// toastText describes notifications in a single line
+func toastText(notifications []notification) string {
+	first := notifications[0]
+	customer := "a customer"
+	if first.thread.Customer != nil && first.thread.Customer.FullName != "" {
+		customer = first.thread.Customer.FullName
+	}
+
+	var text string
+	switch first.kind {
+	case notifyReply:
+		text = fmt.Sprintf("💬 %s replied: %s", customer, truncateString(first.thread.Title, 50))
+	default:
+		text = fmt.Sprintf("🔔 New thread from %s: %s", customer, truncateString(first.thread.Title, 50))
+	}
+	if len(notifications) > 1 {
+		text += fmt.Sprintf(" (+%d more)", len(notifications)-1)
+	}
+	return text
+}
+
+// ringBell rings the terminal bell. It writes to stderr so it doesn't interfere
+// with the rendered view.
+func ringBell() tea.Msg {
+	fmt.Fprint(os.Stderr, "\a")
+	return nil
+}
+
+// runHook runs the notification hook with the thread JSON on stdin
+func runHook(hook string, notification notification) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		data, err := json.Marshal(notification.thread)
+		if err != nil {
+			return hookFailedMsg{error: err.Error()}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Env = append(os.Environ(),
+			"SIMPLE_NOTIFICATION="+notification.kind,
+			"SIMPLE_THREAD_ID="+notification.thread.ID,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			if message := strings.TrimSpace(string(output)); message != "" {
+				return hookFailedMsg{error: fmt.Sprintf("%v: %s", err, truncateString(message, 80))}
+			}
+			return hookFailedMsg{error: err.Error()}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"simple/types"
+)
+
+func TestDetectNotifications(t *testing.T) {
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	before := &types.DateTime{ISO8601: "2024-05-01T11:00:00Z"}
+	after := &types.DateTime{ISO8601: "2024-05-01T12:00:30Z"}
+	me := &types.User{ID: "u_me", Email: "me@example.com"}
+	other := &types.User{ID: "u_other"}
+
+	threads := []*types.Thread{
+		// New TODO thread
+		{ID: "t1", Status: "TODO", CreatedAt: after},
+		// New but already snoozed
+		{ID: "t2", Status: "SNOOZED", CreatedAt: after},
+		// Reply on a thread assigned to me
+		{ID: "t3", Status: "TODO", CreatedAt: before, AssignedTo: me, LastInboundMessageInfo: &types.MessageInfo{Timestamp: after}},
+		// Reply on someone else's thread
+		{ID: "t4", Status: "TODO", CreatedAt: before, AssignedTo: other, LastInboundMessageInfo: &types.MessageInfo{Timestamp: after}},
+		// Assigned to me, but the last reply was before the last check
+		{ID: "t5", Status: "TODO", CreatedAt: before, AssignedTo: me, LastInboundMessageInfo: &types.MessageInfo{Timestamp: before}},
+	}
+
+	tests := []struct {
+		name string
+		me   string
+		want []string
+	}{
+		{"by id", "u_me", []string{"new_thread:t1", "reply:t3:2024-05-01T12:00:30Z"}},
+		{"by email", "ME@example.com", []string{"new_thread:t1", "reply:t3:2024-05-01T12:00:30Z"}},
+		{"unknown user", "", []string{"new_thread:t1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectNotifications(threads, since, tt.me)
+			if len(got) != len(tt.want) {
+				t.Fatalf("detectNotifications() returned %d notifications, want %d", len(got), len(tt.want))
+			}
+			for i, notification := range got {
+				if notification.key != tt.want[i] {
+					t.Errorf("notification %d = %q, want %q", i, notification.key, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"simple/client"
+	"simple/store"
+)
+
+// statusTickInterval is how often "updated Ns ago" is redrawn while polling
+const statusTickInterval = time.Second
+
+// threadsPollMsg is sent when the thread list is due for a background refresh
+type threadsPollMsg struct{}
+
+// statusTickMsg redraws the time since the last refresh
+type statusTickMsg struct{}
+
+// pollAfter sends msg once interval has elapsed, or does nothing when polling is off
+func pollAfter(interval time.Duration, msg tea.Msg) tea.Cmd {
+	if interval <= 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return msg
+	})
+}
+
+// updatedAgo describes how long ago data was loaded, e.g. "updated 12s ago"
+func updatedAgo(at, now time.Time) string {
+	return happenedAgo("updated", at, now)
+}
+
+// happenedAgo describes how long ago something happened, e.g. "synced 3h ago"
+func happenedAgo(verb string, at, now time.Time) string {
+	if at.IsZero() {
+		return "not " + verb + " yet"
+	}
+	elapsed := now.Sub(at)
+	switch {
+	case elapsed < time.Minute:
+		return fmt.Sprintf("%s %ds ago", verb, max(0, int(elapsed.Seconds())))
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%s %dm ago", verb, int(elapsed.Minutes()))
+	default:
+		return fmt.Sprintf("%s %dh ago", verb, int(elapsed.Hours()))
+	}
+}
+
+// offlineStatus describes the local store in offline mode, e.g.
+// "offline (synced 3h ago)", or returns "" when reading from Plain
+func offlineStatus(source client.DataSource, now time.Time) string {
+	local, ok := source.(*store.Source)
+	if !ok {
+		return ""
+	}
+	return "offline (" + happenedAgo("synced", local.SyncedAt(), now) + ")"
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdatedAgo(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Time{}, "not updated yet"},
+		{now, "updated 0s ago"},
+		{now.Add(-42 * time.Second), "updated 42s ago"},
+		{now.Add(-90 * time.Second), "updated 1m ago"},
+		{now.Add(-3 * time.Hour), "updated 3h ago"},
+		// Clock skew shouldn't show negative ages
+		{now.Add(time.Second), "updated 0s ago"},
+	}
+
+	for _, tt := range tests {
+		if got := updatedAgo(tt.at, now); got != tt.want {
+			t.Errorf("updatedAgo(%v) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"simple/config"
+	"simple/query"
+)
+
+// QueryPrompt is an overlay for entering a thread filter query
+type QueryPrompt struct {
+	input  textinput.Model
+	active bool
+	error  *query.ParseError
+	width  int
+	keys   keyMap
+}
+
+// queryPromptDoneMsg is sent when a query is submitted. An empty input clears the query.
+type queryPromptDoneMsg struct {
+	input    string
+	compiled *query.Compiled
+}
+
+// NewQueryPrompt creates a new query prompt
+func NewQueryPrompt(keys keyMap) *QueryPrompt {
+	input := textinput.New()
+	input.Placeholder = `status:todo priority:<=1 label:bug company:"Acme" updated:>7d -assignee:none`
+	input.Prompt = "/ "
+
+	return &QueryPrompt{input: input, keys: keys}
+}
+
+// Open shows the prompt, starting from the current query
+func (qp *QueryPrompt) Open(current string) tea.Cmd {
+	qp.active = true
+	qp.error = nil
+	qp.input.SetValue(current)
+	qp.input.CursorEnd()
+	qp.input.Focus()
+	return textinput.Blink
+}
+
+// Close hides the prompt
+func (qp *QueryPrompt) Close() {
+	qp.active = false
+	qp.input.Blur()
+}
+
+// Active returns true while the prompt is shown
+func (qp *QueryPrompt) Active() bool {
+	return qp.active
+}
+
+// Update handles messages for the query prompt
+func (qp *QueryPrompt) Update(msg tea.Msg) (*QueryPrompt, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case qp.keys.matches(msg, config.KeyCancel):
+			qp.Close()
+			return qp, nil
+		case qp.keys.matches(msg, config.KeySubmit):
+			input := strings.TrimSpace(qp.input.Value())
+			if input == "" {
+				qp.Close()
+				return qp, func() tea.Msg { return queryPromptDoneMsg{} }
+			}
+
+			compiled, err := query.ParseAndCompile(input, time.Now())
+			if err != nil {
+				// Keep the prompt open so the query can be fixed
+				var parseErr *query.ParseError
+				if !errors.As(err, &parseErr) {
+					parseErr = &query.ParseError{Input: input, Msg: err.Error()}
+				}
+				qp.error = parseErr
+				return qp, nil
+			}
+
+			qp.Close()
+			done := queryPromptDoneMsg{input: input, compiled: compiled}
+			return qp, func() tea.Msg { return done }
+		}
+	}
+
+	var cmd tea.Cmd
+	previous := qp.input.Value()
+	qp.input, cmd = qp.input.Update(msg)
+	if qp.input.Value() != previous {
+		qp.error = nil
+	}
+	return qp, cmd
+}
+
+// SetWidth sets the width available to the prompt
+func (qp *QueryPrompt) SetWidth(width int) {
+	qp.width = width
+	qp.input.Width = max(20, width-12)
+}
+
+// View renders the query prompt
+func (qp *QueryPrompt) View() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Label).
+		Padding(0, 1).
+		Width(max(40, qp.width-4))
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Title)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme.Error)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Muted)
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render("Filter threads"))
+	content.WriteString("\n")
+	content.WriteString(qp.input.View())
+	content.WriteString("\n")
+
+	if qp.error != nil {
+		// Line the caret up with the input text, after the prompt
+		content.WriteString(errorStyle.Render(strings.Repeat(" ", len(qp.input.Prompt)+qp.error.Pos) + "^"))
+		content.WriteString("\n")
+		content.WriteString(errorStyle.Render(qp.error.Error()))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render("Fields: status priority label company customer assignee created updated"))
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render(qp.keys.help(config.KeySubmit, "Apply (empty clears)") + " • " + qp.keys.help(config.KeyCancel, "Cancel")))
+
+	return boxStyle.Render(content.String())
+}
@@ -0,0 +1,377 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"simple/client"
+	"simple/config"
+	"simple/store"
+	"simple/types"
+)
+
+// SearchView searches threads with Plain's search, or the synced threads and
+// messages in the local store
+type SearchView struct {
+	config *config.Config
+	client *client.PlainClient
+	stats  *localStats
+	input  textinput.Model
+	list   list.Model
+	keys   keyMap
+	terms  string
+	local  bool
+	next   string
+	total  int
+	// unranked is set when local results are LIKE matches, see store.Stats.Ranked
+	unranked bool
+	loading  bool
+	error    string
+	width    int
+	height   int
+}
+
+// openSearchMsg is sent to switch to the search view
+type openSearchMsg struct{}
+
+// closeSearchMsg is sent to leave the search view
+type closeSearchMsg struct{}
+
+// searchResultsMsg is sent when a page of search results is loaded. next is the
+// cursor of the next page, empty on the last one.
+type searchResultsMsg struct {
+	terms    string
+	local    bool
+	more     bool
+	results  []SearchItem
+	total    int
+	next     string
+	unranked bool
+	error    string
+}
+
+// SearchItem is a thread matching a search, with a snippet of the matching text
+type SearchItem struct {
+	Thread  *types.Thread
+	Snippet string
+}
+
+// FilterValue implements list.Item
+func (i SearchItem) FilterValue() string {
+	return i.Thread.Title
+}
+
+// NewSearchView creates a new search view. Without a client, only the local
+// store is searched.
+func NewSearchView(cfg *config.Config, plainClient *client.PlainClient, stats *localStats) *SearchView {
+	input := textinput.New()
+	input.Placeholder = "words in thread titles and messages"
+
+	l := list.New([]list.Item{}, searchDelegate{}, 0, 0)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+
+	sv := &SearchView{
+		config: cfg,
+		client: plainClient,
+		stats:  stats,
+		input:  input,
+		list:   l,
+		keys:   newKeyMap(cfg),
+		local:  plainClient == nil,
+	}
+	sv.updatePrompt()
+	return sv
+}
+
+// updatePrompt shows what is searched in the input prompt
+func (sv *SearchView) updatePrompt() {
+	sv.input.Prompt = "Search Plain: "
+	if sv.local {
+		sv.input.Prompt = "Search local store: "
+	}
+}
+
+// Open focuses the search input, keeping the last results
+func (sv *SearchView) Open() tea.Cmd {
+	sv.input.CursorEnd()
+	sv.input.Focus()
+	return textinput.Blink
+}
+
+// IsCapturingInput returns true while the search input has focus
+func (sv *SearchView) IsCapturingInput() bool {
+	return sv.input.Focused()
+}
+
+// search loads the page of results after cursor, from the local store or Plain
+func (sv *SearchView) search(terms, cursor string) tea.Cmd {
+	limit := sv.config.UI.PageSize
+	if sv.local {
+		return func() tea.Msg {
+			msg := searchResultsMsg{terms: terms, local: true, more: cursor != ""}
+			stats, err := sv.stats.get()
+			if err != nil {
+				msg.error = err.Error()
+				return msg
+			}
+			if stats == nil {
+				msg.error = "No local store found. Sync threads and their messages with `simple report --timelines` first."
+				return msg
+			}
+
+			offset, _ := strconv.Atoi(cursor)
+			results, total, err := stats.Search(store.SearchQuery{Terms: terms, Limit: limit, Offset: offset})
+			if err != nil {
+				msg.error = err.Error()
+				return msg
+			}
+			for i := range results {
+				msg.results = append(msg.results, SearchItem{Thread: results[i].Thread(), Snippet: results[i].Snippet})
+			}
+			msg.total = total
+			msg.unranked = !stats.Ranked()
+			if next := offset + len(results); next < total {
+				msg.next = strconv.Itoa(next)
+			}
+			return msg
+		}
+	}
+
+	return func() tea.Msg {
+		msg := searchResultsMsg{terms: terms, more: cursor != ""}
+		results, err := sv.client.SearchThreads(context.Background(), terms, limit, cursor)
+		if err != nil {
+			msg.error = err.Error()
+			return msg
+		}
+		for _, edge := range results.Edges {
+			if edge != nil && edge.Node != nil && edge.Node.Thread != nil {
+				msg.results = append(msg.results, SearchItem{Thread: edge.Node.Thread, Snippet: edge.Node.Thread.SearchSnippet(terms)})
+			}
+		}
+		if results.PageInfo != nil && results.PageInfo.HasNextPage {
+			msg.next = results.PageInfo.EndCursor
+		}
+		return msg
+	}
+}
+
+// Update handles messages and updates the model
+func (sv *SearchView) Update(msg tea.Msg) (*SearchView, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		sv.width = msg.Width
+		sv.height = msg.Height
+		sv.input.Width = max(20, msg.Width-16)
+		sv.list.SetSize(msg.Width, max(3, msg.Height-8)) // Account for input, status, help and padding
+		return sv, nil
+
+	case searchResultsMsg:
+		// Only the latest search is shown
+		if msg.terms != sv.terms || msg.local != sv.local {
+			return sv, nil
+		}
+		sv.loading = false
+		sv.error = msg.error
+		if msg.error != "" {
+			return sv, nil
+		}
+		sv.total = msg.total
+		sv.next = msg.next
+		sv.unranked = msg.unranked
+
+		var items []list.Item
+		if msg.more {
+			items = sv.list.Items()
+		}
+		for _, result := range msg.results {
+			items = append(items, result)
+		}
+		sv.list.SetItems(items)
+		if !msg.more {
+			sv.list.Select(0)
+		}
+		return sv, nil
+
+	case tea.KeyMsg:
+		if sv.input.Focused() {
+			switch {
+			case sv.keys.matches(msg, config.KeySubmit):
+				terms := strings.TrimSpace(sv.input.Value())
+				if terms == "" {
+					return sv, nil
+				}
+				sv.input.Blur()
+				sv.terms = terms
+				sv.loading = true
+				return sv, sv.search(terms, "")
+			case sv.keys.matches(msg, config.KeyToggle):
+				// Offline, there is no Plain search to switch to
+				if sv.client != nil {
+					sv.local = !sv.local
+					sv.updatePrompt()
+				}
+				return sv, nil
+			case sv.keys.matches(msg, config.KeyCancel):
+				sv.input.Blur()
+				if sv.terms == "" {
+					return sv, func() tea.Msg { return closeSearchMsg{} }
+				}
+				return sv, nil
+			}
+			var cmd tea.Cmd
+			sv.input, cmd = sv.input.Update(msg)
+			return sv, cmd
+		}
+
+		switch {
+		case sv.keys.matches(msg, config.KeyBack):
+			return sv, func() tea.Msg { return closeSearchMsg{} }
+		case sv.keys.matches(msg, config.KeySearch):
+			return sv, sv.Open()
+		case sv.keys.matches(msg, config.KeyOpen):
+			if item, ok := sv.list.SelectedItem().(SearchItem); ok {
+				threadID := item.Thread.ID
+				return sv, func() tea.Msg { return openThreadMsg{threadID: threadID} }
+			}
+		case sv.keys.matches(msg, config.KeyNextPage):
+			if sv.next != "" && !sv.loading {
+				sv.loading = true
+				return sv, sv.search(sv.terms, sv.next)
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	sv.list, cmd = sv.list.Update(msg)
+	return sv, cmd
+}
+
+// View renders the search view
+func (sv *SearchView) View() string {
+	statusStyle := lipgloss.NewStyle().
+		Foreground(theme.Muted)
+
+	var status string
+	switch {
+	case sv.loading:
+		status = lipgloss.NewStyle().Foreground(theme.Loading).Render("Searching...")
+	case sv.error != "":
+		status = lipgloss.NewStyle().Foreground(theme.Error).Render("Error: " + sv.error)
+	case sv.terms == "":
+		status = statusStyle.Render("Type words to search for and press enter")
+	case len(sv.list.Items()) == 0:
+		status = statusStyle.Render(fmt.Sprintf("No threads found for %q", sv.terms))
+	case sv.total > len(sv.list.Items()):
+		status = statusStyle.Render(fmt.Sprintf("Best %d of %d threads found for %q", len(sv.list.Items()), sv.total, sv.terms))
+	case sv.next != "":
+		// Plain's search doesn't count its results
+		status = statusStyle.Render(fmt.Sprintf("Best %d threads found for %q", len(sv.list.Items()), sv.terms))
+	default:
+		status = statusStyle.Render(fmt.Sprintf("%d threads found for %q", len(sv.list.Items()), sv.terms))
+	}
+	if sv.unranked && !sv.loading && sv.error == "" && len(sv.list.Items()) > 0 {
+		status += statusStyle.Render(" · unranked, this build has no SQLite FTS5")
+	}
+
+	return fmt.Sprintf("%s\n%s\n\n%s\n%s", sv.input.View(), status, sv.list.View(), sv.renderHelpText())
+}
+
+// renderHelpText renders the help text for the search view
+func (sv *SearchView) renderHelpText() string {
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Muted)
+
+	helpItems := []string{sv.keys.help(config.KeySubmit, "Search"), sv.keys.help(config.KeyCancel, "Results")}
+	if sv.client != nil {
+		helpItems = append(helpItems, sv.keys.help(config.KeyToggle, "Search Plain or the local store"))
+	}
+	if !sv.input.Focused() {
+		helpItems = []string{
+			sv.keys.help(config.KeyOpen, "View thread"),
+			sv.keys.help(config.KeySearch, "New search"),
+		}
+		if sv.next != "" {
+			helpItems = append(helpItems, sv.keys.help(config.KeyNextPage, "More results"))
+		}
+		helpItems = append(helpItems, sv.keys.keys(config.KeyQuit)+"/"+sv.keys.help(config.KeyBack, "Back"))
+	}
+
+	return helpStyle.Render(strings.Join(helpItems, " • "))
+}
+
+// searchDelegate implements list.ItemDelegate for SearchItem
+type searchDelegate struct{}
+
+func (d searchDelegate) Height() int                             { return 2 }
+func (d searchDelegate) Spacing() int                            { return 1 }
+func (d searchDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d searchDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(SearchItem)
+	if !ok {
+		return
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Text).
+		Bold(true)
+
+	statusStyle := lipgloss.NewStyle().
+		Foreground(theme.statusColor(i.Thread.Status)).
+		Bold(true)
+
+	snippetStyle := lipgloss.NewStyle().
+		Foreground(theme.Muted)
+
+	matchStyle := lipgloss.NewStyle().
+		Foreground(theme.Highlight).
+		Bold(true)
+
+	var str strings.Builder
+	str.WriteString(titleStyle.Render(fmt.Sprintf("%-50s", truncateString(i.Thread.Title, 50))))
+	str.WriteString(" ")
+	str.WriteString(statusStyle.Render(i.Thread.Status))
+	str.WriteString("\n")
+
+	str.WriteString(renderSnippet(i.Snippet, max(20, m.Width()-6), snippetStyle, matchStyle))
+
+	style := lipgloss.NewStyle().Padding(0, 1)
+	if index == m.Index() {
+		style = theme.selectedStyle().Padding(0, 1)
+	}
+	fmt.Fprint(w, style.Render(str.String()))
+}
+
+// renderSnippet renders a snippet on one line cut to width, with its matches in matchStyle
+func renderSnippet(snippet string, width int, textStyle, matchStyle lipgloss.Style) string {
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	// Markers alternate, so every other part is a match
+	parts := strings.Split(strings.ReplaceAll(snippet, types.HighlightEnd, types.HighlightStart), types.HighlightStart)
+
+	var b strings.Builder
+	for i, part := range parts {
+		style := textStyle
+		if i%2 == 1 {
+			style = matchStyle
+		}
+		runes := []rune(part)
+		if len(runes) >= width {
+			b.WriteString(style.Render(string(runes[:max(0, width-1)]) + "…"))
+			break
+		}
+		b.WriteString(style.Render(part))
+		width -= len(runes)
+	}
+	return b.String()
+}
@@ -0,0 +1,448 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+
+	"simple/config"
+)
+
+// Theme holds the colors used across the terminal UI
+type Theme struct {
+	Name string
+
+	Title      lipgloss.TerminalColor // view and overlay titles
+	Heading    lipgloss.TerminalColor // dashboard header
+	Label      lipgloss.TerminalColor // field labels, overlay borders, entry types
+	Text       lipgloss.TerminalColor // regular text
+	Muted      lipgloss.TerminalColor // help text, timestamps, secondary info
+	Highlight  lipgloss.TerminalColor // senders, fuzzy matches, scroll position
+	Value      lipgloss.TerminalColor // dashboard numbers
+	Loading    lipgloss.TerminalColor
+	Error      lipgloss.TerminalColor
+	Note       lipgloss.TerminalColor // internal notes
+	Attachment lipgloss.TerminalColor
+	SelectedFg lipgloss.TerminalColor
+	SelectedBg lipgloss.TerminalColor
+
+	StatusTodo    lipgloss.TerminalColor
+	StatusSnoozed lipgloss.TerminalColor
+	StatusOpen    lipgloss.TerminalColor
+	StatusPending lipgloss.TerminalColor
+	StatusDone    lipgloss.TerminalColor
+	StatusOther   lipgloss.TerminalColor
+
+	PriorityUrgent lipgloss.TerminalColor
+	PriorityHigh   lipgloss.TerminalColor
+	PriorityMedium lipgloss.TerminalColor
+	PriorityLow    lipgloss.TerminalColor
+	PriorityNone   lipgloss.TerminalColor
+
+	// Dashboard card borders
+	CardTodo       lipgloss.TerminalColor
+	CardSnoozed    lipgloss.TerminalColor
+	CardCreated    lipgloss.TerminalColor
+	CardUnassigned lipgloss.TerminalColor
+	CardOther      lipgloss.TerminalColor
+
+	// Markdown is the glamour style used for message bodies
+	Markdown string
+	// ReverseSelection marks the selected item with reverse video instead of colors
+	ReverseSelection bool
+}
+
+// theme is the theme in use, set from the config when the app starts
+var theme = defaultTheme()
+
+// defaultTheme is the 256 color theme the UI has always used
+func defaultTheme() *Theme {
+	return &Theme{
+		Name:       "default",
+		Title:      lipgloss.Color("205"),
+		Heading:    lipgloss.Color("12"),
+		Label:      lipgloss.Color("39"),
+		Text:       lipgloss.Color("252"),
+		Muted:      lipgloss.Color("241"),
+		Highlight:  lipgloss.Color("118"),
+		Value:      lipgloss.Color("10"),
+		Loading:    lipgloss.Color("69"),
+		Error:      lipgloss.Color("196"),
+		Note:       lipgloss.Color("214"),
+		Attachment: lipgloss.Color("180"),
+		SelectedFg: lipgloss.Color("230"),
+		SelectedBg: lipgloss.Color("57"),
+
+		StatusTodo:    lipgloss.Color("3"),
+		StatusSnoozed: lipgloss.Color("4"),
+		StatusOpen:    lipgloss.Color("2"),
+		StatusPending: lipgloss.Color("3"),
+		StatusDone:    lipgloss.Color("8"),
+		StatusOther:   lipgloss.Color("15"),
+
+		PriorityUrgent: lipgloss.Color("9"),
+		PriorityHigh:   lipgloss.Color("1"),
+		PriorityMedium: lipgloss.Color("3"),
+		PriorityLow:    lipgloss.Color("2"),
+		PriorityNone:   lipgloss.Color("15"),
+
+		CardTodo:       lipgloss.Color("3"),
+		CardSnoozed:    lipgloss.Color("4"),
+		CardCreated:    lipgloss.Color("2"),
+		CardUnassigned: lipgloss.Color("1"),
+		CardOther:      lipgloss.Color("6"),
+
+		Markdown: styles.DarkStyle,
+	}
+}
+
+// darkTheme is a true color theme for dark terminals
+func darkTheme() *Theme {
+	return &Theme{
+		Name:       "dark",
+		Title:      lipgloss.Color("#FF79C6"),
+		Heading:    lipgloss.Color("#8BE9FD"),
+		Label:      lipgloss.Color("#8BE9FD"),
+		Text:       lipgloss.Color("#F8F8F2"),
+		Muted:      lipgloss.Color("#6272A4"),
+		Highlight:  lipgloss.Color("#50FA7B"),
+		Value:      lipgloss.Color("#50FA7B"),
+		Loading:    lipgloss.Color("#BD93F9"),
+		Error:      lipgloss.Color("#FF5555"),
+		Note:       lipgloss.Color("#FFB86C"),
+		Attachment: lipgloss.Color("#F1FA8C"),
+		SelectedFg: lipgloss.Color("#F8F8F2"),
+		SelectedBg: lipgloss.Color("#44475A"),
+
+		StatusTodo:    lipgloss.Color("#F1FA8C"),
+		StatusSnoozed: lipgloss.Color("#BD93F9"),
+		StatusOpen:    lipgloss.Color("#50FA7B"),
+		StatusPending: lipgloss.Color("#F1FA8C"),
+		StatusDone:    lipgloss.Color("#6272A4"),
+		StatusOther:   lipgloss.Color("#F8F8F2"),
+
+		PriorityUrgent: lipgloss.Color("#FF5555"),
+		PriorityHigh:   lipgloss.Color("#FFB86C"),
+		PriorityMedium: lipgloss.Color("#F1FA8C"),
+		PriorityLow:    lipgloss.Color("#50FA7B"),
+		PriorityNone:   lipgloss.Color("#F8F8F2"),
+
+		CardTodo:       lipgloss.Color("#F1FA8C"),
+		CardSnoozed:    lipgloss.Color("#BD93F9"),
+		CardCreated:    lipgloss.Color("#50FA7B"),
+		CardUnassigned: lipgloss.Color("#FF5555"),
+		CardOther:      lipgloss.Color("#8BE9FD"),
+
+		Markdown: styles.DarkStyle,
+	}
+}
+
+// lightTheme is a theme for light terminal backgrounds
+func lightTheme() *Theme {
+	return &Theme{
+		Name:       "light",
+		Title:      lipgloss.Color("125"),
+		Heading:    lipgloss.Color("25"),
+		Label:      lipgloss.Color("25"),
+		Text:       lipgloss.Color("235"),
+		Muted:      lipgloss.Color("244"),
+		Highlight:  lipgloss.Color("28"),
+		Value:      lipgloss.Color("28"),
+		Loading:    lipgloss.Color("61"),
+		Error:      lipgloss.Color("160"),
+		Note:       lipgloss.Color("166"),
+		Attachment: lipgloss.Color("94"),
+		SelectedFg: lipgloss.Color("235"),
+		SelectedBg: lipgloss.Color("153"),
+
+		StatusTodo:    lipgloss.Color("136"),
+		StatusSnoozed: lipgloss.Color("25"),
+		StatusOpen:    lipgloss.Color("28"),
+		StatusPending: lipgloss.Color("136"),
+		StatusDone:    lipgloss.Color("244"),
+		StatusOther:   lipgloss.Color("235"),
+
+		PriorityUrgent: lipgloss.Color("160"),
+		PriorityHigh:   lipgloss.Color("166"),
+		PriorityMedium: lipgloss.Color("136"),
+		PriorityLow:    lipgloss.Color("28"),
+		PriorityNone:   lipgloss.Color("235"),
+
+		CardTodo:       lipgloss.Color("136"),
+		CardSnoozed:    lipgloss.Color("25"),
+		CardCreated:    lipgloss.Color("28"),
+		CardUnassigned: lipgloss.Color("160"),
+		CardOther:      lipgloss.Color("30"),
+
+		Markdown: styles.LightStyle,
+	}
+}
+
+// highContrastTheme uses only bright basic colors on the terminal background
+func highContrastTheme() *Theme {
+	return &Theme{
+		Name:       "high-contrast",
+		Title:      lipgloss.Color("15"),
+		Heading:    lipgloss.Color("15"),
+		Label:      lipgloss.Color("14"),
+		Text:       lipgloss.Color("15"),
+		Muted:      lipgloss.Color("7"),
+		Highlight:  lipgloss.Color("10"),
+		Value:      lipgloss.Color("15"),
+		Loading:    lipgloss.Color("14"),
+		Error:      lipgloss.Color("9"),
+		Note:       lipgloss.Color("11"),
+		Attachment: lipgloss.Color("11"),
+		SelectedFg: lipgloss.Color("0"),
+		SelectedBg: lipgloss.Color("11"),
+
+		StatusTodo:    lipgloss.Color("11"),
+		StatusSnoozed: lipgloss.Color("14"),
+		StatusOpen:    lipgloss.Color("10"),
+		StatusPending: lipgloss.Color("11"),
+		StatusDone:    lipgloss.Color("7"),
+		StatusOther:   lipgloss.Color("15"),
+
+		PriorityUrgent: lipgloss.Color("9"),
+		PriorityHigh:   lipgloss.Color("13"),
+		PriorityMedium: lipgloss.Color("11"),
+		PriorityLow:    lipgloss.Color("10"),
+		PriorityNone:   lipgloss.Color("15"),
+
+		CardTodo:       lipgloss.Color("11"),
+		CardSnoozed:    lipgloss.Color("14"),
+		CardCreated:    lipgloss.Color("10"),
+		CardUnassigned: lipgloss.Color("9"),
+		CardOther:      lipgloss.Color("15"),
+
+		Markdown: styles.DarkStyle,
+	}
+}
+
+// noColorTheme leaves every color to the terminal
+func noColorTheme() *Theme {
+	none := lipgloss.NoColor{}
+	return &Theme{
+		Name:       "no-color",
+		Title:      none,
+		Heading:    none,
+		Label:      none,
+		Text:       none,
+		Muted:      none,
+		Highlight:  none,
+		Value:      none,
+		Loading:    none,
+		Error:      none,
+		Note:       none,
+		Attachment: none,
+		SelectedFg: none,
+		SelectedBg: none,
+
+		StatusTodo:    none,
+		StatusSnoozed: none,
+		StatusOpen:    none,
+		StatusPending: none,
+		StatusDone:    none,
+		StatusOther:   none,
+
+		PriorityUrgent: none,
+		PriorityHigh:   none,
+		PriorityMedium: none,
+		PriorityLow:    none,
+		PriorityNone:   none,
+
+		CardTodo:       none,
+		CardSnoozed:    none,
+		CardCreated:    none,
+		CardUnassigned: none,
+		CardOther:      none,
+
+		Markdown:         styles.NoTTYStyle,
+		ReverseSelection: true,
+	}
+}
+
+// builtinThemes maps theme names to their constructors
+var builtinThemes = map[string]func() *Theme{
+	"default":       defaultTheme,
+	"dark":          darkTheme,
+	"light":         lightTheme,
+	"high-contrast": highContrastTheme,
+	"no-color":      noColorTheme,
+}
+
+// colors maps the names used in ui.themes to the theme's color fields
+func (t *Theme) colors() map[string]*lipgloss.TerminalColor {
+	return map[string]*lipgloss.TerminalColor{
+		"title":           &t.Title,
+		"heading":         &t.Heading,
+		"label":           &t.Label,
+		"text":            &t.Text,
+		"muted":           &t.Muted,
+		"highlight":       &t.Highlight,
+		"value":           &t.Value,
+		"loading":         &t.Loading,
+		"error":           &t.Error,
+		"note":            &t.Note,
+		"attachment":      &t.Attachment,
+		"selected_fg":     &t.SelectedFg,
+		"selected_bg":     &t.SelectedBg,
+		"status_todo":     &t.StatusTodo,
+		"status_snoozed":  &t.StatusSnoozed,
+		"status_open":     &t.StatusOpen,
+		"status_pending":  &t.StatusPending,
+		"status_done":     &t.StatusDone,
+		"status_other":    &t.StatusOther,
+		"priority_urgent": &t.PriorityUrgent,
+		"priority_high":   &t.PriorityHigh,
+		"priority_medium": &t.PriorityMedium,
+		"priority_low":    &t.PriorityLow,
+		"priority_none":   &t.PriorityNone,
+		"card_todo":       &t.CardTodo,
+		"card_snoozed":    &t.CardSnoozed,
+		"card_created":    &t.CardCreated,
+		"card_unassigned": &t.CardUnassigned,
+		"card_other":      &t.CardOther,
+	}
+}
+
+// UseTheme selects the theme configured in ui.theme for the terminal UI
+func UseTheme(cfg *config.Config) error {
+	selected, err := LoadTheme(&cfg.UI)
+	if err != nil {
+		return err
+	}
+	theme = selected
+	return nil
+}
+
+// LoadTheme resolves the theme named by ui.theme, either built in or defined
+// under ui.themes. NO_COLOR always selects the no-color theme.
+func LoadTheme(cfg *config.UIConfig) (*Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return noColorTheme(), nil
+	}
+
+	name := cfg.Theme
+	if name == "" {
+		name = "default"
+	}
+	return resolveTheme(cfg, name, nil)
+}
+
+// resolveTheme builds a theme by name, applying a user theme on top of the theme it extends
+func resolveTheme(cfg *config.UIConfig, name string, seen []string) (*Theme, error) {
+	custom, ok := cfg.Themes[name]
+	if !ok {
+		if builtin, ok := builtinThemes[name]; ok {
+			return builtin(), nil
+		}
+		return nil, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(themeNames(cfg), ", "))
+	}
+
+	for _, s := range seen {
+		if s == name {
+			return nil, fmt.Errorf("theme %q extends itself", name)
+		}
+	}
+
+	base := custom.Extends
+	if base == "" {
+		base = "default"
+	}
+	// A user theme may override a built-in theme of the same name
+	var t *Theme
+	if base == name {
+		builtin, ok := builtinThemes[name]
+		if !ok {
+			return nil, fmt.Errorf("theme %q extends itself", name)
+		}
+		t = builtin()
+	} else {
+		var err error
+		if t, err = resolveTheme(cfg, base, append(seen, name)); err != nil {
+			return nil, err
+		}
+	}
+
+	t.Name = name
+	if custom.Markdown != "" {
+		if _, ok := styles.DefaultStyles[custom.Markdown]; !ok {
+			return nil, fmt.Errorf("theme %q: unknown markdown style %q", name, custom.Markdown)
+		}
+		t.Markdown = custom.Markdown
+	}
+	fields := t.colors()
+	for key, value := range custom.Colors {
+		field, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("theme %q: unknown color %q", name, key)
+		}
+		*field = lipgloss.Color(value)
+	}
+
+	return t, nil
+}
+
+// themeNames lists the built-in and user-defined theme names
+func themeNames(cfg *config.UIConfig) []string {
+	names := make([]string, 0, len(builtinThemes)+len(cfg.Themes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	for name := range cfg.Themes {
+		if _, ok := builtinThemes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// statusColor returns the color for a thread status
+func (t *Theme) statusColor(status string) lipgloss.TerminalColor {
+	switch status {
+	case "TODO":
+		return t.StatusTodo
+	case "SNOOZED":
+		return t.StatusSnoozed
+	case "OPEN":
+		return t.StatusOpen
+	case "PENDING":
+		return t.StatusPending
+	case "DONE":
+		return t.StatusDone
+	default:
+		return t.StatusOther
+	}
+}
+
+// priorityColor returns the color for a thread priority
+func (t *Theme) priorityColor(priority int) lipgloss.TerminalColor {
+	switch priority {
+	case 0:
+		return t.PriorityUrgent
+	case 1:
+		return t.PriorityHigh
+	case 2:
+		return t.PriorityMedium
+	case 3:
+		return t.PriorityLow
+	default:
+		return t.PriorityNone
+	}
+}
+
+// selectedStyle returns the style of the selected item in lists and pickers
+func (t *Theme) selectedStyle() lipgloss.Style {
+	if t.ReverseSelection {
+		return lipgloss.NewStyle().Reverse(true)
+	}
+	return lipgloss.NewStyle().
+		Foreground(t.SelectedFg).
+		Background(t.SelectedBg)
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"simple/config"
+)
+
+func TestLoadTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	cfg := &config.UIConfig{
+		Themes: map[string]config.ThemeConfig{
+			"solarized": {Extends: "light", Colors: map[string]string{"title": "#268BD2"}},
+			"night":     {Extends: "solarized", Markdown: "dark"},
+			"default":   {Extends: "default", Colors: map[string]string{"note": "208"}},
+			"loop":      {Extends: "loop"},
+			"a":         {Extends: "b"},
+			"b":         {Extends: "a"},
+			"bad-color": {Colors: map[string]string{"sparkle": "1"}},
+			"bad-style": {Markdown: "neon"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		check   func(*Theme) bool
+		wantErr string
+	}{
+		{name: "high-contrast", check: func(th *Theme) bool { return th.SelectedBg == lipgloss.Color("11") }},
+		{name: "solarized", check: func(th *Theme) bool {
+			return th.Title == lipgloss.Color("#268BD2") && th.Text == lightTheme().Text
+		}},
+		{name: "night", check: func(th *Theme) bool {
+			return th.Name == "night" && th.Title == lipgloss.Color("#268BD2") && th.Markdown == "dark"
+		}},
+		{name: "default", check: func(th *Theme) bool {
+			return th.Note == lipgloss.Color("208") && th.Title == defaultTheme().Title
+		}},
+		{name: "loop", wantErr: "extends itself"},
+		{name: "a", wantErr: "extends itself"},
+		{name: "bad-color", wantErr: `unknown color "sparkle"`},
+		{name: "bad-style", wantErr: `unknown markdown style "neon"`},
+		{name: "missing", wantErr: `unknown theme "missing"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Theme = tt.name
+			th, err := LoadTheme(cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.check(th) {
+				t.Errorf("theme %q not resolved as expected: %+v", tt.name, th)
+			}
+		})
+	}
+}
+
+func TestLoadThemeNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	th, err := LoadTheme(&config.UIConfig{Theme: "dark"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if th.Name != "no-color" || !th.ReverseSelection {
+		t.Errorf("expected the no-color theme with NO_COLOR set, got %q", th.Name)
+	}
+}
+
+// The config validates themes against its own lists of built-in themes and colors
+func TestThemeNamesMatchConfig(t *testing.T) {
+	for _, name := range config.BuiltinThemes {
+		if _, ok := builtinThemes[name]; !ok {
+			t.Errorf("config.BuiltinThemes has %q, which isn't a built-in theme", name)
+		}
+	}
+	if len(config.BuiltinThemes) != len(builtinThemes) {
+		t.Errorf("config.BuiltinThemes has %d themes, want %d", len(config.BuiltinThemes), len(builtinThemes))
+	}
+
+	colors := defaultTheme().colors()
+	for _, color := range config.ThemeColors {
+		if _, ok := colors[color]; !ok {
+			t.Errorf("config.ThemeColors has %q, which isn't a theme color", color)
+		}
+	}
+	if len(config.ThemeColors) != len(colors) {
+		t.Errorf("config.ThemeColors has %d colors, want %d", len(config.ThemeColors), len(colors))
+	}
+}
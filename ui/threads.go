@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -16,6 +17,8 @@ import (
 
 	"simple/client"
 	"simple/config"
+	"simple/query"
+	"simple/store"
 	"simple/types"
 )
 
@@ -39,6 +42,8 @@ const (
 // ThreadItem represents a thread item for the list
 type ThreadItem struct {
 	Thread *types.Thread
+	// Highlighted marks threads with a notification that haven't been opened yet
+	Highlighted bool
 }
 
 // FilterValue returns the filter value for the list
@@ -49,7 +54,7 @@ func (t ThreadItem) FilterValue() string {
 // ThreadsView represents the threads view
 type ThreadsView struct {
 	config         *config.Config
-	client         *client.PlainClient
+	source         client.DataSource
 	list           list.Model
 	filter         ThreadFilter
 	viewState      ThreadViewState
@@ -58,18 +63,43 @@ type ThreadsView struct {
 	error          string
 	cursor         string
 	hasNextPage    bool
+	// pageCursor is the cursor the current page was loaded after, so polling reloads the same page
+	pageCursor string
+	// listGeneration drops background refreshes that finish after the list was reloaded
+	listGeneration int
+	updatedAt      time.Time
+	pollError      string
+	highlighted    map[string]bool
 	viewport       viewport.Model
 	viewportReady  bool
-	width          int
-	height         int
+	labelCache     *client.LabelCache
+	labelPicker    *LabelPicker
+	labelFilter    []*types.LabelType
+	queryPrompt    *QueryPrompt
+	noteEditor     *NoteEditor
+	keys           keyMap
+	// timelineLoading is set while an earlier page of the timeline is fetched
+	timelineLoading bool
+	// timelineError is the error of the last failed load of earlier entries, shown above the timeline
+	timelineError string
+	markdown      markdownRenderer
+	expandQuoted  bool
+	query         *query.Compiled
+	queryInput    string
+	width         int
+	height        int
 }
 
 // threadsLoadedMsg is sent when threads are loaded
 type threadsLoadedMsg struct {
 	threads     []*types.Thread
+	after       string
 	cursor      string
 	hasNextPage bool
-	error       string
+	// background is set for polling refreshes, which keep the selection and show errors in the status line
+	background bool
+	generation int
+	error      string
 }
 
 // threadDetailLoadedMsg is sent when thread details with messages are loaded
@@ -78,74 +108,190 @@ type threadDetailLoadedMsg struct {
 	error  string
 }
 
+// timelinePageLoadedMsg is sent when an earlier page of a thread's timeline is loaded
+type timelinePageLoadedMsg struct {
+	threadID string
+	entries  *types.TimelineEntryConnection
+	error    string
+}
+
+// noteCreatedMsg is sent when an internal note has been added to a thread
+type noteCreatedMsg struct {
+	threadID string
+	error    string
+}
+
+// threadLabelsUpdatedMsg is sent when the labels of a thread have been changed
+type threadLabelsUpdatedMsg struct {
+	threadID string
+	error    string
+}
+
 // NewThreadsView creates a new threads view
-func NewThreadsView(cfg *config.Config, client *client.PlainClient) *ThreadsView {
+func NewThreadsView(cfg *config.Config, source client.DataSource) *ThreadsView {
 	// Create list model
 	l := list.New([]list.Item{}, threadDelegate{}, 0, 0)
 	l.Title = "Threads"
 	l.SetShowStatusBar(true)
 	l.SetShowHelp(true)
-	l.SetFilteringEnabled(true)
+	// '/' opens the query prompt instead of the list's fuzzy filter
+	l.SetFilteringEnabled(false)
 
 	// Custom keybindings
+	keys := newKeyMap(cfg)
 	l.KeyMap.Quit = key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
+		key.WithKeys(keys.binding(config.KeyQuit).Keys()...),
+		key.WithHelp(keys.keys(config.KeyQuit), "quit"),
 	)
 
+	labelCache := client.NewLabelCache(source)
+
 	return &ThreadsView{
-		config:    cfg,
-		client:    client,
-		list:      l,
-		filter:    FilterTODO,
-		viewState: ViewList,
+		config:      cfg,
+		source:      source,
+		list:        l,
+		filter:      FilterTODO,
+		viewState:   ViewList,
+		labelCache:  labelCache,
+		labelPicker: NewLabelPicker(labelCache, keys),
+		queryPrompt: NewQueryPrompt(keys),
+		noteEditor:  NewNoteEditor(keys),
+		highlighted: make(map[string]bool),
+		keys:        keys,
 	}
 }
 
 // Init initializes the threads view
 func (tv *ThreadsView) Init() tea.Cmd {
-	return tv.loadThreads("")
+	return tea.Batch(tv.loadThreads(""), tv.schedulePoll())
 }
 
-// loadThreads loads threads from the API
+// schedulePoll schedules the next background refresh of the list, if polling is on
+func (tv *ThreadsView) schedulePoll() tea.Cmd {
+	return pollAfter(tv.config.UI.RefreshInterval, threadsPollMsg{})
+}
+
+// loadThreads loads a page of threads from the API, showing the loading state
 func (tv *ThreadsView) loadThreads(cursor string) tea.Cmd {
+	tv.loading = true
+	tv.listGeneration++
+	return tv.fetchThreads(cursor, false)
+}
+
+// fetchThreads fetches the page of threads after cursor
+func (tv *ThreadsView) fetchThreads(cursor string, background bool) tea.Cmd {
+	generation := tv.listGeneration
 	return tea.Cmd(func() tea.Msg {
-		ctx := context.Background()
-		tv.loading = true
+		msg := tv.queryThreads(cursor)
+		msg.after = cursor
+		msg.background = background
+		msg.generation = generation
+		return msg
+	})
+}
 
-		var threads *types.ThreadConnection
-		var err error
+// queryThreads runs the query for the current filters
+func (tv *ThreadsView) queryThreads(cursor string) threadsLoadedMsg {
+	ctx := context.Background()
 
+	var threads *types.ThreadConnection
+	var err error
+
+	// Queries are combined with the status keys and label filter
+	if tv.query != nil {
+		compiled := tv.narrowQuery()
+		if err := compiled.ResolveLabels(ctx, tv.labelCache); err != nil {
+			return threadsLoadedMsg{error: err.Error()}
+		}
+		if len(compiled.LabelNames) == 0 && len(tv.labelFilter) > 0 {
+			for _, label := range tv.labelFilter {
+				compiled.Filter.LabelTypeIDs = append(compiled.Filter.LabelTypeIDs, label.ID)
+			}
+		}
+		threads, err = compiled.Fetch(ctx, tv.source, tv.config.UI.PageSize, cursor)
+	} else if len(tv.labelFilter) > 0 {
+		// Label filters need the generic filtered query, statuses are applied alongside them
+		filter := client.ThreadFilter{}
 		switch tv.filter {
 		case FilterTODO:
-			threads, err = tv.client.GetThreadsByStatus(ctx, "TODO", tv.config.UI.PageSize, cursor)
+			filter.Statuses = []string{"TODO"}
 		case FilterSNOOZED:
-			threads, err = tv.client.GetThreadsByStatus(ctx, "SNOOZED", tv.config.UI.PageSize, cursor)
+			filter.Statuses = []string{"SNOOZED"}
+		}
+		for _, label := range tv.labelFilter {
+			filter.LabelTypeIDs = append(filter.LabelTypeIDs, label.ID)
+		}
+		threads, err = tv.source.GetThreadsByFilter(ctx, filter, tv.config.UI.PageSize, cursor)
+	} else {
+		switch tv.filter {
+		case FilterTODO:
+			threads, err = tv.source.GetThreadsByStatus(ctx, "TODO", tv.config.UI.PageSize, cursor)
+		case FilterSNOOZED:
+			threads, err = tv.source.GetThreadsByStatus(ctx, "SNOOZED", tv.config.UI.PageSize, cursor)
 		case FilterAll:
-			threads, err = tv.client.GetAllThreads(ctx, tv.config.UI.PageSize, cursor)
+			threads, err = tv.source.GetAllThreads(ctx, tv.config.UI.PageSize, cursor)
 		default:
-			threads, err = tv.client.GetThreadsByStatus(ctx, "TODO", tv.config.UI.PageSize, cursor)
+			threads, err = tv.source.GetThreadsByStatus(ctx, "TODO", tv.config.UI.PageSize, cursor)
 		}
+	}
 
-		if err != nil {
-			return threadsLoadedMsg{error: err.Error()}
-		}
+	if err != nil {
+		return threadsLoadedMsg{error: err.Error()}
+	}
 
-		if threads == nil || threads.Edges == nil {
-			return threadsLoadedMsg{error: "No threads returned from API"}
-		}
+	if threads == nil || threads.Edges == nil {
+		return threadsLoadedMsg{error: "No threads returned from API"}
+	}
+
+	threadList := make([]*types.Thread, len(threads.Edges))
+	for i, edge := range threads.Edges {
+		threadList[i] = edge.Node
+	}
+
+	return threadsLoadedMsg{
+		threads:     threadList,
+		cursor:      threads.PageInfo.EndCursor,
+		hasNextPage: threads.PageInfo.HasNextPage,
+	}
+}
+
+// narrowQuery combines the current query with the status keys and label filter.
+// Statuses in the query take precedence over the status keys.
+func (tv *ThreadsView) narrowQuery() *query.Compiled {
+	compiled := tv.query.Clone()
 
-		threadList := make([]*types.Thread, len(threads.Edges))
-		for i, edge := range threads.Edges {
-			threadList[i] = edge.Node
+	if len(compiled.Filter.Statuses) == 0 {
+		switch tv.filter {
+		case FilterTODO:
+			compiled.Filter.Statuses = []string{"TODO"}
+		case FilterSNOOZED:
+			compiled.Filter.Statuses = []string{"SNOOZED"}
 		}
+	}
+
+	if len(tv.labelFilter) > 0 {
+		labelFilter := tv.labelFilter
+		compiled.Predicates = append(compiled.Predicates, func(thread *types.Thread) bool {
+			for _, label := range labelFilter {
+				if containsString(labelIDs(thread), label.ID) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	return compiled
+}
 
-		return threadsLoadedMsg{
-			threads:     threadList,
-			cursor:      threads.PageInfo.EndCursor,
-			hasNextPage: threads.PageInfo.HasNextPage,
+// containsString returns true if value is in values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
-	})
+	}
+	return false
 }
 
 // loadThreadDetail loads detailed thread information with messages from the API
@@ -153,7 +299,7 @@ func (tv *ThreadsView) loadThreadDetail(threadID string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		ctx := context.Background()
 
-		thread, err := tv.client.GetThreadWithMessages(ctx, threadID)
+		thread, err := tv.source.GetThreadWithMessages(ctx, threadID)
 		if err != nil {
 			return threadDetailLoadedMsg{error: err.Error()}
 		}
@@ -164,14 +310,159 @@ func (tv *ThreadsView) loadThreadDetail(threadID string) tea.Cmd {
 	})
 }
 
+// loadEarlierTimeline loads the page of timeline entries before the oldest loaded entry
+func (tv *ThreadsView) loadEarlierTimeline() tea.Cmd {
+	thread := tv.selectedThread
+	if tv.timelineLoading || thread == nil || thread.TimelineEntries == nil {
+		return nil
+	}
+	pageInfo := thread.TimelineEntries.PageInfo
+	if pageInfo == nil || !pageInfo.HasPreviousPage {
+		return nil
+	}
+
+	tv.timelineLoading = true
+	tv.updateViewport()
+
+	threadID := thread.ID
+	before := pageInfo.StartCursor
+	return tea.Cmd(func() tea.Msg {
+		entries, err := tv.source.GetThreadTimeline(context.Background(), threadID, client.TimelinePageSize, before)
+		if err != nil {
+			return timelinePageLoadedMsg{threadID: threadID, error: err.Error()}
+		}
+		return timelinePageLoadedMsg{threadID: threadID, entries: entries}
+	})
+}
+
+// prependTimeline adds an earlier page of entries above the loaded ones, keeping the
+// viewport on the entry that was at the top
+func (tv *ThreadsView) prependTimeline(entries *types.TimelineEntryConnection) {
+	timeline := tv.selectedThread.TimelineEntries
+	if entries != nil {
+		timeline.Edges = append(entries.Edges, timeline.Edges...)
+		if entries.PageInfo != nil {
+			timeline.PageInfo.HasPreviousPage = entries.PageInfo.HasPreviousPage
+			timeline.PageInfo.StartCursor = entries.PageInfo.StartCursor
+		}
+	}
+
+	before := tv.viewport.TotalLineCount()
+	offset := tv.viewport.YOffset
+	tv.updateViewport()
+	tv.viewport.SetYOffset(offset + tv.viewport.TotalLineCount() - before)
+}
+
+// createNote adds an internal note to a thread
+func (tv *ThreadsView) createNote(msg noteSubmitMsg) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		plainClient, err := tv.plainClient()
+		if err != nil {
+			return noteCreatedMsg{threadID: msg.threadID, error: err.Error()}
+		}
+		if _, err := plainClient.CreateNote(context.Background(), msg.customerID, msg.threadID, msg.text); err != nil {
+			return noteCreatedMsg{threadID: msg.threadID, error: err.Error()}
+		}
+		return noteCreatedMsg{threadID: msg.threadID}
+	})
+}
+
+// setThreadLabels adds and removes labels so the thread ends up with exactly the given labels
+func (tv *ThreadsView) setThreadLabels(thread *types.Thread, labels []*types.LabelType) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx := context.Background()
+		plainClient, err := tv.plainClient()
+		if err != nil {
+			return threadLabelsUpdatedMsg{threadID: thread.ID, error: err.Error()}
+		}
+
+		wanted := make(map[string]bool, len(labels))
+		for _, label := range labels {
+			wanted[label.ID] = true
+		}
+
+		current := make(map[string]bool, len(thread.Labels))
+		var removeIDs []string
+		for _, label := range thread.Labels {
+			current[label.LabelType.ID] = true
+			if !wanted[label.LabelType.ID] {
+				removeIDs = append(removeIDs, label.ID)
+			}
+		}
+
+		var addIDs []string
+		for _, label := range labels {
+			if !current[label.ID] {
+				addIDs = append(addIDs, label.ID)
+			}
+		}
+
+		if len(addIDs) > 0 {
+			if _, err := plainClient.AddLabels(ctx, thread.ID, addIDs); err != nil {
+				return threadLabelsUpdatedMsg{threadID: thread.ID, error: err.Error()}
+			}
+		}
+
+		if len(removeIDs) > 0 {
+			if err := plainClient.RemoveLabels(ctx, removeIDs); err != nil {
+				return threadLabelsUpdatedMsg{threadID: thread.ID, error: err.Error()}
+			}
+		}
+
+		return threadLabelsUpdatedMsg{threadID: thread.ID}
+	})
+}
+
+// plainClient returns the API client for changes to threads, which can't be made offline
+func (tv *ThreadsView) plainClient() (*client.PlainClient, error) {
+	plainClient, ok := tv.source.(*client.PlainClient)
+	if !ok {
+		return nil, fmt.Errorf("changing threads is %w", client.ErrOffline)
+	}
+	return plainClient, nil
+}
+
+// labelIDs returns the label type IDs applied to a thread
+func labelIDs(thread *types.Thread) []string {
+	ids := make([]string, 0, len(thread.Labels))
+	for _, label := range thread.Labels {
+		ids = append(ids, label.LabelType.ID)
+	}
+	return ids
+}
+
 // Update handles messages and updates the model
 func (tv *ThreadsView) Update(msg tea.Msg) (*ThreadsView, tea.Cmd) {
+	// The label picker and query prompt capture all key presses while open
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && tv.labelPicker.Active() {
+		var cmd tea.Cmd
+		tv.labelPicker, cmd = tv.labelPicker.Update(keyMsg)
+		return tv, cmd
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && tv.queryPrompt.Active() {
+		var cmd tea.Cmd
+		tv.queryPrompt, cmd = tv.queryPrompt.Update(keyMsg)
+		return tv, cmd
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && tv.noteEditor.Active() {
+		var cmd tea.Cmd
+		tv.noteEditor, cmd = tv.noteEditor.Update(keyMsg)
+		return tv, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		tv.width = msg.Width
 		tv.height = msg.Height
+		tv.labelPicker.SetWidth(msg.Width)
+		tv.queryPrompt.SetWidth(msg.Width)
+		tv.noteEditor.SetSize(msg.Width, msg.Height)
 		tv.list.SetWidth(msg.Width)
-		tv.list.SetHeight(msg.Height - 4) // Account for padding
+		listHeight := msg.Height - 4 // Account for padding
+		if tv.renderStatus() != "" {
+			listHeight-- // Status line
+		}
+		tv.list.SetHeight(listHeight)
 
 		// Update viewport if in detail view
 		if tv.viewState == ViewDetail {
@@ -180,36 +471,150 @@ func (tv *ThreadsView) Update(msg tea.Msg) (*ThreadsView, tea.Cmd) {
 		return tv, nil
 
 	case threadsLoadedMsg:
-		tv.loading = false
-		if msg.error != "" {
-			tv.error = msg.error
+		if msg.generation != tv.listGeneration {
+			// The list was reloaded while this page was fetched
+			return tv, nil
+		}
+		if msg.background {
+			if tv.loading {
+				return tv, nil
+			}
+			if msg.error != "" {
+				tv.pollError = msg.error
+				return tv, nil
+			}
 		} else {
-			tv.error = ""
-			tv.cursor = msg.cursor
-			tv.hasNextPage = msg.hasNextPage
-
-			// Convert threads to list items
-			items := make([]list.Item, len(msg.threads))
-			for i, thread := range msg.threads {
-				items[i] = ThreadItem{Thread: thread}
+			tv.loading = false
+			if msg.error != "" {
+				tv.error = msg.error
+				return tv, nil
 			}
+		}
 
-			tv.list.SetItems(items)
-			tv.updateTitle()
+		tv.error = ""
+		tv.pollError = ""
+		tv.updatedAt = time.Now()
+		tv.pageCursor = msg.after
+		tv.cursor = msg.cursor
+		tv.hasNextPage = msg.hasNextPage
+
+		// Convert threads to list items
+		items := make([]list.Item, len(msg.threads))
+		for i, thread := range msg.threads {
+			items[i] = ThreadItem{Thread: thread, Highlighted: tv.highlighted[thread.ID]}
+		}
+
+		selectedID := ""
+		if item, ok := tv.list.SelectedItem().(ThreadItem); ok && msg.background {
+			selectedID = item.Thread.ID
 		}
+		tv.list.SetItems(items)
+		// Polling keeps the cursor on the same thread wherever it moved to
+		for i, thread := range msg.threads {
+			if thread.ID == selectedID {
+				tv.list.Select(i)
+				break
+			}
+		}
+		tv.updateTitle()
 		return tv, nil
 
+	case threadsPollMsg:
+		next := tv.schedulePoll()
+		// Overlays and foreground loads pause polling until the next tick
+		if tv.loading || tv.IsCapturingInput() {
+			return tv, next
+		}
+		return tv, tea.Batch(next, tv.fetchThreads(tv.pageCursor, true))
+
 	case threadDetailLoadedMsg:
 		tv.loading = false
+		tv.timelineLoading = false
+		tv.timelineError = ""
 		if msg.error != "" {
 			tv.error = msg.error
 		} else {
 			tv.error = ""
 			tv.selectedThread = msg.thread
 			tv.updateViewport()
+			// The newest entries are at the bottom
+			tv.viewport.GotoBottom()
+		}
+		return tv, nil
+
+	case timelinePageLoadedMsg:
+		if tv.selectedThread == nil || tv.selectedThread.ID != msg.threadID || !tv.timelineLoading {
+			return tv, nil
+		}
+		tv.timelineLoading = false
+		tv.timelineError = msg.error
+		if msg.error != "" {
+			tv.updateViewport()
+			return tv, nil
+		}
+		tv.prependTimeline(msg.entries)
+		return tv, nil
+
+	case labelsLoadedMsg:
+		var cmd tea.Cmd
+		tv.labelPicker, cmd = tv.labelPicker.Update(msg)
+		return tv, cmd
+
+	case labelPickerDoneMsg:
+		switch msg.mode {
+		case PickerFilter:
+			tv.labelFilter = msg.labels
+			tv.updateTitle()
+			return tv, tv.loadThreads("")
+		case PickerApply:
+			thread := tv.selectedThread
+			if tv.viewState == ViewList {
+				if item, ok := tv.list.SelectedItem().(ThreadItem); ok {
+					thread = item.Thread
+				}
+			}
+			if thread == nil || thread.ID != msg.threadID {
+				return tv, nil
+			}
+			tv.loading = true
+			return tv, tv.setThreadLabels(thread, msg.labels)
 		}
 		return tv, nil
 
+	case queryPromptDoneMsg:
+		tv.query = msg.compiled
+		tv.queryInput = msg.input
+		tv.updateTitle()
+		return tv, tv.loadThreads("")
+
+	case noteSubmitMsg:
+		tv.loading = true
+		return tv, tv.createNote(msg)
+
+	case noteCreatedMsg:
+		tv.loading = false
+		if msg.error != "" {
+			tv.error = msg.error
+			return tv, nil
+		}
+		if tv.viewState == ViewDetail && tv.selectedThread != nil && tv.selectedThread.ID == msg.threadID {
+			tv.loading = true
+			return tv, tv.loadThreadDetail(msg.threadID)
+		}
+		return tv, nil
+
+	case threadLabelsUpdatedMsg:
+		tv.loading = false
+		if msg.error != "" {
+			tv.error = msg.error
+			return tv, nil
+		}
+		if tv.viewState == ViewDetail && tv.selectedThread != nil && tv.selectedThread.ID == msg.threadID {
+			tv.loading = true
+			return tv, tv.loadThreadDetail(msg.threadID)
+		}
+		return tv, tv.loadThreads("")
+
 	case tea.KeyMsg:
 		if tv.viewState == ViewDetail {
 			return tv.handleDetailKeys(msg)
@@ -225,36 +630,51 @@ func (tv *ThreadsView) Update(msg tea.Msg) (*ThreadsView, tea.Cmd) {
 
 // handleListKeys handles key events in list view
 func (tv *ThreadsView) handleListKeys(msg tea.KeyMsg) (*ThreadsView, tea.Cmd) {
-	switch msg.String() {
-	case "1":
+	switch {
+	case tv.keys.matches(msg, config.KeyFilterTODO):
 		if tv.filter != FilterTODO {
 			tv.filter = FilterTODO
 			tv.updateTitle()
 			return tv, tv.loadThreads("")
 		}
-	case "2":
+	case tv.keys.matches(msg, config.KeyFilterSnoozed):
 		if tv.filter != FilterSNOOZED {
 			tv.filter = FilterSNOOZED
 			tv.updateTitle()
 			return tv, tv.loadThreads("")
 		}
-	case "3":
+	case tv.keys.matches(msg, config.KeyFilterAll):
 		if tv.filter != FilterAll {
 			tv.filter = FilterAll
 			tv.updateTitle()
 			return tv, tv.loadThreads("")
 		}
-	case "r":
+	case tv.keys.matches(msg, config.KeyRefresh):
 		return tv, tv.loadThreads("")
-	case "n":
+	case tv.keys.matches(msg, config.KeyFilterLabels):
+		preselected := make([]string, 0, len(tv.labelFilter))
+		for _, label := range tv.labelFilter {
+			preselected = append(preselected, label.ID)
+		}
+		return tv, tv.labelPicker.Open(PickerFilter, "", preselected)
+	case tv.keys.matches(msg, config.KeyQuery):
+		return tv, tv.queryPrompt.Open(tv.queryInput)
+	case tv.keys.matches(msg, config.KeySearch):
+		return tv, func() tea.Msg { return openSearchMsg{} }
+	case tv.keys.matches(msg, config.KeyLabelThread):
+		if item, ok := tv.list.SelectedItem().(ThreadItem); ok {
+			return tv, tv.labelPicker.Open(PickerApply, item.Thread.ID, labelIDs(item.Thread))
+		}
+	case tv.keys.matches(msg, config.KeyNextPage):
 		if tv.hasNextPage {
 			return tv, tv.loadThreads(tv.cursor)
 		}
-	case "enter":
+	case tv.keys.matches(msg, config.KeyOpen):
 		if item, ok := tv.list.SelectedItem().(ThreadItem); ok {
 			tv.selectedThread = item.Thread
 			tv.viewState = ViewDetail
 			tv.loading = true
+			tv.clearHighlight(item.Thread.ID)
 			return tv, tv.loadThreadDetail(item.Thread.ID)
 		}
 	}
@@ -269,19 +689,41 @@ func (tv *ThreadsView) handleListKeys(msg tea.KeyMsg) (*ThreadsView, tea.Cmd) {
 func (tv *ThreadsView) handleDetailKeys(msg tea.KeyMsg) (*ThreadsView, tea.Cmd) {
 	var cmd tea.Cmd
 
-	switch msg.String() {
-	case "q", "esc":
+	switch {
+	case tv.keys.matches(msg, config.KeyBack), tv.keys.matches(msg, config.KeyQuit):
 		tv.viewState = ViewList
 		tv.selectedThread = nil
 		tv.viewportReady = false
-	case "b":
+		tv.timelineLoading = false
+		tv.timelineError = ""
+		tv.error = ""
+	case tv.keys.matches(msg, config.KeyOpenBrowser):
 		if tv.selectedThread != nil {
 			return tv, tv.openInBrowser(tv.selectedThread.ID)
 		}
+	case tv.keys.matches(msg, config.KeyEditLabels):
+		if tv.selectedThread != nil {
+			return tv, tv.labelPicker.Open(PickerApply, tv.selectedThread.ID, labelIDs(tv.selectedThread))
+		}
+	case tv.keys.matches(msg, config.KeyNote):
+		if tv.selectedThread != nil && tv.selectedThread.Customer != nil {
+			return tv, tv.noteEditor.Open(tv.selectedThread.ID, tv.selectedThread.Customer.ID)
+		}
+	case tv.keys.matches(msg, config.KeyExpandQuoted):
+		tv.expandQuoted = !tv.expandQuoted
+		tv.updateViewport()
+	case tv.keys.matches(msg, config.KeyCustomer):
+		if tv.selectedThread != nil && tv.selectedThread.Customer != nil && tv.selectedThread.Customer.ID != "" {
+			customerID := tv.selectedThread.Customer.ID
+			return tv, func() tea.Msg { return openCustomerMsg{customerID: customerID} }
+		}
 	default:
 		// Handle viewport scrolling
 		if tv.viewportReady {
 			tv.viewport, cmd = tv.viewport.Update(msg)
+			if tv.viewport.AtTop() {
+				return tv, tea.Batch(cmd, tv.loadEarlierTimeline())
+			}
 		}
 	}
 	return tv, cmd
@@ -298,9 +740,69 @@ func (tv *ThreadsView) updateTitle() {
 	case FilterAll:
 		title = "Threads (All)"
 	}
+	if tv.query != nil && len(tv.query.Filter.Statuses) > 0 {
+		title = "Threads (" + strings.Join(tv.query.Filter.Statuses, ", ") + ")"
+	}
+
+	if len(tv.labelFilter) > 0 {
+		names := make([]string, len(tv.labelFilter))
+		for i, label := range tv.labelFilter {
+			names[i] = label.Name
+		}
+		title += " · Labels: " + strings.Join(names, ", ")
+	}
+	if tv.queryInput != "" {
+		title += " · Query: " + tv.queryInput
+	}
 	tv.list.Title = title
 }
 
+// Highlight marks threads in the list until they are opened
+func (tv *ThreadsView) Highlight(threadIDs []string) {
+	if len(threadIDs) == 0 {
+		return
+	}
+	for _, id := range threadIDs {
+		tv.highlighted[id] = true
+	}
+	for i, listItem := range tv.list.Items() {
+		if item, ok := listItem.(ThreadItem); ok && tv.highlighted[item.Thread.ID] && !item.Highlighted {
+			item.Highlighted = true
+			tv.list.SetItem(i, item)
+		}
+	}
+}
+
+// clearHighlight removes the highlight from a thread once it has been opened
+func (tv *ThreadsView) clearHighlight(threadID string) {
+	if !tv.highlighted[threadID] {
+		return
+	}
+	delete(tv.highlighted, threadID)
+	for i, listItem := range tv.list.Items() {
+		if item, ok := listItem.(ThreadItem); ok && item.Thread.ID == threadID {
+			item.Highlighted = false
+			tv.list.SetItem(i, item)
+		}
+	}
+}
+
+// OpenThread switches to the detail view and loads the given thread
+func (tv *ThreadsView) OpenThread(threadID string) tea.Cmd {
+	tv.selectedThread = &types.Thread{ID: threadID}
+	tv.clearHighlight(threadID)
+	tv.viewState = ViewDetail
+	tv.viewportReady = false
+	tv.loading = true
+	return tv.loadThreadDetail(threadID)
+}
+
+// IsCapturingInput returns true while a text input (query prompt, label picker or note editor) has focus,
+// so global key bindings must not be handled
+func (tv *ThreadsView) IsCapturingInput() bool {
+	return tv.labelPicker.Active() || tv.queryPrompt.Active() || tv.noteEditor.Active()
+}
+
 // IsInDetailView returns true if in detail view
 func (tv *ThreadsView) IsInDetailView() bool {
 	return tv.viewState == ViewDetail
@@ -308,6 +810,18 @@ func (tv *ThreadsView) IsInDetailView() bool {
 
 // View renders the threads view
 func (tv *ThreadsView) View() string {
+	if tv.labelPicker.Active() {
+		return tv.labelPicker.View()
+	}
+
+	if tv.queryPrompt.Active() {
+		return tv.queryPrompt.View()
+	}
+
+	if tv.noteEditor.Active() {
+		return tv.noteEditor.View()
+	}
+
 	if tv.loading {
 		return tv.renderLoading()
 	}
@@ -326,7 +840,7 @@ func (tv *ThreadsView) View() string {
 // renderLoading renders the loading state
 func (tv *ThreadsView) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("69")).
+		Foreground(theme.Loading).
 		Padding(2)
 	return style.Render("Loading threads...")
 }
@@ -334,9 +848,9 @@ func (tv *ThreadsView) renderLoading() string {
 // renderError renders the error state
 func (tv *ThreadsView) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")).
+		Foreground(theme.Error).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("196")).
+		BorderForeground(theme.Error).
 		Padding(1, 2)
 
 	content := fmt.Sprintf("Error: %s\n\nPress 'r' to retry or 'q' to quit.", tv.error)
@@ -346,9 +860,33 @@ func (tv *ThreadsView) renderError() string {
 // renderList renders the list view
 func (tv *ThreadsView) renderList() string {
 	helpText := tv.renderHelpText()
+	if status := tv.renderStatus(); status != "" {
+		return tv.list.View() + "\n" + status + "\n" + helpText
+	}
 	return tv.list.View() + "\n" + helpText
 }
 
+// renderStatus renders the offline indicator and when the list was last
+// refreshed while polling is on
+func (tv *ThreadsView) renderStatus() string {
+	var parts []string
+	if offline := offlineStatus(tv.source, time.Now()); offline != "" {
+		parts = append(parts, "⚠ "+offline)
+	}
+	if tv.config.UI.RefreshInterval > 0 {
+		parts = append(parts, fmt.Sprintf("⟳ %s • every %s", updatedAgo(tv.updatedAt, time.Now()), tv.config.UI.RefreshInterval))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	status := strings.Join(parts, " • ")
+	if tv.pollError != "" {
+		return lipgloss.NewStyle().Foreground(theme.Error).Render(status + " • refresh failed: " + tv.pollError)
+	}
+	return lipgloss.NewStyle().Foreground(theme.Muted).Render(status)
+}
+
 // renderDetail renders the detail view
 func (tv *ThreadsView) renderDetail() string {
 	if tv.selectedThread == nil {
@@ -371,16 +909,16 @@ func (tv *ThreadsView) renderDetailHeader() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205"))
+		Foreground(theme.Title)
 
 	// Status styling
-	statusColor := getStatusColor(thread.Status)
+	statusColor := theme.statusColor(thread.Status)
 	statusStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(statusColor)
 
 	// Priority styling
-	priorityColor := getPriorityColor(thread.Priority)
+	priorityColor := theme.priorityColor(thread.Priority)
 	priorityStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(priorityColor)
@@ -388,10 +926,10 @@ func (tv *ThreadsView) renderDetailHeader() string {
 	// Thread details styles
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39"))
+		Foreground(theme.Label)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(theme.Text)
 
 	var content strings.Builder
 
@@ -428,6 +966,16 @@ func (tv *ThreadsView) renderDetailHeader() string {
 		}
 	}
 
+	if len(thread.Labels) > 0 {
+		names := make([]string, len(thread.Labels))
+		for i, label := range thread.Labels {
+			names[i] = label.LabelType.Name
+		}
+		content.WriteString(labelStyle.Render("Labels: "))
+		content.WriteString(valueStyle.Render(strings.Join(names, ", ")))
+		content.WriteString("\n")
+	}
+
 	if thread.CreatedAt != nil {
 		if t, err := thread.CreatedAt.Time(); err == nil {
 			content.WriteString(labelStyle.Render("Created: "))
@@ -445,7 +993,7 @@ func (tv *ThreadsView) renderDetailHeader() string {
 	}
 
 	// Create a border line
-	borderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	borderStyle := lipgloss.NewStyle().Foreground(theme.Muted)
 	line := strings.Repeat("─", tv.width-2)
 	content.WriteString("\n")
 	content.WriteString(borderStyle.Render(line))
@@ -456,19 +1004,31 @@ func (tv *ThreadsView) renderDetailHeader() string {
 // renderDetailFooter renders the footer for detail view
 func (tv *ThreadsView) renderDetailFooter() string {
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(theme.Muted)
 
 	scrollStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("118"))
+		Foreground(theme.Highlight)
 
-	help := "b: Open in browser • q/esc: Back to list • ↑/↓: Scroll"
+	expand := tv.keys.help(config.KeyExpandQuoted, "Expand quotes")
+	if tv.expandQuoted {
+		expand = tv.keys.help(config.KeyExpandQuoted, "Collapse quotes")
+	}
+	help := strings.Join([]string{
+		tv.keys.help(config.KeyOpenBrowser, "Open in browser"),
+		tv.keys.help(config.KeyCustomer, "Customer"),
+		tv.keys.help(config.KeyEditLabels, "Labels"),
+		tv.keys.help(config.KeyNote, "Note"),
+		expand,
+		tv.keys.keys(config.KeyQuit) + "/" + tv.keys.help(config.KeyBack, "Back to list"),
+		"↑/↓: Scroll",
+	}, " • ")
 
 	scrollInfo := ""
 	if tv.viewportReady {
 		scrollInfo = fmt.Sprintf("%3.f%%", tv.viewport.ScrollPercent()*100)
 	}
 
-	borderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	borderStyle := lipgloss.NewStyle().Foreground(theme.Muted)
 	line := strings.Repeat("─", max(0, tv.width-lipgloss.Width(scrollInfo)-2))
 
 	if scrollInfo != "" {
@@ -524,10 +1084,10 @@ func (tv *ThreadsView) renderThreadContent() string {
 
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39"))
+		Foreground(theme.Label)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(theme.Text)
 
 	// Messages section from timeline entries
 	if thread.TimelineEntries != nil && len(thread.TimelineEntries.Edges) > 0 {
@@ -537,32 +1097,50 @@ func (tv *ThreadsView) renderThreadContent() string {
 		content.WriteString("\n\n")
 
 		messageStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
+			Foreground(theme.Text).
 			Padding(0, 0, 1, 0)
 
 		senderStyle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("118"))
+			Foreground(theme.Highlight)
 
 		timestampStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241"))
+			Foreground(theme.Muted)
 
 		entryTypeStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("39")).
+			Foreground(theme.Label).
 			Bold(true)
 
-		for i := len(thread.TimelineEntries.Edges) - 1; i >= 0; i-- {
-			edge := thread.TimelineEntries.Edges[i]
+		attachmentStyle := lipgloss.NewStyle().
+			Foreground(theme.Attachment).
+			PaddingLeft(2)
+
+		noteStyle := lipgloss.NewStyle().
+			Border(lipgloss.ThickBorder(), false, false, false, true).
+			BorderForeground(theme.Note).
+			PaddingLeft(1)
+
+		// Older entries are loaded when scrolling to the top
+		pageInfo := thread.TimelineEntries.PageInfo
+		if tv.timelineLoading {
+			content.WriteString(timestampStyle.Render("Loading earlier messages…"))
+			content.WriteString("\n\n")
+		} else if tv.timelineError != "" {
+			content.WriteString(lipgloss.NewStyle().Foreground(theme.Error).Render("Failed to load earlier messages: " + tv.timelineError + " (scroll up to retry)"))
+			content.WriteString("\n\n")
+		} else if pageInfo != nil && pageInfo.HasPreviousPage {
+			content.WriteString(timestampStyle.Render("↑ Scroll up for earlier messages"))
+			content.WriteString("\n\n")
+		}
+
+		edges := thread.TimelineEntries.Edges
+		for i, edge := range edges {
 			entry := edge.Node
 
+			var entryContent strings.Builder
+
 			// Message header with sender and timestamp
-			sender := "Unknown"
-			if entry.Actor != nil {
-				sender = entry.Actor.GetFullName()
-				if sender == "" {
-					sender = entry.Actor.GetID()
-				}
-			}
+			sender := entry.ActorName()
 
 			timestamp := ""
 			if entry.Timestamp != nil {
@@ -571,29 +1149,49 @@ func (tv *ThreadsView) renderThreadContent() string {
 				}
 			}
 
-			content.WriteString(senderStyle.Render(sender))
+			entryContent.WriteString(senderStyle.Render(sender))
 			if timestamp != "" {
-				content.WriteString(" ")
-				content.WriteString(timestampStyle.Render(timestamp))
+				entryContent.WriteString(" ")
+				entryContent.WriteString(timestampStyle.Render(timestamp))
 			}
-			content.WriteString("\n")
+			entryContent.WriteString("\n")
 
 			// Message content based on entry type
-			messageContent := tv.getEntryContent(entry)
-			entryType := tv.getEntryType(entry)
+			messageContent := entry.Text()
+			entryType := entry.Kind()
+
+			if entry.IsInternal() {
+				entryContent.WriteString(entryTypeStyle.Foreground(theme.Note).Render(fmt.Sprintf("[🔒 %s] ", entryType)))
+			} else if entryType != "" {
+				entryContent.WriteString(entryTypeStyle.Render(fmt.Sprintf("[%s] ", entryType)))
+			}
+
+			switch {
+			case messageContent == "":
+				entryContent.WriteString(messageStyle.Render("(no content)"))
+			case entry.IsMessage():
+				entryContent.WriteString("\n")
+				entryContent.WriteString(tv.renderMessageBody(messageContent))
+				entryContent.WriteString("\n")
+			default:
+				entryContent.WriteString(messageStyle.Render(messageContent))
+			}
 
-			if entryType != "" {
-				content.WriteString(entryTypeStyle.Render(fmt.Sprintf("[%s] ", entryType)))
+			for _, attachment := range entry.Attachments() {
+				entryContent.WriteString(attachmentStyle.Render(fmt.Sprintf("📎 %s (%s, %s)", attachment.FileName, attachment.Size(), attachment.FileMimeType)))
+				entryContent.WriteString("\n")
 			}
 
-			if messageContent != "" {
-				content.WriteString(messageStyle.Render(messageContent))
+			// Internal notes are marked so they are never mistaken for customer-facing messages
+			if entry.IsInternal() {
+				content.WriteString(noteStyle.Render(strings.TrimRight(entryContent.String(), "\n")))
+				content.WriteString("\n")
 			} else {
-				content.WriteString(messageStyle.Render("(no content)"))
+				content.WriteString(entryContent.String())
 			}
 
 			// Add separator between messages (except for the last one)
-			if i > 0 {
+			if i < len(edges)-1 {
 				content.WriteString("\n")
 				content.WriteString(strings.Repeat("─", 30))
 				content.WriteString("\n\n")
@@ -610,25 +1208,50 @@ func (tv *ThreadsView) renderThreadContent() string {
 	return content.String()
 }
 
+// renderMessageBody renders a message as Markdown wrapped to the viewport, with its quoted
+// reply chain and signature collapsed unless expanded
+func (tv *ThreadsView) renderMessageBody(text string) string {
+	width := tv.viewport.Width - 2
+	body, quoted := splitQuoted(text)
+	if quoted == "" {
+		return tv.markdown.render(text, width)
+	}
+
+	if tv.expandQuoted {
+		return tv.markdown.render(body, width) + "\n" + tv.markdown.render(quoted, width)
+	}
+
+	hiddenStyle := lipgloss.NewStyle().
+		Foreground(theme.Muted).
+		Italic(true).
+		PaddingLeft(2)
+	hidden := len(strings.Split(strings.TrimSpace(quoted), "\n"))
+	return tv.markdown.render(body, width) + "\n" + hiddenStyle.Render(fmt.Sprintf("▸ %d quoted lines hidden (%s: expand)", hidden, tv.keys.keys(config.KeyExpandQuoted)))
+}
+
 // renderHelpText renders the help text for list view
 func (tv *ThreadsView) renderHelpText() string {
 	helpItems := []string{
-		"1: TODO only",
-		"2: SNOOZED only",
-		"3: All threads",
-		"r: Refresh",
-		"enter: View details",
-		"d: Dashboard",
+		tv.keys.help(config.KeyFilterTODO, "TODO only"),
+		tv.keys.help(config.KeyFilterSnoozed, "SNOOZED only"),
+		tv.keys.help(config.KeyFilterAll, "All threads"),
+		tv.keys.help(config.KeyQuery, "Query"),
+		tv.keys.help(config.KeySearch, "Search"),
+		tv.keys.help(config.KeyFilterLabels, "Filter by labels"),
+		tv.keys.help(config.KeyLabelThread, "Label thread"),
+		tv.keys.help(config.KeyRefresh, "Refresh"),
+		tv.keys.help(config.KeyOpen, "View details"),
+		tv.keys.help(config.KeyDashboard, "Dashboard"),
 	}
 
 	if tv.hasNextPage {
-		helpItems = append(helpItems, "n: Next page")
+		helpItems = append(helpItems, tv.keys.help(config.KeyNextPage, "Next page"))
 	}
 
-	helpItems = append(helpItems, "q: Quit")
+	helpItems = append(helpItems, tv.keys.help(config.KeyQuit, "Quit"))
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Muted).
 		Padding(1, 0, 0, 0)
 
 	return helpStyle.Render(strings.Join(helpItems, " • "))
@@ -676,15 +1299,20 @@ func (d threadDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 
 	// First line: Title and Status
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252")).
+		Foreground(theme.Text).
 		Bold(true)
 
-	statusColor := getStatusColor(thread.Status)
+	statusColor := theme.statusColor(thread.Status)
 	statusStyle := lipgloss.NewStyle().
 		Foreground(statusColor).
 		Bold(true)
 
-	str.WriteString(titleStyle.Render(fmt.Sprintf("%-50s", truncateString(thread.Title, 50))))
+	title := fmt.Sprintf("%-50s", truncateString(thread.Title, 50))
+	if i.Highlighted {
+		titleStyle = titleStyle.Foreground(theme.Highlight)
+		title = fmt.Sprintf("● %-48s", truncateString(thread.Title, 48))
+	}
+	str.WriteString(titleStyle.Render(title))
 	str.WriteString(" ")
 	str.WriteString(statusStyle.Render(thread.Status))
 
@@ -698,12 +1326,12 @@ func (d threadDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 	}
 
 	// Priority info
-	priorityColor := getPriorityColor(thread.Priority)
+	priorityColor := theme.priorityColor(thread.Priority)
 	priorityStyle := lipgloss.NewStyle().
 		Foreground(priorityColor)
 
 	infoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(theme.Muted)
 
 	str.WriteString("\n")
 	str.WriteString(infoStyle.Render(fmt.Sprintf("%-50s", truncateString(customerInfo, 50))))
@@ -712,9 +1340,7 @@ func (d threadDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 
 	// Apply selection styling
 	if index == m.Index() {
-		selectedStyle := lipgloss.NewStyle().
-			Background(lipgloss.Color("57")).
-			Foreground(lipgloss.Color("230")).
+		selectedStyle := theme.selectedStyle().
 			Padding(0, 1)
 		fmt.Fprint(w, selectedStyle.Render(str.String()))
 	} else {
@@ -724,40 +1350,6 @@ func (d threadDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 	}
 }
 
-// getStatusColor returns the color for a thread status
-func getStatusColor(status string) lipgloss.Color {
-	switch status {
-	case "TODO":
-		return lipgloss.Color("3") // Yellow
-	case "SNOOZED":
-		return lipgloss.Color("4") // Blue
-	case "OPEN":
-		return lipgloss.Color("2") // Green
-	case "PENDING":
-		return lipgloss.Color("3") // Yellow
-	case "DONE":
-		return lipgloss.Color("8") // Gray
-	default:
-		return lipgloss.Color("15") // White
-	}
-}
-
-// getPriorityColor returns the color for a thread priority
-func getPriorityColor(priority int) lipgloss.Color {
-	switch priority {
-	case 0:
-		return lipgloss.Color("9") // Bright red - Urgent
-	case 1:
-		return lipgloss.Color("1") // Red - High
-	case 2:
-		return lipgloss.Color("3") // Yellow - Medium
-	case 3:
-		return lipgloss.Color("2") // Green - Low
-	default:
-		return lipgloss.Color("15") // White
-	}
-}
-
 // getPriorityString converts a priority number to a readable string
 func getPriorityString(priority int) string {
 	switch priority {
@@ -769,6 +1361,8 @@ func getPriorityString(priority int) string {
 		return "Medium"
 	case 3:
 		return "Low"
+	case store.UnknownPriority:
+		return "-"
 	default:
 		return fmt.Sprintf("P%d", priority)
 	}
@@ -785,220 +1379,6 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-// getEntryContent extracts content from a timeline entry based on its type
-func (tv *ThreadsView) getEntryContent(entry *types.TimelineEntry) string {
-	if entry.Entry == nil {
-		return ""
-	}
-
-	switch e := entry.Entry.(type) {
-	case *types.EmailEntry:
-		return e.TextContent
-	case *types.ChatEntry:
-		return e.Text
-	case *types.NoteEntry:
-		if e.Markdown != "" {
-			return e.Markdown
-		}
-		return e.Text
-	case *types.SlackMessageEntry:
-		return e.Text
-	case *types.SlackReplyEntry:
-		return e.Text
-	case *types.CustomEntry:
-		return e.Title
-
-	case *types.ThreadStatusTransitionedEntry:
-		return fmt.Sprintf("Status changed from %s to %s", e.PreviousStatus, e.NextStatus)
-	case *types.ThreadPriorityChangedEntry:
-		return fmt.Sprintf("Priority changed from %s to %s", getPriorityString(e.PreviousPriority), getPriorityString(e.NextPriority))
-	case map[string]interface{}:
-		// Handle raw JSON entries that weren't specifically typed
-		// Check for aliased text fields first (from GraphQL fragments)
-		if slackText, ok := e["slackText"].(string); ok && slackText != "" {
-			return slackText
-		}
-		if chatText, ok := e["chatText"].(string); ok && chatText != "" {
-			return chatText
-		}
-		if noteText, ok := e["noteText"].(string); ok && noteText != "" {
-			return noteText
-		}
-
-		// Check for standard text fields
-		if text, ok := e["text"].(string); ok && text != "" {
-			return text
-		}
-		if textContent, ok := e["textContent"].(string); ok && textContent != "" {
-			return textContent
-		}
-		if markdown, ok := e["markdown"].(string); ok && markdown != "" {
-			return markdown
-		}
-		if title, ok := e["title"].(string); ok && title != "" {
-			return title
-		}
-
-		// Handle system event entries
-		if prevStatus, ok := e["previousStatus"].(string); ok {
-			if nextStatus, ok := e["nextStatus"].(string); ok {
-				return fmt.Sprintf("Status changed from %s to %s", prevStatus, nextStatus)
-			}
-		}
-		if prevPriority, ok := e["previousPriority"].(float64); ok {
-			if nextPriority, ok := e["nextPriority"].(float64); ok {
-				return fmt.Sprintf("Priority changed from %s to %s", getPriorityString(int(prevPriority)), getPriorityString(int(nextPriority)))
-			}
-		}
-
-		// Handle assignment changes in raw format
-		if prevAssignee, ok := e["previousAssignee"]; ok || e["nextAssignee"] != nil {
-			prevName := "None"
-			nextName := "None"
-
-			if prevAssignee != nil {
-				if assigneeMap, ok := prevAssignee.(map[string]interface{}); ok {
-					if user, ok := assigneeMap["user"].(map[string]interface{}); ok {
-						if fullName, ok := user["fullName"].(string); ok {
-							prevName = fullName
-						}
-					} else if team, ok := assigneeMap["team"].(map[string]interface{}); ok {
-						if teamName, ok := team["name"].(string); ok {
-							prevName = teamName + " (Team)"
-						}
-					}
-				}
-			}
-
-			if nextAssignee, ok := e["nextAssignee"]; ok && nextAssignee != nil {
-				if assigneeMap, ok := nextAssignee.(map[string]interface{}); ok {
-					if user, ok := assigneeMap["user"].(map[string]interface{}); ok {
-						if fullName, ok := user["fullName"].(string); ok {
-							nextName = fullName
-						}
-					} else if team, ok := assigneeMap["team"].(map[string]interface{}); ok {
-						if teamName, ok := team["name"].(string); ok {
-							nextName = teamName + " (Team)"
-						}
-					}
-				}
-			}
-
-			return fmt.Sprintf("Assignment changed from %s to %s", prevName, nextName)
-		}
-
-		// For any other entry type, try to extract some meaningful content
-		if len(e) > 0 {
-			// Look for common content fields in any order
-			contentFields := []string{"content", "message", "description", "body", "value"}
-			for _, field := range contentFields {
-				if value, ok := e[field].(string); ok && value != "" {
-					return value
-				}
-			}
-		}
-
-		return ""
-	default:
-		return ""
-	}
-}
-
-// getEntryType returns a readable type name for a timeline entry
-func (tv *ThreadsView) getEntryType(entry *types.TimelineEntry) string {
-	if entry.Entry == nil {
-		return "Event"
-	}
-
-	switch e := entry.Entry.(type) {
-	case *types.EmailEntry:
-		return "Email"
-	case *types.ChatEntry:
-		return "Chat"
-	case *types.NoteEntry:
-		return "Note"
-	case *types.SlackMessageEntry:
-		return "Slack"
-	case *types.SlackReplyEntry:
-		return "Slack Reply"
-	case *types.CustomEntry:
-		return "Custom"
-
-	case *types.ThreadStatusTransitionedEntry:
-		return "Status Change"
-	case *types.ThreadPriorityChangedEntry:
-		return "Priority Change"
-	case map[string]interface{}:
-		// Handle raw JSON entries by looking at discriminator fields
-		// Check for specific ID fields first
-		if _, ok := e["emailId"]; ok {
-			return "Email"
-		}
-		if _, ok := e["chatId"]; ok {
-			return "Chat"
-		}
-		if _, ok := e["noteId"]; ok {
-			return "Note"
-		}
-
-		// Check for aliased text fields from GraphQL fragments
-		if _, ok := e["slackText"]; ok {
-			return "Slack"
-		}
-		if _, ok := e["chatText"]; ok {
-			return "Chat"
-		}
-		if _, ok := e["noteText"]; ok {
-			return "Note"
-		}
-
-		// Check for Slack-specific fields
-		if _, ok := e["slackMessageLink"]; ok {
-			return "Slack"
-		}
-		if _, ok := e["slackWebMessageLink"]; ok {
-			return "Slack"
-		}
-
-		// Check for system event fields
-		if _, ok := e["previousStatus"]; ok {
-			return "Status Change"
-		}
-		if _, ok := e["previousPriority"]; ok {
-			return "Priority Change"
-		}
-		if _, ok := e["previousAssignee"]; ok {
-			return "Assignment"
-		}
-
-		// Check for custom entry fields
-		if _, ok := e["externalId"]; ok {
-			return "Custom"
-		}
-		if entryType, ok := e["type"].(string); ok && entryType != "" {
-			return "Custom"
-		}
-		if _, ok := e["title"]; ok {
-			return "Custom"
-		}
-
-		// Check for generic text content
-		if _, ok := e["text"]; ok {
-			return "Message"
-		}
-		if _, ok := e["textContent"]; ok {
-			return "Message"
-		}
-		if _, ok := e["markdown"]; ok {
-			return "Note"
-		}
-
-		return "Event"
-	default:
-		return "Event"
-	}
-}
-
 // max returns the maximum of two integers
 func max(a, b int) int {
 	if a > b {
@@ -0,0 +1,379 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"simple/client"
+	"simple/config"
+	"simple/query"
+	"simple/types"
+)
+
+// openThreadsQuery is the query used by widgets about open threads when none is configured
+const openThreadsQuery = "status:todo,snoozed"
+
+// defaultWidgetRows is the number of rows shown by list widgets when no limit is configured
+const defaultWidgetRows = 3
+
+// maxWidgetThreads bounds how many threads a widget looks at
+const maxWidgetThreads = 1000
+
+// Widget is a dashboard component computed from the threads matching a query
+type Widget struct {
+	id      int
+	title   string
+	value   string
+	lines   []string
+	series  []int
+	chart   string
+	exact   bool
+	loading bool
+	error   string
+	fetch   func(ctx context.Context) (widgetResult, error)
+}
+
+// widgetResult is what a widget shows once loaded
+type widgetResult struct {
+	value  string
+	lines  []string
+	series []int
+	// exact is false when the value is an estimate
+	exact bool
+}
+
+// widgetLoadedMsg is sent when a widget has loaded
+type widgetLoadedMsg struct {
+	id     int
+	result widgetResult
+	error  string
+}
+
+// newWidget creates a widget from its config. id must be unique on the dashboard.
+func newWidget(id int, cfg config.WidgetConfig, source client.DataSource, labelCache *client.LabelCache, stats *localStats) *Widget {
+	widget := &Widget{
+		id:      id,
+		title:   cfg.Title,
+		loading: true,
+	}
+
+	limit := cfg.Limit
+	if limit <= 0 {
+		limit = defaultWidgetRows
+	}
+	days := cfg.Days
+	if days <= 0 {
+		days = config.DefaultChartDays
+	}
+
+	switch cfg.Type {
+	case config.WidgetQuery:
+		widget.fetch = countWidget(source, labelCache, cfg.Query)
+		widget.defaultTitle("🔎 " + cfg.Query)
+	case config.WidgetLabel:
+		widget.fetch = countWidget(source, labelCache, joinQuery("label:"+strconv.Quote(cfg.Label), cfg.Query))
+		widget.defaultTitle("🏷️  " + cfg.Label)
+	case config.WidgetAssigneeLoad:
+		widget.fetch = groupWidget(source, labelCache, openQuery(cfg.Query), limit, func(thread *types.Thread) string {
+			if thread.AssignedTo == nil || thread.AssignedTo.FullName == "" {
+				return "Unassigned"
+			}
+			return thread.AssignedTo.FullName
+		})
+		widget.defaultTitle("👥 Load per assignee")
+	case config.WidgetTopCompanies:
+		widget.fetch = groupWidget(source, labelCache, openQuery(cfg.Query), limit, func(thread *types.Thread) string {
+			if thread.Customer == nil || thread.Customer.Company == nil || thread.Customer.Company.Name == "" {
+				return ""
+			}
+			return thread.Customer.Company.Name
+		})
+		widget.defaultTitle("🏢 Top companies")
+	case config.WidgetOldestOpen:
+		widget.fetch = oldestWidget(source, labelCache, openQuery(cfg.Query))
+		widget.defaultTitle("⏳ Oldest open thread")
+	case config.WidgetCreatedChart:
+		widget.fetch = chartWidget(source, stats, cfg.Type, days)
+		widget.chart = chartStyle(cfg.Chart, config.ChartBars)
+		widget.defaultTitle("📈 Created per day")
+	case config.WidgetDoneChart:
+		widget.fetch = chartWidget(source, stats, cfg.Type, days)
+		widget.chart = chartStyle(cfg.Chart, config.ChartBars)
+		widget.defaultTitle("✅ Done per day")
+	case config.WidgetBacklogChart:
+		widget.fetch = chartWidget(source, stats, cfg.Type, days)
+		widget.chart = chartStyle(cfg.Chart, config.ChartSparkline)
+		widget.defaultTitle("📚 Backlog")
+	default:
+		widget.loading = false
+		widget.error = fmt.Sprintf("unsupported widget type %q", cfg.Type)
+	}
+
+	return widget
+}
+
+// defaultTitle sets the title unless one was configured
+func (w *Widget) defaultTitle(title string) {
+	if w.title == "" {
+		w.title = title
+	}
+}
+
+// Title returns the widget title
+func (w *Widget) Title() string {
+	return w.title
+}
+
+// Value returns the widget value
+func (w *Widget) Value() string {
+	return w.value
+}
+
+// Lines returns the rows shown below the value
+func (w *Widget) Lines() []string {
+	return w.lines
+}
+
+// Chart renders the widget's series to fit width, or "" for widgets without one
+func (w *Widget) Chart(width int) string {
+	if len(w.series) == 0 {
+		return ""
+	}
+	if w.chart == config.ChartSparkline {
+		return sparkline(w.series, width)
+	}
+	return barChart(w.series, width, chartHeight)
+}
+
+// Estimated returns true if the value is an estimate
+func (w *Widget) Estimated() bool {
+	return !w.loading && w.error == "" && !w.exact
+}
+
+// Loading returns the loading state
+func (w *Widget) Loading() bool {
+	return w.loading
+}
+
+// Error returns the error message
+func (w *Widget) Error() string {
+	return w.error
+}
+
+// Update handles messages for the widget
+func (w *Widget) Update(msg tea.Msg) (DashboardComponent, tea.Cmd) {
+	switch msg := msg.(type) {
+	case widgetLoadedMsg:
+		if msg.id == w.id {
+			w.value = msg.result.value
+			w.lines = msg.result.lines
+			w.series = msg.result.series
+			w.exact = msg.result.exact
+			w.loading = false
+			w.error = msg.error
+		}
+	}
+	return w, nil
+}
+
+// loadCount loads the widget
+func (w *Widget) loadCount() tea.Cmd {
+	if w.fetch == nil {
+		return nil
+	}
+	// Reloads keep showing the previous value until the new one arrives
+	if w.value == "" && w.error == "" {
+		w.loading = true
+	}
+	id, fetch := w.id, w.fetch
+	return tea.Cmd(func() tea.Msg {
+		result, err := fetch(context.Background())
+		if err != nil {
+			return widgetLoadedMsg{id: id, error: err.Error()}
+		}
+		return widgetLoadedMsg{id: id, result: result}
+	})
+}
+
+// countWidget counts the threads matching a query
+func countWidget(source client.DataSource, labelCache *client.LabelCache, input string) func(context.Context) (widgetResult, error) {
+	return func(ctx context.Context) (widgetResult, error) {
+		count, exact, err := countThreads(ctx, source, labelCache, input)
+		if err != nil {
+			return widgetResult{}, err
+		}
+		return widgetResult{value: countString(count, !exact), exact: exact}, nil
+	}
+}
+
+// groupWidget counts the threads matching a query per group, showing the largest groups.
+// Threads for which group returns "" are left out.
+func groupWidget(source client.DataSource, labelCache *client.LabelCache, input string, limit int, group func(*types.Thread) string) func(context.Context) (widgetResult, error) {
+	return func(ctx context.Context) (widgetResult, error) {
+		threads, more, err := matchingThreads(ctx, source, labelCache, input)
+		if err != nil {
+			return widgetResult{}, err
+		}
+
+		counts := make(map[string]int)
+		for _, thread := range threads {
+			if name := group(thread); name != "" {
+				counts[name]++
+			}
+		}
+
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if counts[names[i]] != counts[names[j]] {
+				return counts[names[i]] > counts[names[j]]
+			}
+			return names[i] < names[j]
+		})
+
+		result := widgetResult{value: countString(len(threads), more), exact: !more}
+		for _, name := range names[:min(limit, len(names))] {
+			result.lines = append(result.lines, fmt.Sprintf("%-20s %4d", truncateString(name, 20), counts[name]))
+		}
+		if len(names) == 0 {
+			result.lines = []string{"No threads"}
+		}
+		return result, nil
+	}
+}
+
+// oldestWidget shows the age and title of the oldest thread matching a query.
+// Plain can't sort threads by age, so when more threads match than are scanned
+// the age is a lower bound and the thread only the oldest of those scanned.
+func oldestWidget(source client.DataSource, labelCache *client.LabelCache, input string) func(context.Context) (widgetResult, error) {
+	return func(ctx context.Context) (widgetResult, error) {
+		threads, more, err := matchingThreads(ctx, source, labelCache, input)
+		if err != nil {
+			return widgetResult{}, err
+		}
+
+		var oldest *types.Thread
+		var oldestAt time.Time
+		for _, thread := range threads {
+			if thread.CreatedAt == nil {
+				continue
+			}
+			createdAt, err := thread.CreatedAt.Time()
+			if err != nil {
+				continue
+			}
+			if oldest == nil || createdAt.Before(oldestAt) {
+				oldest, oldestAt = thread, createdAt
+			}
+		}
+
+		if oldest == nil {
+			return widgetResult{value: "-", lines: []string{"No open threads"}, exact: !more}, nil
+		}
+		result := widgetResult{
+			value: formatAge(time.Since(oldestAt)),
+			lines: []string{truncateString(oldest.Title, 30)},
+			exact: !more,
+		}
+		if more {
+			result.value += "+"
+			result.lines = append(result.lines, fmt.Sprintf("Oldest of the first %d", len(threads)))
+		}
+		return result, nil
+	}
+}
+
+// countThreads counts the threads matching a query. Queries Plain can filter on
+// exactly are counted with totalCount, others by checking every thread.
+func countThreads(ctx context.Context, source client.DataSource, labelCache *client.LabelCache, input string) (int, bool, error) {
+	compiled, err := query.ParseAndCompile(input, time.Now())
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid widget query: %w", err)
+	}
+
+	if compiled.ServerSide() {
+		if err := compiled.ResolveLabels(ctx, labelCache); err != nil {
+			return 0, false, err
+		}
+		return source.CountThreads(ctx, compiled.Filter)
+	}
+
+	threads, more, err := matchingThreads(ctx, source, labelCache, input)
+	if err != nil {
+		return 0, false, err
+	}
+	return len(threads), !more, nil
+}
+
+// matchingThreads returns the threads matching a query, and whether more were left unscanned
+func matchingThreads(ctx context.Context, source client.DataSource, labelCache *client.LabelCache, input string) ([]*types.Thread, bool, error) {
+	compiled, err := query.ParseAndCompile(input, time.Now())
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid widget query: %w", err)
+	}
+	if err := compiled.ResolveLabels(ctx, labelCache); err != nil {
+		return nil, false, err
+	}
+
+	connection, err := compiled.Fetch(ctx, source, maxWidgetThreads, "")
+	if err != nil {
+		return nil, false, err
+	}
+
+	threads := make([]*types.Thread, 0, len(connection.Edges))
+	for _, edge := range connection.Edges {
+		threads = append(threads, edge.Node)
+	}
+	return threads, connection.PageInfo.HasNextPage, nil
+}
+
+// openQuery returns the configured query, or one matching open threads
+func openQuery(input string) string {
+	if strings.TrimSpace(input) == "" {
+		return openThreadsQuery
+	}
+	return input
+}
+
+// chartStyle returns the configured chart style, or fallback when none is set
+func chartStyle(style, fallback string) string {
+	if style == "" {
+		return fallback
+	}
+	return style
+}
+
+// joinQuery combines two queries
+func joinQuery(a, b string) string {
+	return strings.TrimSpace(a + " " + b)
+}
+
+// countString formats a count, marking estimates that stopped at a limit
+func countString(count int, estimated bool) string {
+	if estimated {
+		return fmt.Sprintf("%d+", count)
+	}
+	return fmt.Sprintf("%d", count)
+}
+
+// formatAge formats a duration as days and hours, e.g. "3d 4h"
+func formatAge(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
@@ -0,0 +1,98 @@
+package watch
+
+import (
+	"errors"
+	"time"
+
+	"simple/types"
+)
+
+// Event types
+const (
+	EventThreadCreated       = "thread.created"        // a thread was created
+	EventThreadStatusChanged = "thread.status_changed" // a thread moved to another status
+	EventThreadUpdated       = "thread.updated"        // a thread not seen before changed, its previous status is unknown
+	EventEntryAdded          = "thread.entry_added"    // a message or event was added to a thread's timeline
+)
+
+// Event is a single change to a thread, written as one line of NDJSON
+type Event struct {
+	Type     string    `json:"type"`
+	At       time.Time `json:"at"`
+	ThreadID string    `json:"threadId"`
+	// Status is the thread's current status, PreviousStatus is set for status changes
+	Status         string        `json:"status,omitempty"`
+	PreviousStatus string        `json:"previousStatus,omitempty"`
+	Thread         *types.Thread `json:"thread,omitempty"`
+	Entry          *Entry        `json:"entry,omitempty"`
+}
+
+// Entry summarizes a timeline entry
+type Entry struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Actor     string `json:"actor"`
+	Text      string `json:"text"`
+	Internal  bool   `json:"internal"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// newEntry summarizes a timeline entry for an event
+func newEntry(entry *types.TimelineEntry) *Entry {
+	summary := &Entry{
+		ID:       entry.ID,
+		Kind:     entry.Kind(),
+		Actor:    entry.ActorName(),
+		Text:     entry.Text(),
+		Internal: entry.IsInternal(),
+	}
+	if entry.Timestamp != nil {
+		summary.Timestamp = entry.Timestamp.ISO8601
+	}
+	return summary
+}
+
+// threadEvents returns the created and status change events for a thread that
+// changed since the last poll. prev is the thread as last seen, if known.
+// Threads first seen when they change are reported as updated, as it isn't
+// known whether their status changed.
+func threadEvents(thread *types.Thread, prev ThreadState, known bool, since, now time.Time) []Event {
+	at := now
+	if updatedAt, err := parseTimestamp(thread.UpdatedAt); err == nil {
+		at = updatedAt
+	}
+
+	if !known {
+		if createdAt, err := parseTimestamp(thread.CreatedAt); err == nil && createdAt.After(since) {
+			return []Event{{Type: EventThreadCreated, At: createdAt, ThreadID: thread.ID, Status: thread.Status, Thread: thread}}
+		}
+		if !at.After(since) {
+			// Changed before since, only read again in the poll overlap
+			return nil
+		}
+		return []Event{{Type: EventThreadUpdated, At: at, ThreadID: thread.ID, Status: thread.Status, Thread: thread}}
+	}
+
+	if prev.Status != "" && prev.Status != thread.Status {
+		return []Event{{
+			Type:           EventThreadStatusChanged,
+			At:             at,
+			ThreadID:       thread.ID,
+			Status:         thread.Status,
+			PreviousStatus: prev.Status,
+			Thread:         thread,
+		}}
+	}
+	return nil
+}
+
+// errMissingTimestamp is returned when Plain left out a timestamp
+var errMissingTimestamp = errors.New("missing timestamp")
+
+// parseTimestamp parses an optional Plain timestamp
+func parseTimestamp(dt *types.DateTime) (time.Time, error) {
+	if dt == nil {
+		return time.Time{}, errMissingTimestamp
+	}
+	return dt.Time()
+}
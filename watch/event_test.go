@@ -0,0 +1,75 @@
+package watch
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"simple/types"
+)
+
+func TestThreadEvents(t *testing.T) {
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := since.Add(time.Minute)
+	before := &types.DateTime{ISO8601: "2024-05-01T11:00:00Z"}
+	after := &types.DateTime{ISO8601: "2024-05-01T12:00:30Z"}
+
+	tests := []struct {
+		name   string
+		thread *types.Thread
+		prev   ThreadState
+		known  bool
+		want   []string
+	}{
+		{"created", &types.Thread{ID: "t1", Status: "TODO", CreatedAt: after}, ThreadState{}, false, []string{EventThreadCreated}},
+		{"first seen updated before since", &types.Thread{ID: "t2", Status: "DONE", CreatedAt: before, UpdatedAt: before}, ThreadState{}, false, nil},
+		{"first seen updated after since", &types.Thread{ID: "t5", Status: "DONE", CreatedAt: before, UpdatedAt: after}, ThreadState{}, false, []string{EventThreadUpdated}},
+		{"status changed", &types.Thread{ID: "t3", Status: "DONE", CreatedAt: before, UpdatedAt: after}, ThreadState{Status: "TODO"}, true, []string{EventThreadStatusChanged}},
+		{"same status", &types.Thread{ID: "t4", Status: "TODO", CreatedAt: before}, ThreadState{Status: "TODO"}, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := threadEvents(tt.thread, tt.prev, tt.known, since, now)
+			if len(events) != len(tt.want) {
+				t.Fatalf("threadEvents() returned %d events, want %d", len(events), len(tt.want))
+			}
+			for i, event := range events {
+				if event.Type != tt.want[i] || event.ThreadID != tt.thread.ID {
+					t.Errorf("event %d = %s %s, want %s %s", i, event.Type, event.ThreadID, tt.want[i], tt.thread.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestStateSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watch.json")
+
+	missing, err := LoadState(path)
+	if err != nil || missing != nil {
+		t.Fatalf("LoadState() without a file = %v, %v, want nil, nil", missing, err)
+	}
+
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	state := NewState(since)
+	state.Threads["recent"] = ThreadState{Status: "TODO", UpdatedAt: "2024-04-30T12:00:00Z", EntryCursor: "c1"}
+	state.Threads["stale"] = ThreadState{Status: "DONE", UpdatedAt: "2024-01-01T12:00:00Z"}
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if !loaded.Since.Equal(since) {
+		t.Errorf("Since = %v, want %v", loaded.Since, since)
+	}
+	if got := loaded.Threads["recent"]; got.EntryCursor != "c1" || got.Status != "TODO" {
+		t.Errorf("recent thread = %+v", got)
+	}
+	if _, ok := loaded.Threads["stale"]; ok {
+		t.Errorf("threads unchanged for over %s should be pruned", stateRetention)
+	}
+}
@@ -0,0 +1,91 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stateRetention is how long threads without changes are remembered in the state
+const stateRetention = 30 * 24 * time.Hour
+
+// State is what the watcher remembers between polls and restarts
+type State struct {
+	// Since is when the last complete poll started, changes after it are still to be reported
+	Since   time.Time              `json:"since"`
+	Threads map[string]ThreadState `json:"threads"`
+}
+
+// ThreadState is a thread as it was last seen
+type ThreadState struct {
+	Status    string `json:"status"`
+	UpdatedAt string `json:"updatedAt"`
+	// EntryCursor points after the last reported timeline entry
+	EntryCursor string `json:"entryCursor,omitempty"`
+}
+
+// NewState returns an empty state reporting changes after since
+func NewState(since time.Time) *State {
+	return &State{Since: since, Threads: make(map[string]ThreadState)}
+}
+
+// LoadState reads a state file, returning nil without an error when there is none yet
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse watch state %s: %w", path, err)
+	}
+	if state.Threads == nil {
+		state.Threads = make(map[string]ThreadState)
+	}
+	return &state, nil
+}
+
+// Save writes the state to path, replacing the previous file atomically
+func (s *State) Save(path string) error {
+	s.prune()
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode watch state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+	return nil
+}
+
+// prune forgets threads that haven't changed for stateRetention
+func (s *State) prune() {
+	cutoff := s.Since.Add(-stateRetention)
+	for id, thread := range s.Threads {
+		if updatedAt, err := time.Parse(time.RFC3339, thread.UpdatedAt); err == nil && updatedAt.Before(cutoff) {
+			delete(s.Threads, id)
+		}
+	}
+}
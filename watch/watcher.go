@@ -0,0 +1,223 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"simple/client"
+	"simple/query"
+	"simple/types"
+)
+
+// pollPageSize is the page size used when fetching changed threads
+const pollPageSize = 50
+
+// pollOverlap re-reads a short window before the last poll, so changes Plain
+// records slightly late aren't missed. Threads seen with the same updatedAt are skipped.
+const pollOverlap = time.Minute
+
+// Watcher polls Plain for thread changes and reports them as events
+type Watcher struct {
+	client *client.PlainClient
+	// query limits which threads are reported, nil reports every thread
+	query    *query.Compiled
+	interval time.Duration
+	state    *State
+	// statePath is where the state is saved after each poll, "" to keep it in memory
+	statePath string
+	emit      func(Event) error
+	// errorf reports errors that don't stop the watcher, such as a failed poll
+	errorf func(format string, args ...interface{})
+}
+
+// NewWatcher creates a watcher. compiled must have its labels resolved.
+func NewWatcher(plainClient *client.PlainClient, compiled *query.Compiled, interval time.Duration, state *State, statePath string, emit func(Event) error, errorf func(format string, args ...interface{})) *Watcher {
+	return &Watcher{
+		client:    plainClient,
+		query:     compiled,
+		interval:  interval,
+		state:     state,
+		statePath: statePath,
+		emit:      emit,
+		errorf:    errorf,
+	}
+}
+
+// Run polls until ctx is cancelled, which is not an error. Failed polls are
+// reported and retried on the next interval; failing to emit an event stops the watcher.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		err := w.poll(ctx)
+		if saveErr := w.save(); saveErr != nil {
+			return saveErr
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		var emitErr *emitError
+		if errors.As(err, &emitErr) {
+			return emitErr.err
+		}
+		if err != nil {
+			w.errorf("poll failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// emitError wraps errors from the emit function, which stop the watcher
+type emitError struct {
+	err error
+}
+
+func (e *emitError) Error() string {
+	return e.err.Error()
+}
+
+// save writes the state file, if there is one
+func (w *Watcher) save() error {
+	if w.statePath == "" {
+		return nil
+	}
+	return w.state.Save(w.statePath)
+}
+
+// poll reports the changes since the last complete poll
+func (w *Watcher) poll(ctx context.Context) error {
+	checkedAt := time.Now()
+	since := w.state.Since
+
+	// Only the update time is filtered on by Plain, so threads that stop
+	// matching the query are still seen and their status changes reported
+	filter := client.ThreadFilter{UpdatedAfter: since.Add(-pollOverlap).UTC().Format(time.RFC3339)}
+
+	cursor := ""
+	for {
+		threads, err := w.client.GetThreadsByFilter(ctx, filter, pollPageSize, cursor)
+		if err != nil {
+			return err
+		}
+		if threads == nil {
+			break
+		}
+
+		for _, edge := range threads.Edges {
+			if edge.Node == nil {
+				continue
+			}
+			if err := w.thread(ctx, edge.Node, since, checkedAt); err != nil {
+				return err
+			}
+		}
+
+		if threads.PageInfo == nil || !threads.PageInfo.HasNextPage {
+			break
+		}
+		cursor = threads.PageInfo.EndCursor
+	}
+
+	w.state.Since = checkedAt
+	return nil
+}
+
+// thread reports the changes to a single thread and remembers it
+func (w *Watcher) thread(ctx context.Context, thread *types.Thread, since, now time.Time) error {
+	prev, known := w.state.Threads[thread.ID]
+	updatedAt := ""
+	if thread.UpdatedAt != nil {
+		updatedAt = thread.UpdatedAt.ISO8601
+	}
+	if known && prev.UpdatedAt == updatedAt {
+		return nil
+	}
+	if !known && w.query != nil && !w.query.Match(thread) {
+		return nil
+	}
+
+	events := threadEvents(thread, prev, known, since, now)
+
+	entries, cursor, err := w.newEntries(ctx, thread.ID, prev.EntryCursor, since)
+	if err != nil {
+		return err
+	}
+	events = append(events, entries...)
+
+	for _, event := range events {
+		if err := w.emit(event); err != nil {
+			return &emitError{err: fmt.Errorf("failed to write event: %w", err)}
+		}
+	}
+
+	if cursor == "" {
+		cursor = prev.EntryCursor
+	}
+	w.state.Threads[thread.ID] = ThreadState{Status: thread.Status, UpdatedAt: updatedAt, EntryCursor: cursor}
+	return nil
+}
+
+// newEntries returns events for the timeline entries after cursor, or for the
+// latest entries after since when the thread's timeline hasn't been read yet.
+// It returns the cursor after the last entry.
+func (w *Watcher) newEntries(ctx context.Context, threadID, cursor string, since time.Time) ([]Event, string, error) {
+	var events []Event
+	add := func(edge *types.TimelineEntryEdge) {
+		if edge == nil || edge.Node == nil {
+			return
+		}
+		cursor = edge.Cursor
+		at, err := parseTimestamp(edge.Node.Timestamp)
+		if err != nil {
+			at = time.Now()
+		}
+		events = append(events, Event{Type: EventEntryAdded, At: at, ThreadID: threadID, Entry: newEntry(edge.Node)})
+	}
+
+	if cursor == "" {
+		page, err := w.client.GetThreadTimeline(ctx, threadID, client.TimelinePageSize, "")
+		if err != nil {
+			return nil, "", err
+		}
+		if page == nil {
+			return nil, "", nil
+		}
+		for _, edge := range page.Edges {
+			if edge == nil || edge.Node == nil {
+				continue
+			}
+			if at, err := parseTimestamp(edge.Node.Timestamp); err == nil && !at.After(since) {
+				// Already there before the watch started, only move the cursor past it
+				cursor = edge.Cursor
+				continue
+			}
+			add(edge)
+		}
+		return events, cursor, nil
+	}
+
+	for {
+		page, err := w.client.GetThreadTimelineAfter(ctx, threadID, client.TimelinePageSize, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if page == nil {
+			return events, cursor, nil
+		}
+		for _, edge := range page.Edges {
+			add(edge)
+		}
+		if page.PageInfo == nil || !page.PageInfo.HasNextPage {
+			return events, cursor, nil
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+}
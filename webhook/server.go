@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"simple/types"
+)
+
+// maxBodySize bounds the size of a webhook delivery
+const maxBodySize = 5 << 20
+
+// ThreadWriter stores the threads carried by events
+type ThreadWriter interface {
+	SaveThread(thread *types.Thread) error
+}
+
+// Handler receives Plain webhook deliveries
+type Handler struct {
+	secret string
+	// store is optional, events are only passed to the sinks without one
+	store ThreadWriter
+	sinks []Sink
+	// errorf reports errors that don't fail the delivery, such as a failing sink
+	errorf func(format string, args ...interface{})
+	// sending tracks the events still being passed to the sinks
+	sending sync.WaitGroup
+}
+
+// NewHandler creates a handler verifying deliveries against the signing secret
+func NewHandler(secret string, store ThreadWriter, sinks []Sink, errorf func(format string, args ...interface{})) *Handler {
+	return &Handler{
+		secret: secret,
+		store:  store,
+		sinks:  sinks,
+		errorf: errorf,
+	}
+}
+
+// ServeHTTP verifies, stores and fans out a delivery. Deliveries that fail to be
+// stored get a 500 so Plain retries them. Sinks run after the delivery is
+// acknowledged, so slow ones don't make Plain time out, and their errors are only reported.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if !Verify(h.secret, body, r.Header.Get(SignatureHeader)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := Decode(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.storeThread(event); err != nil {
+		h.errorf("%v", err)
+		http.Error(w, "failed to store event", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	h.sending.Add(1)
+	go h.send(event, body)
+}
+
+// send passes an event to every sink
+func (h *Handler) send(event *types.Event, raw []byte) {
+	defer h.sending.Done()
+	for _, sink := range h.sinks {
+		if err := sink.Send(context.Background(), event, raw); err != nil {
+			h.errorf("event %s: %v", event.ID, err)
+		}
+	}
+}
+
+// Wait waits for the sinks to finish with the events received so far
+func (h *Handler) Wait() {
+	h.sending.Wait()
+}
+
+// storeThread writes the event's thread to the store, if it has one
+func (h *Handler) storeThread(event *types.Event) error {
+	if h.store == nil {
+		return nil
+	}
+	thread, err := event.Thread()
+	if err != nil {
+		return err
+	}
+	if thread == nil || thread.ID == "" {
+		return nil
+	}
+	return h.store.SaveThread(thread)
+}
+
+// Decode decodes a webhook delivery into an event
+func Decode(body []byte) (*types.Event, error) {
+	var event types.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	if event.Type == "" {
+		return nil, fmt.Errorf("failed to decode event: missing type")
+	}
+	return &event, nil
+}
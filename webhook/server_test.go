@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"simple/types"
+)
+
+const testSecret = "whsec_test_secret"
+
+// Signatures of the fixtures in testdata with testSecret
+var fixtureSignatures = map[string]string{
+	"thread_created.json":             "5f9e4e263aef462bcf1e837478bcea3cb3f11a3b85266b01a2a9f178ec6a1b28",
+	"thread_status_transitioned.json": "5def0b7c83999eda52161ee7d80ce1c988c1403c6d7ec6d2efc7c9532aeec1fc",
+}
+
+// fakeStore records saved threads
+type fakeStore struct {
+	threads map[string]*types.Thread
+	err     error
+}
+
+func (s *fakeStore) SaveThread(thread *types.Thread) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.threads[thread.ID] = thread
+	return nil
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestSign(t *testing.T) {
+	for name, signature := range fixtureSignatures {
+		if got := Sign(testSecret, readFixture(t, name)); got != signature {
+			t.Errorf("Sign(%s) = %s, want %s", name, got, signature)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	created := readFixture(t, "thread_created.json")
+	transitioned := readFixture(t, "thread_status_transitioned.json")
+	malformed := []byte(`{"type": `)
+
+	tests := []struct {
+		name         string
+		method       string
+		body         []byte
+		signature    string
+		storeErr     error
+		wantStatus   int
+		storedStatus string
+		wantEvents   int
+	}{
+		{"thread created", http.MethodPost, created, fixtureSignatures["thread_created.json"], nil, http.StatusOK, "TODO", 1},
+		{"status transitioned", http.MethodPost, transitioned, fixtureSignatures["thread_status_transitioned.json"], nil, http.StatusOK, "DONE", 1},
+		{"uppercase signature", http.MethodPost, created, strings.ToUpper(fixtureSignatures["thread_created.json"]), nil, http.StatusOK, "TODO", 1},
+		{"signature of another body", http.MethodPost, created, fixtureSignatures["thread_status_transitioned.json"], nil, http.StatusUnauthorized, "", 0},
+		{"tampered body", http.MethodPost, bytes.Replace(created, []byte(`"TODO"`), []byte(`"DONE"`), 1), fixtureSignatures["thread_created.json"], nil, http.StatusUnauthorized, "", 0},
+		{"missing signature", http.MethodPost, created, "", nil, http.StatusUnauthorized, "", 0},
+		{"malformed payload", http.MethodPost, malformed, Sign(testSecret, malformed), nil, http.StatusBadRequest, "", 0},
+		{"store failure", http.MethodPost, created, fixtureSignatures["thread_created.json"], errors.New("disk full"), http.StatusInternalServerError, "", 0},
+		{"wrong method", http.MethodGet, nil, "", nil, http.StatusMethodNotAllowed, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{threads: make(map[string]*types.Thread), err: tt.storeErr}
+			var out bytes.Buffer
+			handler := NewHandler(testSecret, store, []Sink{NewStdoutSink(&out)}, func(string, ...interface{}) {})
+
+			req := httptest.NewRequest(tt.method, "/webhooks/plain", bytes.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set(SignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			handler.Wait()
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if out.Len() == 0 {
+				lines = nil
+			}
+			if len(lines) != tt.wantEvents {
+				t.Fatalf("sink got %d lines, want %d: %q", len(lines), tt.wantEvents, out.String())
+			}
+			if tt.wantEvents > 0 {
+				event, err := Decode([]byte(lines[0]))
+				if err != nil {
+					t.Fatalf("sink line is not an event: %v", err)
+				}
+				if event.ID == "" || event.WorkspaceID == "" {
+					t.Errorf("sink event = %+v, want id and workspace", event)
+				}
+			}
+
+			if tt.storedStatus != "" {
+				thread := store.threads["th_01HXY4KQ7VQ2E9R5B3C8D6F1GA"]
+				if thread == nil {
+					t.Fatal("thread was not stored")
+				}
+				if thread.Status != tt.storedStatus || thread.Title != "Cannot log in after password reset" {
+					t.Errorf("stored thread = %s %q, want %s", thread.Status, thread.Title, tt.storedStatus)
+				}
+			} else if len(store.threads) > 0 {
+				t.Errorf("rejected delivery stored %d threads", len(store.threads))
+			}
+		})
+	}
+}
+
+func TestEventPreviousThread(t *testing.T) {
+	event, err := Decode(readFixture(t, "thread_status_transitioned.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := event.PreviousThread()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous == nil || previous.Status != "TODO" {
+		t.Errorf("PreviousThread() = %+v, want status TODO", previous)
+	}
+}
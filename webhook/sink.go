@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"simple/config"
+	"simple/types"
+)
+
+// execTimeout bounds how long an exec sink may run for one event
+const execTimeout = 30 * time.Second
+
+// Sink receives verified webhook events
+type Sink interface {
+	// Send delivers an event. raw is the event as Plain sent it.
+	Send(ctx context.Context, event *types.Event, raw []byte) error
+}
+
+// NewSinks creates the sinks in the webhooks config. Stdout sinks write to stdout.
+func NewSinks(cfg []config.SinkConfig, stdout io.Writer) []Sink {
+	sinks := make([]Sink, 0, len(cfg))
+	for _, sink := range cfg {
+		var s Sink
+		switch sink.Type {
+		case config.SinkExec:
+			s = &ExecSink{Command: sink.Command}
+		default:
+			s = NewStdoutSink(stdout)
+		}
+		if len(sink.Events) > 0 {
+			s = &filteredSink{sink: s, events: sink.Events}
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks
+}
+
+// StdoutSink writes each event as a line of JSON
+type StdoutSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutSink creates a sink writing NDJSON to w
+func NewStdoutSink(w io.Writer) *StdoutSink {
+	return &StdoutSink{w: w}
+}
+
+// Send writes the event on a single line
+func (s *StdoutSink) Send(_ context.Context, _ *types.Event, raw []byte) error {
+	var line bytes.Buffer
+	// Plain may send indented JSON, NDJSON needs one event per line
+	if err := json.Compact(&line, raw); err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	line.WriteByte('\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(line.Bytes()); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
+
+// ExecSink runs a shell command for each event with the event JSON on stdin
+type ExecSink struct {
+	Command string
+}
+
+// Send runs the command. PLAIN_EVENT_TYPE and PLAIN_EVENT_ID are set in its environment.
+func (s *ExecSink) Send(ctx context.Context, event *types.Event, raw []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.Command)
+	cmd.Stdin = bytes.NewReader(raw)
+	cmd.Env = append(os.Environ(),
+		"PLAIN_EVENT_TYPE="+event.Type,
+		"PLAIN_EVENT_ID="+event.ID,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("failed to run %q: %w: %s", s.Command, err, message)
+		}
+		return fmt.Errorf("failed to run %q: %w", s.Command, err)
+	}
+	return nil
+}
+
+// filteredSink only passes on events of the given types
+type filteredSink struct {
+	sink   Sink
+	events []string
+}
+
+// Send passes the event on if its type is one of the sink's events
+func (s *filteredSink) Send(ctx context.Context, event *types.Event, raw []byte) error {
+	for _, eventType := range s.events {
+		if eventType == event.Type {
+			return s.sink.Send(ctx, event, raw)
+		}
+	}
+	return nil
+}